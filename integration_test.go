@@ -11,9 +11,15 @@ import (
 	"financefetcher/internal/coordinator"
 	"financefetcher/internal/etherscan"
 	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
 	"financefetcher/internal/rentcast"
 )
 
+// Run this package's tests without real API rate limits
+func init() {
+	ratelimit.SetTestMode(true)
+}
+
 // TestIntegration_AllFetchers tests the full flow with all fetchers using mock HTTP servers
 func TestIntegration_AllFetchers(t *testing.T) {
 	// Create mock Etherscan server
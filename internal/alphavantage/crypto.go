@@ -0,0 +1,41 @@
+package alphavantage
+
+import (
+	"context"
+	"fmt"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+
+	"resty.dev/v3"
+)
+
+// CryptoFetcher fetches the price of a digital currency (e.g. BTC) in a fiat market (e.g. USD)
+type CryptoFetcher struct {
+	apiKey string
+	symbol string
+	market string
+	client *resty.Client
+}
+
+// NewCryptoFetcher creates a new crypto price fetcher
+func NewCryptoFetcher(apiKey, symbol, market, baseURL string) *CryptoFetcher {
+	client := fetcher.NewHTTPClient(baseURL, fetcher.WithRateLimitedRetries(ratelimit.APIAlphaVantage))
+
+	return &CryptoFetcher{
+		apiKey: apiKey,
+		symbol: symbol,
+		market: market,
+		client: client,
+	}
+}
+
+// Fetch retrieves the current price of one unit of the symbol in the market currency
+func (f *CryptoFetcher) Fetch(ctx context.Context) (float64, error) {
+	return fetchExchangeRate(ctx, f.client, f.apiKey, f.symbol, f.market)
+}
+
+// Key returns the Redis key for this fetcher
+func (f *CryptoFetcher) Key() string {
+	return fmt.Sprintf("fetcher:alphavantage:crypto:%s-%s", f.symbol, f.market)
+}
@@ -0,0 +1,132 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestCryptoFetcher_Key(t *testing.T) {
+	tests := []struct {
+		symbol      string
+		market      string
+		expectedKey string
+	}{
+		{"BTC", "USD", "fetcher:alphavantage:crypto:BTC-USD"},
+		{"ETH", "EUR", "fetcher:alphavantage:crypto:ETH-EUR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol+"-"+tt.market, func(t *testing.T) {
+			fetcher := NewCryptoFetcher("test_key", tt.symbol, tt.market, "http://localhost")
+			if got := fetcher.Key(); got != tt.expectedKey {
+				t.Errorf("Key() = %q, want %q", got, tt.expectedKey)
+			}
+		})
+	}
+}
+
+func TestCryptoFetcher_Fetch_Success(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("function") != "CURRENCY_EXCHANGE_RATE" {
+			t.Errorf("function = %q, want CURRENCY_EXCHANGE_RATE", query.Get("function"))
+		}
+		if query.Get("from_currency") != "BTC" {
+			t.Errorf("from_currency = %q, want BTC", query.Get("from_currency"))
+		}
+		if query.Get("to_currency") != "USD" {
+			t.Errorf("to_currency = %q, want USD", query.Get("to_currency"))
+		}
+		if query.Get("apikey") != "test_key" {
+			t.Errorf("apikey = %q, want test_key", query.Get("apikey"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"Realtime Currency Exchange Rate": {
+				"1. From_Currency Code": "BTC",
+				"2. From_Currency Name": "Bitcoin",
+				"3. To_Currency Code": "USD",
+				"4. To_Currency Name": "United States Dollar",
+				"5. Exchange Rate": "67012.34000000",
+				"6. Last Refreshed": "2024-05-01 12:00:01",
+				"7. Time Zone": "UTC",
+				"8. Bid Price": "67012.33000000",
+				"9. Ask Price": "67012.35000000"
+			}
+		}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewCryptoFetcher("test_key", "BTC", "USD", server.URL)
+	ctx := context.Background()
+
+	price, err := fetcher.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	expectedPrice := 67012.34
+	if price != expectedPrice {
+		t.Errorf("Fetch() = %v, want %v", price, expectedPrice)
+	}
+}
+
+func TestCryptoFetcher_Fetch_MissingRate(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewCryptoFetcher("test_key", "BTC", "USD", server.URL)
+	ctx := context.Background()
+
+	_, err := fetcher.Fetch(ctx)
+	if err == nil {
+		t.Fatal("Fetch() expected error for missing rate, got nil")
+	}
+
+	expectedErrMsg := "validation error: exchange rate not found in response for BTC-USD"
+	if err.Error() != expectedErrMsg {
+		t.Errorf("Fetch() error = %q, want %q", err.Error(), expectedErrMsg)
+	}
+}
+
+func TestCryptoFetcher_Fetch_InvalidSymbol(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Error Message": "Invalid API call."}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewCryptoFetcher("test_key", "NOTACOIN", "USD", server.URL)
+	ctx := context.Background()
+
+	_, err := fetcher.Fetch(ctx)
+	if err == nil {
+		t.Fatal("Fetch() expected error for invalid symbol, got nil")
+	}
+
+	var fetchErr *fetcherpkg.FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("Fetch() error = %v, want *FetchError", err)
+	}
+	if fetchErr.Type != fetcherpkg.ErrorTypeClient {
+		t.Errorf("error type = %q, want %q", fetchErr.Type, fetcherpkg.ErrorTypeClient)
+	}
+}
@@ -0,0 +1,87 @@
+package alphavantage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+
+	"resty.dev/v3"
+)
+
+// ExchangeRateResponse represents the AlphaVantage CURRENCY_EXCHANGE_RATE response.
+// The same endpoint quotes both digital (BTC) and physical (EUR) currencies.
+type ExchangeRateResponse struct {
+	Rate struct {
+		FromCode      string `json:"1. From_Currency Code"`
+		FromName      string `json:"2. From_Currency Name"`
+		ToCode        string `json:"3. To_Currency Code"`
+		ToName        string `json:"4. To_Currency Name"`
+		ExchangeRate  string `json:"5. Exchange Rate"`
+		LastRefreshed string `json:"6. Last Refreshed"`
+		TimeZone      string `json:"7. Time Zone"`
+		BidPrice      string `json:"8. Bid Price"`
+		AskPrice      string `json:"9. Ask Price"`
+	} `json:"Realtime Currency Exchange Rate"`
+
+	// ErrorMessage is set (with HTTP 200) when the request is invalid, e.g. an unknown currency
+	ErrorMessage string `json:"Error Message"`
+	// Note is set (with HTTP 200) when the API key has exceeded its call frequency
+	Note string `json:"Note"`
+	// Information is set (with HTTP 200) when the daily quota is spent or the key is rejected
+	Information string `json:"Information"`
+}
+
+// fetchExchangeRate retrieves the current from→to exchange rate, sharing the
+// AlphaVantage rate limit with the stock fetchers
+func fetchExchangeRate(ctx context.Context, client *resty.Client, apiKey, from, to string) (float64, error) {
+	pair := from + "-" + to
+
+	limiter := ratelimit.GetLimiter()
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIAlphaVantage)
+	if err != nil {
+		return 0, fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIAlphaVantage, "pair", pair, "wait_duration", waited)
+
+	slog.Debug("fetching exchange rate from AlphaVantage", "run_id", fetcher.RunIDFromContext(ctx), "pair", pair)
+
+	var result ExchangeRateResponse
+
+	resp, err := client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"apikey":        apiKey,
+			"function":      "CURRENCY_EXCHANGE_RATE",
+			"from_currency": from,
+			"to_currency":   to,
+		}).
+		SetResult(&result).
+		Get("")
+
+	if err != nil {
+		return 0, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch exchange rate for " + pair)
+	}
+
+	if !resp.IsSuccess() {
+		return 0, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch exchange rate for " + pair)
+	}
+
+	if bodyErr := bodyError(pair, result.ErrorMessage, result.Note, result.Information); bodyErr != nil {
+		return 0, bodyErr
+	}
+
+	if result.Rate.ExchangeRate == "" {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("exchange rate not found in response for %s", pair))
+	}
+
+	rate, err := strconv.ParseFloat(result.Rate.ExchangeRate, 64)
+	if err != nil {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("failed to parse exchange rate for %s: %v", pair, err))
+	}
+
+	return rate, nil
+}
@@ -0,0 +1,15 @@
+package alphavantage
+
+import "financefetcher/internal/fetcher"
+
+func init() {
+	fetcher.Register("alphavantage", newFromParams)
+}
+
+// newFromParams builds a StockFetcher from the "api_key", "ticker", and "base_url" parameters
+func newFromParams(params fetcher.Params) (fetcher.Fetcher, error) {
+	if err := params.Require("api_key", "ticker", "base_url"); err != nil {
+		return nil, err
+	}
+	return NewStockFetcher(params["api_key"], params["ticker"], params["base_url"]), nil
+}
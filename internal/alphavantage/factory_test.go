@@ -0,0 +1,25 @@
+package alphavantage
+
+import (
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestFactory(t *testing.T) {
+	f, err := fetcherpkg.New("alphavantage", fetcherpkg.Params{
+		"api_key":  "test_key",
+		"ticker":   "AAPL",
+		"base_url": "http://localhost",
+	})
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	if got := f.Key(); got != "fetcher:alphavantage:AAPL" {
+		t.Errorf("Key() = %q, want %q", got, "fetcher:alphavantage:AAPL")
+	}
+
+	if _, err := fetcherpkg.New("alphavantage", fetcherpkg.Params{"api_key": "test_key"}); err == nil {
+		t.Error("New() expected error for missing ticker and base_url, got nil")
+	}
+}
@@ -0,0 +1,41 @@
+package alphavantage
+
+import (
+	"context"
+	"fmt"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+
+	"resty.dev/v3"
+)
+
+// ForexFetcher fetches the spot exchange rate for a currency pair
+type ForexFetcher struct {
+	apiKey string
+	from   string
+	to     string
+	client *resty.Client
+}
+
+// NewForexFetcher creates a new FX rate fetcher for the from→to pair
+func NewForexFetcher(apiKey, from, to, baseURL string) *ForexFetcher {
+	client := fetcher.NewHTTPClient(baseURL, fetcher.WithRateLimitedRetries(ratelimit.APIAlphaVantage))
+
+	return &ForexFetcher{
+		apiKey: apiKey,
+		from:   from,
+		to:     to,
+		client: client,
+	}
+}
+
+// Fetch retrieves how many units of the "to" currency one unit of the "from" currency buys
+func (f *ForexFetcher) Fetch(ctx context.Context) (float64, error) {
+	return fetchExchangeRate(ctx, f.client, f.apiKey, f.from, f.to)
+}
+
+// Key returns the Redis key for this fetcher
+func (f *ForexFetcher) Key() string {
+	return fmt.Sprintf("fetcher:alphavantage:fx:%s-%s", f.from, f.to)
+}
@@ -0,0 +1,135 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestForexFetcher_Key(t *testing.T) {
+	fetcher := NewForexFetcher("test_key", "EUR", "USD", "http://localhost")
+
+	expectedKey := "fetcher:alphavantage:fx:EUR-USD"
+	if got := fetcher.Key(); got != expectedKey {
+		t.Errorf("Key() = %q, want %q", got, expectedKey)
+	}
+}
+
+func TestForexFetcher_Fetch_Success(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("from_currency") != "EUR" || query.Get("to_currency") != "USD" {
+			t.Errorf("currencies = %q→%q, want EUR→USD", query.Get("from_currency"), query.Get("to_currency"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"Realtime Currency Exchange Rate": {
+				"1. From_Currency Code": "EUR",
+				"3. To_Currency Code": "USD",
+				"5. Exchange Rate": "1.08450000"
+			}
+		}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewForexFetcher("test_key", "EUR", "USD", server.URL)
+	ctx := context.Background()
+
+	rate, err := fetcher.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	expectedRate := 1.0845
+	if rate != expectedRate {
+		t.Errorf("Fetch() = %v, want %v", rate, expectedRate)
+	}
+}
+
+func TestForexFetcher_Fetch_MissingRate(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing section", `{}`},
+		{"empty rate", `{"Realtime Currency Exchange Rate": {"5. Exchange Rate": ""}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			})
+
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			fetcher := NewForexFetcher("test_key", "EUR", "USD", server.URL)
+
+			_, err := fetcher.Fetch(context.Background())
+			if err == nil {
+				t.Fatal("Fetch() expected error, got nil")
+			}
+
+			var fetchErr *fetcherpkg.FetchError
+			if !errors.As(err, &fetchErr) {
+				t.Fatalf("Fetch() error = %v, want *FetchError", err)
+			}
+			if fetchErr.Type != fetcherpkg.ErrorTypeValidation {
+				t.Errorf("error type = %q, want %q", fetchErr.Type, fetcherpkg.ErrorTypeValidation)
+			}
+		})
+	}
+}
+
+func TestForexFetcher_Fetch_QuotaExhausted(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Information": "Our standard API rate limit is 25 requests per day."}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewForexFetcher("test_key", "EUR", "USD", server.URL)
+
+	_, err := fetcher.Fetch(context.Background())
+	want := "rate_limit error: rate limited for EUR-USD: Our standard API rate limit is 25 requests per day."
+	if err == nil || err.Error() != want {
+		t.Errorf("Fetch() error = %v, want %q", err, want)
+	}
+}
+
+func TestForexFetcher_Fetch_InvalidRate(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Realtime Currency Exchange Rate": {"5. Exchange Rate": "n/a"}}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewForexFetcher("test_key", "EUR", "USD", server.URL)
+
+	_, err := fetcher.Fetch(context.Background())
+	if err == nil {
+		t.Fatal("Fetch() expected error for unparseable rate, got nil")
+	}
+
+	var fetchErr *fetcherpkg.FetchError
+	if !errors.As(err, &fetchErr) || fetchErr.Type != fetcherpkg.ErrorTypeValidation {
+		t.Errorf("Fetch() error = %v, want validation error", err)
+	}
+}
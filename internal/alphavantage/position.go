@@ -0,0 +1,88 @@
+package alphavantage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// PositionFetcher values a stock holding by wrapping a StockFetcher with a share
+// count and a weighted-average cost basis per share. Share counts may be fractional
+// (e.g. 2.37 shares), as brokerages allow.
+type PositionFetcher struct {
+	stock     *StockFetcher
+	shares    float64
+	costBasis float64
+}
+
+// NewPositionFetcher creates a position for shares of the stock bought at an
+// average of costBasis per share
+func NewPositionFetcher(stock *StockFetcher, shares, costBasis float64) *PositionFetcher {
+	return &PositionFetcher{
+		stock:     stock,
+		shares:    shares,
+		costBasis: costBasis,
+	}
+}
+
+// AddLot records a purchase of shares at pricePerShare, updating the weighted-average
+// cost basis. A negative share count records a sale: the shares are removed and the
+// average cost of those that remain is unchanged, so pricePerShare is ignored. Zero
+// shares and selling more shares than are held are errors that leave the position as it was.
+func (p *PositionFetcher) AddLot(shares, pricePerShare float64) error {
+	switch {
+	case shares == 0:
+		return fmt.Errorf("lot for %s has no shares", p.stock.ticker)
+	case shares < 0 && -shares > p.shares:
+		return fmt.Errorf("cannot sell %v shares of %s, only %v held", -shares, p.stock.ticker, p.shares)
+	}
+
+	total := p.shares + shares
+	if total == 0 {
+		p.shares, p.costBasis = 0, 0
+		return nil
+	}
+	if shares > 0 {
+		p.costBasis = (p.shares*p.costBasis + shares*pricePerShare) / total
+	}
+	p.shares = total
+	return nil
+}
+
+// Shares returns the number of shares held
+func (p *PositionFetcher) Shares() float64 {
+	return p.shares
+}
+
+// CostBasis returns the weighted-average cost per share
+func (p *PositionFetcher) CostBasis() float64 {
+	return p.costBasis
+}
+
+// Valuation fetches the current price and returns the position's market value
+// (price × shares) and its unrealized gain over the cost basis. Products are computed
+// in decimal, so fractional share counts don't pick up float error that could tip the
+// value across a half cent when it is rounded for output.
+func (p *PositionFetcher) Valuation(ctx context.Context) (marketValue, unrealizedGain float64, err error) {
+	price, err := p.stock.Fetch(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	shares := decimal.NewFromFloat(p.shares)
+	value := decimal.NewFromFloat(price).Mul(shares)
+	cost := decimal.NewFromFloat(p.costBasis).Mul(shares)
+	return value.InexactFloat64(), value.Sub(cost).InexactFloat64(), nil
+}
+
+// Fetch returns the position's market value so it flows into portfolio totals
+func (p *PositionFetcher) Fetch(ctx context.Context) (float64, error) {
+	marketValue, _, err := p.Valuation(ctx)
+	return marketValue, err
+}
+
+// Key returns the Redis key for this fetcher
+func (p *PositionFetcher) Key() string {
+	return fmt.Sprintf("fetcher:alphavantage:position:%s", p.stock.ticker)
+}
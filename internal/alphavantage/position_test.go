@@ -0,0 +1,163 @@
+package alphavantage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newQuoteServer(price string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Global Quote": {"05. price": "` + price + `"}}`))
+	}))
+}
+
+func TestPositionFetcher_Key(t *testing.T) {
+	position := NewPositionFetcher(NewStockFetcher("test_key", "AAPL", "http://localhost"), 10, 150)
+
+	expectedKey := "fetcher:alphavantage:position:AAPL"
+	if got := position.Key(); got != expectedKey {
+		t.Errorf("Key() = %q, want %q", got, expectedKey)
+	}
+}
+
+func TestPositionFetcher_Valuation(t *testing.T) {
+	server := newQuoteServer("200.00")
+	defer server.Close()
+
+	position := NewPositionFetcher(NewStockFetcher("test_key", "AAPL", server.URL), 10, 150)
+	ctx := context.Background()
+
+	marketValue, gain, err := position.Valuation(ctx)
+	if err != nil {
+		t.Fatalf("Valuation() error = %v", err)
+	}
+	if marketValue != 2000 {
+		t.Errorf("marketValue = %v, want 2000", marketValue)
+	}
+	if gain != 500 {
+		t.Errorf("unrealizedGain = %v, want 500", gain)
+	}
+
+	value, err := position.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if value != marketValue {
+		t.Errorf("Fetch() = %v, want market value %v", value, marketValue)
+	}
+}
+
+func TestPositionFetcher_AddLot(t *testing.T) {
+	position := NewPositionFetcher(NewStockFetcher("test_key", "AAPL", "http://localhost"), 10, 100)
+
+	if err := position.AddLot(30, 200); err != nil {
+		t.Fatalf("AddLot() error = %v", err)
+	}
+
+	if position.Shares() != 40 {
+		t.Errorf("Shares() = %v, want 40", position.Shares())
+	}
+	// (10*100 + 30*200) / 40 = 175
+	if position.CostBasis() != 175 {
+		t.Errorf("CostBasis() = %v, want 175", position.CostBasis())
+	}
+}
+
+func TestPositionFetcher_AddLot_Sale(t *testing.T) {
+	position := NewPositionFetcher(NewStockFetcher("test_key", "AAPL", "http://localhost"), 40, 175)
+
+	// A sale removes shares at any price without changing the average cost of the rest
+	if err := position.AddLot(-15, 300); err != nil {
+		t.Fatalf("AddLot() error = %v", err)
+	}
+	if position.Shares() != 25 {
+		t.Errorf("Shares() = %v, want 25", position.Shares())
+	}
+	if position.CostBasis() != 175 {
+		t.Errorf("CostBasis() = %v, want 175 (unchanged by a sale)", position.CostBasis())
+	}
+
+	// Selling everything that is left closes the position
+	if err := position.AddLot(-25, 300); err != nil {
+		t.Fatalf("AddLot() error = %v", err)
+	}
+	if position.Shares() != 0 || position.CostBasis() != 0 {
+		t.Errorf("Shares(), CostBasis() = %v, %v, want 0, 0", position.Shares(), position.CostBasis())
+	}
+}
+
+func TestPositionFetcher_AddLot_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		shares float64
+	}{
+		{"no shares", 0},
+		{"oversold", -11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position := NewPositionFetcher(NewStockFetcher("test_key", "AAPL", "http://localhost"), 10, 100)
+
+			if err := position.AddLot(tt.shares, 200); err == nil {
+				t.Errorf("AddLot(%v) expected error, got nil", tt.shares)
+			}
+			if position.Shares() != 10 || position.CostBasis() != 100 {
+				t.Errorf("Shares(), CostBasis() = %v, %v, want the position unchanged at 10, 100", position.Shares(), position.CostBasis())
+			}
+		})
+	}
+}
+
+func TestPositionFetcher_Fetch_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	position := NewPositionFetcher(NewStockFetcher("test_key", "AAPL", server.URL), 10, 150)
+
+	if _, err := position.Fetch(context.Background()); err == nil {
+		t.Error("Fetch() expected error, got nil")
+	}
+}
+
+func TestPositionFetcher_FractionalShares(t *testing.T) {
+	tests := []struct {
+		name      string
+		price     string
+		shares    float64
+		costBasis float64
+		want      float64
+		wantGain  float64
+	}{
+		// 187.43 * 2.37 is 444.20910000000003 in float64
+		{name: "fractional", price: "187.43", shares: 2.37, costBasis: 150, want: 444.2091, wantGain: 88.7091},
+		// 4.35 * 1.5 is 6.5249999999999995 in float64, which would round half-up to 6.52
+		{name: "half cent", price: "4.35", shares: 1.5, costBasis: 4, want: 6.525, wantGain: 0.525},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newQuoteServer(tt.price)
+			defer server.Close()
+
+			position := NewPositionFetcher(NewStockFetcher("test_key", "AAPL", server.URL), tt.shares, tt.costBasis)
+
+			marketValue, gain, err := position.Valuation(context.Background())
+			if err != nil {
+				t.Fatalf("Valuation() error = %v", err)
+			}
+			if marketValue != tt.want {
+				t.Errorf("marketValue = %v, want %v", marketValue, tt.want)
+			}
+			if gain != tt.wantGain {
+				t.Errorf("unrealizedGain = %v, want %v", gain, tt.wantGain)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package alphavantage
+
+import (
+	"context"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+
+	"resty.dev/v3"
+)
+
+// PriceSource is a fetcher.PriceSource that prices digital currencies in USD through
+// AlphaVantage's exchange rate endpoint, e.g. to price ETH without spending Etherscan's
+// rate limit.
+type PriceSource struct {
+	apiKey string
+	client *resty.Client
+}
+
+// NewPriceSource creates a new USD price source
+func NewPriceSource(apiKey, baseURL string) *PriceSource {
+	return &PriceSource{
+		apiKey: apiKey,
+		client: fetcher.NewHTTPClient(baseURL, fetcher.WithRateLimitedRetries(ratelimit.APIAlphaVantage)),
+	}
+}
+
+// PriceUSD retrieves the current USD price of one unit of symbol (e.g. "ETH")
+func (s *PriceSource) PriceUSD(ctx context.Context, symbol string) (float64, error) {
+	return fetchExchangeRate(ctx, s.client, s.apiKey, symbol, "USD")
+}
@@ -0,0 +1,37 @@
+package alphavantage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPriceSource_PriceUSD(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("from_currency") != "ETH" || query.Get("to_currency") != "USD" {
+			t.Errorf("pair = %s-%s, want ETH-USD", query.Get("from_currency"), query.Get("to_currency"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"Realtime Currency Exchange Rate": {
+				"1. From_Currency Code": "ETH",
+				"3. To_Currency Code": "USD",
+				"5. Exchange Rate": "3012.50000000"
+			}
+		}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	price, err := NewPriceSource("test_key", server.URL).PriceUSD(context.Background(), "ETH")
+	if err != nil {
+		t.Fatalf("PriceUSD() returned unexpected error: %v", err)
+	}
+	if expected := 3012.5; price != expected {
+		t.Errorf("PriceUSD() = %.2f, want %.2f", price, expected)
+	}
+}
@@ -0,0 +1,80 @@
+package alphavantage
+
+import (
+	"fmt"
+	"strings"
+
+	"financefetcher/internal/fetcher"
+)
+
+// bodyError classifies a problem AlphaVantage reported in the body of an HTTP 200 response
+// for subject (a ticker or currency pair), or returns nil if the response reports none.
+// A rejected API key is a client error, as is any other Error Message. Throttle and quota
+// notices in Note or Information are a retryable rate limit error.
+func bodyError(subject, errorMessage, note, information string) *fetcher.FetchError {
+	if keyErr := invalidKeyError(errorMessage, information); keyErr != nil {
+		return keyErr
+	}
+
+	if errorMessage != "" {
+		return fetcher.NewClientError(0, fmt.Sprintf("invalid request for %s: %s", subject, errorMessage))
+	}
+
+	if rateErr := noticeError(note, information); rateErr != nil {
+		rateErr.Message = fmt.Sprintf("rate limited for %s: %s", subject, rateErr.Message)
+		return rateErr
+	}
+
+	return nil
+}
+
+// invalidKeyError returns a client error for the first of msgs that says the API key was
+// rejected, or nil if none does
+func invalidKeyError(msgs ...string) *fetcher.FetchError {
+	for _, msg := range msgs {
+		if isInvalidKeyMessage(msg) {
+			return fetcher.NewClientError(0, fmt.Sprintf("invalid AlphaVantage API key: %s", msg))
+		}
+	}
+	return nil
+}
+
+// noticeError returns a rate limit error carrying the non-empty notices, or nil if there
+// are none. Throttle and quota notices mean the key was recognized but is out of quota.
+// They arrive as Note or Information and often mention the key ("We have detected your
+// API key as ..."), so callers check invalidKeyError first.
+func noticeError(notices ...string) *fetcher.FetchError {
+	var messages []string
+	for _, msg := range notices {
+		if msg != "" {
+			messages = append(messages, msg)
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	rateErr := fetcher.NewRateLimitError(0)
+	rateErr.Message = strings.Join(messages, "; ")
+	return rateErr
+}
+
+// invalidKeyPhrases are the wordings AlphaVantage uses for a missing, invalid, or demo
+// key, e.g. "the parameter apikey is invalid or missing"
+var invalidKeyPhrases = []string{
+	"apikey is invalid",
+	"api key is invalid",
+	"invalid api key",
+	"**demo** api key",
+}
+
+// isInvalidKeyMessage reports whether msg says the API key itself was rejected
+func isInvalidKeyMessage(msg string) bool {
+	lower := strings.ToLower(msg)
+	for _, phrase := range invalidKeyPhrases {
+		if strings.Contains(lower, phrase) {
+			return true
+		}
+	}
+	return false
+}
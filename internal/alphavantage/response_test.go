@@ -0,0 +1,81 @@
+package alphavantage
+
+import (
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestBodyError(t *testing.T) {
+	tests := []struct {
+		name         string
+		errorMessage string
+		note         string
+		information  string
+		wantType     fetcherpkg.ErrorType
+		wantErr      string
+	}{
+		{
+			name:     "no problem",
+			wantType: "",
+		},
+		{
+			name:         "invalid request",
+			errorMessage: "Invalid API call.",
+			wantType:     fetcherpkg.ErrorTypeClient,
+			wantErr:      "client error: invalid request for AAPL: Invalid API call.",
+		},
+		{
+			name:         "invalid key in Error Message",
+			errorMessage: "the parameter apikey is invalid or missing.",
+			wantType:     fetcherpkg.ErrorTypeClient,
+			wantErr:      "client error: invalid AlphaVantage API key: the parameter apikey is invalid or missing.",
+		},
+		{
+			name:        "demo key in Information",
+			information: "The **demo** API key is for demo purposes only.",
+			wantType:    fetcherpkg.ErrorTypeClient,
+			wantErr:     "client error: invalid AlphaVantage API key: The **demo** API key is for demo purposes only.",
+		},
+		{
+			name:     "throttled",
+			note:     "Our standard API call frequency is 5 calls per minute.",
+			wantType: fetcherpkg.ErrorTypeRateLimit,
+			wantErr:  "rate_limit error: rate limited for AAPL: Our standard API call frequency is 5 calls per minute.",
+		},
+		{
+			name:        "daily quota",
+			information: "We have detected your API key as ABC123 and our standard API rate limit is 25 requests per day.",
+			wantType:    fetcherpkg.ErrorTypeRateLimit,
+			wantErr:     "rate_limit error: rate limited for AAPL: We have detected your API key as ABC123 and our standard API rate limit is 25 requests per day.",
+		},
+		{
+			name:        "note and information",
+			note:        "Slow down.",
+			information: "Daily limit reached.",
+			wantType:    fetcherpkg.ErrorTypeRateLimit,
+			wantErr:     "rate_limit error: rate limited for AAPL: Slow down.; Daily limit reached.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := bodyError("AAPL", tt.errorMessage, tt.note, tt.information)
+			if tt.wantType == "" {
+				if err != nil {
+					t.Fatalf("bodyError() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("bodyError() = nil, want error")
+			}
+			if err.Type != tt.wantType {
+				t.Errorf("bodyError() type = %q, want %q", err.Type, tt.wantType)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("bodyError() = %q, want %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
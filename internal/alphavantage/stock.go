@@ -1,10 +1,14 @@
 package alphavantage
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strconv"
+	"strings"
+	"time"
 
 	"financefetcher/internal/fetcher"
 	"financefetcher/internal/ratelimit"
@@ -12,84 +16,286 @@ import (
 	"resty.dev/v3"
 )
 
+// GlobalQuote holds the fields of an AlphaVantage stock quote
+type GlobalQuote struct {
+	Symbol           string `json:"01. symbol"`
+	Open             string `json:"02. open"`
+	High             string `json:"03. high"`
+	Low              string `json:"04. low"`
+	Price            string `json:"05. price"`
+	Volume           string `json:"06. volume"`
+	LatestTradingDay string `json:"07. latest trading day"`
+	PreviousClose    string `json:"08. previous close"`
+	Change           string `json:"09. change"`
+	ChangePercent    string `json:"10. change percent"`
+}
+
+// UnmarshalJSON matches the quote's numbered keys by number alone, so "05. price",
+// "5. price", and "05.price" all fill Price; AlphaVantage has changed the padding and
+// spacing of these keys before. Keys without a number are ignored, and numeric values
+// are accepted in place of strings. It also accepts an empty array in place of the quote
+// object, which AlphaVantage occasionally sends instead of {}.
+func (q *GlobalQuote) UnmarshalJSON(data []byte) error {
+	*q = GlobalQuote{}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("[]")) {
+		return nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	fields := map[int]*string{
+		1:  &q.Symbol,
+		2:  &q.Open,
+		3:  &q.High,
+		4:  &q.Low,
+		5:  &q.Price,
+		6:  &q.Volume,
+		7:  &q.LatestTradingDay,
+		8:  &q.PreviousClose,
+		9:  &q.Change,
+		10: &q.ChangePercent,
+	}
+
+	for key, value := range raw {
+		field, ok := fields[keyNumber(key)]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(value, field); err != nil {
+			// Numbers and other literals are kept as their JSON text
+			*field = string(bytes.TrimSpace(value))
+		}
+	}
+
+	return nil
+}
+
+// keyNumber returns the number before the first "." in an AlphaVantage key such as
+// "05. price", ignoring surrounding whitespace and leading zeros, or 0 if there is none
+func keyNumber(key string) int {
+	prefix, _, ok := strings.Cut(key, ".")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(prefix))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
 // GlobalQuoteResponse represents the AlphaVantage API response for stock quotes
 type GlobalQuoteResponse struct {
-	GlobalQuote struct {
-		Symbol           string `json:"01. symbol"`
-		Open             string `json:"02. open"`
-		High             string `json:"03. high"`
-		Low              string `json:"04. low"`
-		Price            string `json:"05. price"`
-		Volume           string `json:"06. volume"`
-		LatestTradingDay string `json:"07. latest trading day"`
-		PreviousClose    string `json:"08. previous close"`
-		Change           string `json:"09. change"`
-		ChangePercent    string `json:"10. change percent"`
-	} `json:"Global Quote"`
+	// GlobalQuote is nil when the "Global Quote" key is absent from the response
+	GlobalQuote *GlobalQuote `json:"Global Quote"`
+
+	// ErrorMessage is set (with HTTP 200) when the request is invalid, e.g. an unknown symbol
+	ErrorMessage string `json:"Error Message"`
+	// Note is set (with HTTP 200) when the API key has exceeded its call frequency
+	Note string `json:"Note"`
+	// Information is set (with HTTP 200) when the daily quota is spent or the key is rejected
+	Information string `json:"Information"`
 }
 
+// PriceField selects which GLOBAL_QUOTE field a StockFetcher returns
+type PriceField string
+
+const (
+	// PriceCurrent is the latest traded price ("05. price"). During market hours it moves
+	// with the market; for mutual funds it is the last published NAV.
+	PriceCurrent PriceField = "price"
+
+	// PricePreviousClose is the close of the previous trading session ("08. previous close").
+	// It stays fixed through the day and ignores after-hours trading, but like the rest of
+	// GLOBAL_QUOTE it is not adjusted for splits or dividends.
+	PricePreviousClose PriceField = "previous_close"
+)
+
 // StockFetcher fetches stock prices from AlphaVantage
 type StockFetcher struct {
-	apiKey string
-	ticker string
-	client *resty.Client
+	apiKey      string
+	ticker      string
+	entitlement string
+	priceField  PriceField
+	client      *resty.Client
+
+	// maxQuoteAge rejects quotes whose latest trading day is more than this many days old; zero disables it
+	maxQuoteAge int
+
+	// now returns the current time for the quote age check
+	now func() time.Time
+}
+
+// Option configures optional behavior of a StockFetcher
+type Option func(*StockFetcher)
+
+// WithHeader adds an extra header to every request, e.g. an org id required by an API gateway
+func WithHeader(key, value string) Option {
+	return func(f *StockFetcher) {
+		f.client.SetHeader(key, value)
+	}
+}
+
+// WithEntitlement sets the entitlement query parameter ("realtime" or "delayed")
+// that premium subscriptions need to receive realtime or 15-minute delayed quotes
+func WithEntitlement(entitlement string) Option {
+	return func(f *StockFetcher) {
+		f.entitlement = entitlement
+	}
+}
+
+// WithPriceField selects which quote field drives the returned value (defaults to PriceCurrent)
+func WithPriceField(field PriceField) Option {
+	return func(f *StockFetcher) {
+		f.priceField = field
+	}
+}
+
+// WithMaxQuoteAge rejects quotes whose latest trading day is more than days calendar
+// days before today with a validation error, e.g. when the free tier serves the previous
+// session's data. Weekends and holidays count, so allow at least 3 days to accept
+// Friday's close on a Monday. Zero (the default) disables the check.
+func WithMaxQuoteAge(days int) Option {
+	return func(f *StockFetcher) {
+		f.maxQuoteAge = days
+	}
+}
+
+// WithClock sets the clock the quote age check compares against (defaults to time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(f *StockFetcher) {
+		f.now = now
+	}
+}
+
+// WithClientOptions applies HTTP client options, e.g. fetcher.WithoutRetries()
+func WithClientOptions(opts ...fetcher.ClientOption) Option {
+	return func(f *StockFetcher) {
+		for _, opt := range opts {
+			opt(f.client)
+		}
+	}
 }
 
 // NewStockFetcher creates a new stock price fetcher
-func NewStockFetcher(apiKey, ticker, baseURL string) *StockFetcher {
-	client := fetcher.NewHTTPClient(baseURL)
+func NewStockFetcher(apiKey, ticker, baseURL string, opts ...Option) *StockFetcher {
+	client := fetcher.NewHTTPClient(baseURL, fetcher.WithRateLimitedRetries(ratelimit.APIAlphaVantage))
 
-	return &StockFetcher{
-		apiKey: apiKey,
-		ticker: ticker,
-		client: client,
+	f := &StockFetcher{
+		apiKey:     apiKey,
+		ticker:     ticker,
+		priceField: PriceCurrent,
+		client:     client,
+		now:        time.Now,
 	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
 }
 
-// Fetch retrieves the current stock price
+// Fetch retrieves the stock price selected by the fetcher's PriceField
 func (f *StockFetcher) Fetch(ctx context.Context) (float64, error) {
 	// Apply rate limiting
 	limiter := ratelimit.GetLimiter()
-	if err := limiter.Wait(ctx, ratelimit.APIAlphaVantage); err != nil {
-		return 0, fetcher.NewTimeoutError(err)
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIAlphaVantage)
+	if err != nil {
+		return 0, fetcher.ClassifyLimiterError(err)
 	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIAlphaVantage, "ticker", f.ticker, "wait_duration", waited)
 
-	slog.Debug("fetching stock price from AlphaVantage", "ticker", f.ticker)
+	slog.Debug("fetching stock price from AlphaVantage", "run_id", fetcher.RunIDFromContext(ctx), "ticker", f.ticker)
 
 	var result GlobalQuoteResponse
 
 	resp, err := f.client.R().
 		SetContext(ctx).
-		SetQueryParams(map[string]string{
-			"apikey":   f.apiKey,
-			"function": "GLOBAL_QUOTE",
-			"symbol":   f.ticker,
-		}).
+		SetQueryParams(f.quoteParams()).
 		SetResult(&result).
 		Get("")
 
 	if err != nil {
-		return 0, fetcher.NewNetworkError(err)
+		return 0, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch stock price for " + f.ticker)
 	}
 
 	if !resp.IsSuccess() {
-		fetchErr := fetcher.ClassifyHTTPError(resp.StatusCode())
-		return 0, fmt.Errorf("failed to fetch stock price for %s: %w", f.ticker, fetchErr)
+		return 0, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch stock price for " + f.ticker)
+	}
+
+	// AlphaVantage reports invalid calls and throttling in the body of a 200 response
+	if bodyErr := bodyError(f.ticker, result.ErrorMessage, result.Note, result.Information); bodyErr != nil {
+		return 0, bodyErr
+	}
+
+	if result.GlobalQuote == nil {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("malformed response for %s: Global Quote missing", f.ticker))
 	}
 
-	if result.GlobalQuote.Price == "" {
-		return 0, fetcher.NewValidationError(fmt.Sprintf("price not found in response for %s", f.ticker))
+	if f.maxQuoteAge > 0 {
+		if err := f.checkQuoteAge(result.GlobalQuote.LatestTradingDay); err != nil {
+			return 0, err
+		}
 	}
 
-	price, err := strconv.ParseFloat(result.GlobalQuote.Price, 64)
+	raw := result.GlobalQuote.Price
+	if f.priceField == PricePreviousClose {
+		raw = result.GlobalQuote.PreviousClose
+	}
+
+	// An empty quote usually means the symbol is valid but has no current trading data
+	if raw == "" {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("%s not found in response for %s (empty quote, market may be closed)", f.priceField, f.ticker))
+	}
+
+	price, err := strconv.ParseFloat(raw, 64)
 	if err != nil {
-		return 0, fetcher.NewValidationError(fmt.Sprintf("failed to parse stock price: %v", err))
+		return 0, fetcher.NewValidationError(fmt.Sprintf("failed to parse stock %s for %s: %v", f.priceField, f.ticker, err))
 	}
 
 	return price, nil
 }
 
-// Key returns the Redis key for this fetcher
+// checkQuoteAge returns a validation error if the quote's latest trading day (YYYY-MM-DD)
+// is missing or more than maxQuoteAge calendar days before today
+func (f *StockFetcher) checkQuoteAge(latestTradingDay string) *fetcher.FetchError {
+	now := f.now()
+	day, err := time.ParseInLocation(time.DateOnly, latestTradingDay, now.Location())
+	if err != nil {
+		return fetcher.NewValidationError(fmt.Sprintf("failed to parse latest trading day for %s: %q", f.ticker, latestTradingDay))
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	age := int(today.Sub(day).Hours()/24 + 0.5)
+	if age > f.maxQuoteAge {
+		return fetcher.NewValidationError(fmt.Sprintf("quote for %s is stale: latest trading day %s is %d days old (max %d)",
+			f.ticker, latestTradingDay, age, f.maxQuoteAge))
+	}
+	return nil
+}
+
+// quoteParams returns the GLOBAL_QUOTE query parameters, including the entitlement if set
+func (f *StockFetcher) quoteParams() map[string]string {
+	params := map[string]string{
+		"apikey":   f.apiKey,
+		"function": "GLOBAL_QUOTE",
+		"symbol":   f.ticker,
+	}
+	if f.entitlement != "" {
+		params["entitlement"] = f.entitlement
+	}
+	return params
+}
+
+// Key returns the Redis key for this fetcher. Previous-close fetchers get their own key
+// so they don't overwrite the current price of the same ticker.
 func (f *StockFetcher) Key() string {
+	if f.priceField == PricePreviousClose {
+		return fmt.Sprintf("fetcher:alphavantage:%s:previous_close", f.ticker)
+	}
 	return fmt.Sprintf("fetcher:alphavantage:%s", f.ticker)
-}
\ No newline at end of file
+}
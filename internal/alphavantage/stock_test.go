@@ -2,11 +2,24 @@ package alphavantage
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
 )
 
+// Run this package's tests without real API rate limits
+func init() {
+	ratelimit.SetTestMode(true)
+}
+
 func TestNewStockFetcher(t *testing.T) {
 	apiKey := "test_api_key"
 	ticker := "AAPL"
@@ -93,6 +106,35 @@ func TestStockFetcher_Fetch_Success(t *testing.T) {
 	}
 }
 
+func TestStockFetcher_Fetch_PreviousClose(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"Global Quote": {
+				"01. symbol": "VFIAX",
+				"05. price": "512.10",
+				"08. previous close": "509.87"
+			}
+		}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewStockFetcher("test_key", "VFIAX", server.URL, WithPriceField(PricePreviousClose))
+
+	value, err := fetcher.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+	if expected := 509.87; value != expected {
+		t.Errorf("Fetch() = %.2f, want the previous close %.2f", value, expected)
+	}
+	if got, want := fetcher.Key(), "fetcher:alphavantage:VFIAX:previous_close"; got != want {
+		t.Errorf("Key() = %q, want %q", got, want)
+	}
+}
+
 func TestStockFetcher_Fetch_DifferentStocks(t *testing.T) {
 	tests := []struct {
 		ticker string
@@ -175,7 +217,7 @@ func TestStockFetcher_Fetch_MissingPrice(t *testing.T) {
 		t.Error("Fetch() expected error for missing price, got nil")
 	}
 
-	expectedErrMsg := "validation error: price not found in response for AAPL"
+	expectedErrMsg := "validation error: price not found in response for AAPL (empty quote, market may be closed)"
 	if err.Error() != expectedErrMsg {
 		t.Errorf("Fetch() error = %q, want %q", err.Error(), expectedErrMsg)
 	}
@@ -241,7 +283,86 @@ func TestStockFetcher_Fetch_RateLimitResponse(t *testing.T) {
 
 	_, err := fetcher.Fetch(ctx)
 	if err == nil {
-		t.Error("Fetch() expected error for rate limit response, got nil")
+		t.Fatal("Fetch() expected error for rate limit response, got nil")
+	}
+
+	var fetchErr *fetcherpkg.FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("Fetch() error = %v, want *FetchError", err)
+	}
+	if fetchErr.Type != fetcherpkg.ErrorTypeRateLimit {
+		t.Errorf("error type = %q, want %q", fetchErr.Type, fetcherpkg.ErrorTypeRateLimit)
+	}
+	if !strings.HasPrefix(fetchErr.Message, "rate limited for AAPL: ") {
+		t.Errorf("error message = %q, want it to name the ticker", fetchErr.Message)
+	}
+}
+
+func TestStockFetcher_Fetch_InformationResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantType fetcherpkg.ErrorType
+	}{
+		{"daily quota", `{"Information": "We have detected your API key as ABC123 and our standard API rate limit is 25 requests per day."}`, fetcherpkg.ErrorTypeRateLimit},
+		{"demo key", `{"Information": "The **demo** API key is for demo purposes only. Please claim your free API key."}`, fetcherpkg.ErrorTypeClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			})
+
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			fetcher := NewStockFetcher("test_key", "AAPL", server.URL)
+
+			_, err := fetcher.Fetch(context.Background())
+			if got := fetcherpkg.ErrorTypeOf(err); got != tt.wantType {
+				t.Errorf("Fetch() error = %v, want %q error", err, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestStockFetcher_Fetch_InvalidSymbol(t *testing.T) {
+	apiMessage := "Invalid API call. Please retry or visit the documentation (https://www.alphavantage.co/documentation/) for GLOBAL_QUOTE."
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Error Message": "` + apiMessage + `"}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewStockFetcher("test_key", "APPL", server.URL)
+	ctx := context.Background()
+
+	_, err := fetcher.Fetch(ctx)
+	if err == nil {
+		t.Fatal("Fetch() expected error for invalid symbol, got nil")
+	}
+
+	var fetchErr *fetcherpkg.FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("Fetch() error = %v, want *FetchError", err)
+	}
+	if fetchErr.Type != fetcherpkg.ErrorTypeClient {
+		t.Errorf("error type = %q, want %q", fetchErr.Type, fetcherpkg.ErrorTypeClient)
+	}
+	if fetchErr.Retryable {
+		t.Error("invalid symbol error should not be retryable")
+	}
+
+	expectedErrMsg := "client error: invalid request for APPL: " + apiMessage
+	if err.Error() != expectedErrMsg {
+		t.Errorf("Fetch() error = %q, want %q", err.Error(), expectedErrMsg)
 	}
 }
 
@@ -302,4 +423,250 @@ func TestStockFetcher_Fetch_VerifyQueryParams(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Fetch() returned unexpected error: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestStockFetcher_Fetch_WithHeader(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Org-Id"); got != "org_123" {
+			t.Errorf("X-Org-Id header = %q, want %q", got, "org_123")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Global Quote": {"05. price": "178.23"}}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewStockFetcher("test_key", "AAPL", server.URL, WithHeader("X-Org-Id", "org_123"))
+	ctx := context.Background()
+
+	if _, err := fetcher.Fetch(ctx); err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+}
+
+func TestStockFetcher_Fetch_HTTPErrorMessage(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewStockFetcher("test_key", "AAPL", server.URL)
+	ctx := context.Background()
+
+	_, err := fetcher.Fetch(ctx)
+	if err == nil {
+		t.Fatal("Fetch() expected error, got nil")
+	}
+
+	if _, ok := err.(*fetcherpkg.FetchError); !ok {
+		t.Fatalf("Fetch() error type = %T, want *FetchError", err)
+	}
+
+	expectedErrMsg := "client error (status 404): failed to fetch stock price for AAPL: client error: HTTP 404"
+	if err.Error() != expectedErrMsg {
+		t.Errorf("Fetch() error = %q, want %q", err.Error(), expectedErrMsg)
+	}
+}
+
+func TestStockFetcher_Fetch_WithoutRetries(t *testing.T) {
+	var requests atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewStockFetcher("test_key", "AAPL", server.URL, WithClientOptions(fetcherpkg.WithoutRetries()))
+	ctx := context.Background()
+
+	_, err := fetcher.Fetch(ctx)
+	if err == nil {
+		t.Fatal("Fetch() expected error, got nil")
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected exactly 1 request with retries disabled, got %d", got)
+	}
+}
+
+func TestStockFetcher_Fetch_QuoteShapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{
+			name:    "empty object",
+			body:    `{"Global Quote": {}}`,
+			wantErr: "validation error: price not found in response for AAPL (empty quote, market may be closed)",
+		},
+		{
+			name:    "empty array",
+			body:    `{"Global Quote": []}`,
+			wantErr: "validation error: price not found in response for AAPL (empty quote, market may be closed)",
+		},
+		{
+			name:    "missing key",
+			body:    `{"Meta Data": {}}`,
+			wantErr: "validation error: malformed response for AAPL: Global Quote missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			})
+
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			fetcher := NewStockFetcher("test_key", "AAPL", server.URL)
+
+			_, err := fetcher.Fetch(context.Background())
+			if err == nil {
+				t.Fatal("Fetch() expected error, got nil")
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("Fetch() error = %q, want %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStockFetcher_Fetch_Entitlement(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"not set", nil, ""},
+		{"realtime", []Option{WithEntitlement("realtime")}, "realtime"},
+		{"delayed", []Option{WithEntitlement("delayed")}, "delayed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				if query.Has("entitlement") != (tt.want != "") || query.Get("entitlement") != tt.want {
+					t.Errorf("entitlement = %q (present: %v), want %q", query.Get("entitlement"), query.Has("entitlement"), tt.want)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"Global Quote": {"05. price": "178.23"}}`))
+			})
+
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			fetcher := NewStockFetcher("test_key", "AAPL", server.URL, tt.opts...)
+			if _, err := fetcher.Fetch(context.Background()); err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestGlobalQuote_UnmarshalJSON_KeyFormats(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"zero-padded", `{"01. symbol": "AAPL", "05. price": "178.23", "08. previous close": "176.50"}`},
+		{"unpadded", `{"1. symbol": "AAPL", "5. price": "178.23", "8. previous close": "176.50"}`},
+		{"no space", `{"01.symbol": "AAPL", "05.price": "178.23", "08.previous close": "176.50"}`},
+		{"extra spacing", `{" 01 .  symbol": "AAPL", "05 . price": "178.23", "008. previous  close": "176.50"}`},
+		{"numeric values", `{"01. symbol": "AAPL", "05. price": 178.23, "08. previous close": 176.50, "extra": "ignored"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var quote GlobalQuote
+			if err := json.Unmarshal([]byte(tt.json), &quote); err != nil {
+				t.Fatalf("Unmarshal() returned unexpected error: %v", err)
+			}
+			if quote.Symbol != "AAPL" || quote.Price != "178.23" || quote.PreviousClose != "176.50" {
+				t.Errorf("quote = %+v, want symbol AAPL, price 178.23, previous close 176.50", quote)
+			}
+		})
+	}
+}
+
+func TestStockFetcher_Fetch_MaxQuoteAge(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"Global Quote": {
+				"01. symbol": "AAPL",
+				"05. price": "178.23",
+				"07. latest trading day": "2024-01-12"
+			}
+		}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// The quote is from Friday 2024-01-12
+	monday := func() time.Time { return time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{"check disabled", []Option{WithClock(monday)}, false},
+		{"within max age", []Option{WithMaxQuoteAge(3), WithClock(monday)}, false},
+		{"older than max age", []Option{WithMaxQuoteAge(2), WithClock(monday)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := NewStockFetcher("test_key", "AAPL", server.URL, tt.opts...)
+
+			value, err := fetcher.Fetch(context.Background())
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Fetch() returned unexpected error: %v", err)
+				}
+				if value != 178.23 {
+					t.Errorf("Fetch() = %.2f, want 178.23", value)
+				}
+				return
+			}
+
+			if fetcherpkg.ErrorTypeOf(err) != fetcherpkg.ErrorTypeValidation {
+				t.Errorf("Fetch() error = %v, want a validation error", err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "3 days old") {
+				t.Errorf("Fetch() error = %v, want it to mention the quote's age", err)
+			}
+		})
+	}
+}
+
+func TestStockFetcher_Fetch_MaxQuoteAge_MissingDate(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Global Quote": {"01. symbol": "AAPL", "05. price": "178.23"}}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewStockFetcher("test_key", "AAPL", server.URL, WithMaxQuoteAge(3))
+
+	if _, err := fetcher.Fetch(context.Background()); fetcherpkg.ErrorTypeOf(err) != fetcherpkg.ErrorTypeValidation {
+		t.Errorf("Fetch() error = %v, want a validation error for a missing trading day", err)
+	}
+}
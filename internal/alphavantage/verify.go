@@ -0,0 +1,53 @@
+package alphavantage
+
+import (
+	"context"
+	"fmt"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+)
+
+// verifyResponse captures the message fields AlphaVantage uses to report problems with HTTP 200
+type verifyResponse struct {
+	ErrorMessage string `json:"Error Message"`
+	Information  string `json:"Information"`
+	Note         string `json:"Note"`
+}
+
+// Verify checks the API key with a single GLOBAL_QUOTE call for the fetcher's ticker
+func (f *StockFetcher) Verify(ctx context.Context) error {
+	if err := ratelimit.GetLimiter().Wait(ctx, ratelimit.APIAlphaVantage); err != nil {
+		return fetcher.ClassifyLimiterError(err)
+	}
+
+	var result verifyResponse
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParams(f.quoteParams()).
+		SetResult(&result).
+		Get("")
+
+	if err != nil {
+		return fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to verify AlphaVantage API key")
+	}
+
+	if !resp.IsSuccess() {
+		return fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to verify AlphaVantage API key")
+	}
+
+	if keyErr := invalidKeyError(result.ErrorMessage, result.Information); keyErr != nil {
+		return keyErr
+	}
+
+	if rateErr := noticeError(result.Note, result.Information); rateErr != nil {
+		return rateErr
+	}
+
+	if result.ErrorMessage != "" {
+		return fetcher.NewValidationError(fmt.Sprintf("unexpected AlphaVantage response: %s", result.ErrorMessage))
+	}
+
+	return nil
+}
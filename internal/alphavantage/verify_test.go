@@ -0,0 +1,67 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestStockFetcher_Verify(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantType fetcherpkg.ErrorType
+	}{
+		{"valid key", `{"Global Quote": {"05. price": "178.23"}}`, ""},
+		{"invalid key", `{"Error Message": "the parameter apikey is invalid or missing."}`, fetcherpkg.ErrorTypeClient},
+		{"throttled", `{"Note": "Our standard API call frequency is 5 calls per minute."}`, fetcherpkg.ErrorTypeRateLimit},
+		{"daily quota mentions the key", `{"Information": "We have detected your API key as ABC123 and our standard API rate limit is 25 requests per day."}`, fetcherpkg.ErrorTypeRateLimit},
+		{"demo key", `{"Information": "The **demo** API key is for demo purposes only. Please claim your free API key."}`, fetcherpkg.ErrorTypeClient},
+		{"unexpected error message", `{"Error Message": "Invalid API call. Please retry or visit the documentation."}`, fetcherpkg.ErrorTypeValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := NewStockFetcher("test_key", "AAPL", server.URL).Verify(context.Background())
+
+			if tt.wantType == "" {
+				if err != nil {
+					t.Errorf("Verify() error = %v, want nil", err)
+				}
+				return
+			}
+			if got := fetcherpkg.ErrorTypeOf(err); got != tt.wantType {
+				t.Errorf("Verify() error type = %q, want %q (err: %v)", got, tt.wantType, err)
+			}
+		})
+	}
+}
+
+func TestStockFetcher_Verify_JoinsNotices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Note": "Slow down.", "Information": "Daily limit reached."}`))
+	}))
+	defer server.Close()
+
+	err := NewStockFetcher("test_key", "AAPL", server.URL).Verify(context.Background())
+
+	var fetchErr *fetcherpkg.FetchError
+	if !errors.As(err, &fetchErr) || fetchErr.Type != fetcherpkg.ErrorTypeRateLimit {
+		t.Fatalf("Verify() error = %v, want rate limit error", err)
+	}
+	if want := "Slow down.; Daily limit reached."; fetchErr.Message != want {
+		t.Errorf("Message = %q, want %q", fetchErr.Message, want)
+	}
+}
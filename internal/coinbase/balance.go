@@ -0,0 +1,280 @@
+package coinbase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+
+	"resty.dev/v3"
+)
+
+const (
+	// apiVersion pins the response format of the v2 API
+	apiVersion = "2024-01-01"
+
+	// accountsPageSize is the largest page the accounts endpoint returns
+	accountsPageSize = 100
+
+	// maxAccountPages bounds pagination so a misbehaving next_uri can't loop forever
+	maxAccountPages = 10
+)
+
+// Money is an amount in a currency. Coinbase sends amounts as strings to keep precision.
+type Money struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// Account is a single currency wallet in a Coinbase account
+type Account struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Balance Money  `json:"balance"`
+}
+
+// Pagination links to the next page of a list response
+type Pagination struct {
+	NextURI string `json:"next_uri"`
+}
+
+// AccountsResponse represents the Coinbase /v2/accounts response
+type AccountsResponse struct {
+	Pagination Pagination `json:"pagination"`
+	Data       []Account  `json:"data"`
+}
+
+// ExchangeRatesResponse represents the Coinbase /v2/exchange-rates response. Rates are how
+// much of each currency one unit of Data.Currency buys.
+type ExchangeRatesResponse struct {
+	Data struct {
+		Currency string            `json:"currency"`
+		Rates    map[string]string `json:"rates"`
+	} `json:"data"`
+}
+
+// BalanceFetcher fetches the total USD value of every wallet in a Coinbase account
+type BalanceFetcher struct {
+	apiKey    string
+	apiSecret string
+	account   string
+	client    *resty.Client
+
+	// now timestamps signed requests; Coinbase rejects signatures more than 30s old
+	now func() time.Time
+}
+
+// Option configures a BalanceFetcher
+type Option func(*BalanceFetcher)
+
+// WithClock replaces the time source used to timestamp signed requests (for tests)
+func WithClock(now func() time.Time) Option {
+	return func(f *BalanceFetcher) {
+		f.now = now
+	}
+}
+
+// NewBalanceFetcher creates a fetcher for the Coinbase account named account, which only
+// labels the key. Every request is signed with apiKey and apiSecret.
+func NewBalanceFetcher(apiKey, apiSecret, account, baseURL string, opts ...Option) *BalanceFetcher {
+	client := fetcher.NewHTTPClient(baseURL, fetcher.WithRateLimitedRetries(ratelimit.APICoinbase))
+	client.SetHeader("CB-VERSION", apiVersion)
+
+	f := &BalanceFetcher{
+		apiKey:    apiKey,
+		apiSecret: apiSecret,
+		account:   account,
+		client:    client,
+		now:       time.Now,
+	}
+
+	// Sign in the transport, which sees the final URL and runs again for each retry,
+	// so every attempt gets a fresh timestamp
+	client.SetTransport(&signingTransport{fetcher: f, base: client.Transport()})
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+// Fetch retrieves every wallet balance and returns their combined USD value.
+// Balances in currencies Coinbase has no USD rate for are skipped with a warning.
+func (f *BalanceFetcher) Fetch(ctx context.Context) (float64, error) {
+	accounts, err := f.fetchAccounts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rates, err := f.fetchRates(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for _, account := range accounts {
+		amount, err := strconv.ParseFloat(account.Balance.Amount, 64)
+		if err != nil {
+			return 0, fetcher.NewValidationError(fmt.Sprintf("invalid balance %q for %s wallet in Coinbase account %s",
+				account.Balance.Amount, account.Balance.Currency, f.account))
+		}
+		if amount == 0 {
+			continue
+		}
+
+		value, ok := usdValue(amount, account.Balance.Currency, rates)
+		if !ok {
+			slog.Warn("no USD rate for Coinbase balance, skipping", "run_id", fetcher.RunIDFromContext(ctx), "account", f.account, "currency", account.Balance.Currency, "amount", amount)
+			continue
+		}
+		total += value
+	}
+
+	return total, nil
+}
+
+// fetchAccounts lists every wallet in the account, following pagination
+func (f *BalanceFetcher) fetchAccounts(ctx context.Context) ([]Account, error) {
+	var accounts []Account
+
+	next := "/v2/accounts?limit=" + strconv.Itoa(accountsPageSize)
+	for page := 0; next != ""; page++ {
+		if page == maxAccountPages {
+			return nil, fetcher.NewValidationError(fmt.Sprintf("Coinbase account %s has more than %d pages of wallets", f.account, maxAccountPages))
+		}
+
+		if err := f.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		slog.Debug("fetching Coinbase accounts", "run_id", fetcher.RunIDFromContext(ctx), "account", f.account, "page", page)
+
+		var result AccountsResponse
+
+		resp, err := f.client.R().
+			SetContext(ctx).
+			SetResult(&result).
+			Get(next)
+
+		if err != nil {
+			return nil, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch Coinbase accounts for " + f.account)
+		}
+
+		if !resp.IsSuccess() {
+			return nil, f.httpError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch Coinbase accounts for " + f.account)
+		}
+
+		accounts = append(accounts, result.Data...)
+		next = result.Pagination.NextURI
+	}
+
+	return accounts, nil
+}
+
+// fetchRates returns how much of each currency one US dollar buys
+func (f *BalanceFetcher) fetchRates(ctx context.Context) (map[string]string, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	slog.Debug("fetching Coinbase exchange rates", "run_id", fetcher.RunIDFromContext(ctx), "account", f.account)
+
+	var result ExchangeRatesResponse
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParam("currency", "USD").
+		SetResult(&result).
+		Get("/v2/exchange-rates")
+
+	if err != nil {
+		return nil, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch Coinbase exchange rates")
+	}
+
+	if !resp.IsSuccess() {
+		return nil, f.httpError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch Coinbase exchange rates")
+	}
+
+	if len(result.Data.Rates) == 0 {
+		return nil, fetcher.NewValidationError("exchange rates not found in Coinbase response")
+	}
+
+	return result.Data.Rates, nil
+}
+
+// wait applies rate limiting before a request
+func (f *BalanceFetcher) wait(ctx context.Context) error {
+	waited, err := ratelimit.GetLimiter().WaitTimed(ctx, ratelimit.APICoinbase)
+	if err != nil {
+		return fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APICoinbase, "account", f.account, "wait_duration", waited)
+	return nil
+}
+
+// httpError classifies a failed response. Coinbase answers a bad key, secret, or
+// signature with 401 and a key lacking wallet:accounts:read with 403; both are client
+// errors that retrying won't fix, so the message points at the credentials.
+func (f *BalanceFetcher) httpError(statusCode int) *fetcher.FetchError {
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		return fetcher.NewClientError(statusCode,
+			fmt.Sprintf("Coinbase rejected the credentials (HTTP %d); check COINBASE_API_KEY, COINBASE_API_SECRET, and the key's permissions", statusCode))
+	}
+	return fetcher.ClassifyHTTPError(statusCode)
+}
+
+// signingTransport adds Coinbase's HMAC authentication headers to each outgoing request.
+// Only bodiless requests are sent, so the body is always signed as empty.
+type signingTransport struct {
+	fetcher *BalanceFetcher
+	base    http.RoundTripper
+}
+
+// RoundTrip signs a copy of req and sends it with the base transport
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timestamp := strconv.FormatInt(t.fetcher.now().Unix(), 10)
+
+	signed := req.Clone(req.Context())
+	signed.Header.Set("CB-ACCESS-KEY", t.fetcher.apiKey)
+	signed.Header.Set("CB-ACCESS-TIMESTAMP", timestamp)
+	signed.Header.Set("CB-ACCESS-SIGN", sign(t.fetcher.apiSecret, timestamp, req.Method, req.URL.RequestURI(), ""))
+
+	return t.base.RoundTrip(signed)
+}
+
+// sign returns the hex HMAC-SHA256 of timestamp + method + requestPath + body, keyed
+// with the API secret, as Coinbase expects in CB-ACCESS-SIGN
+func sign(secret, timestamp, method, requestPath, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + method + requestPath + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// usdValue converts amount of currency to US dollars using rates quoted per dollar.
+// It reports false if there's no usable rate for currency.
+func usdValue(amount float64, currency string, rates map[string]string) (float64, bool) {
+	if currency == "USD" {
+		return amount, true
+	}
+
+	rate, err := strconv.ParseFloat(rates[currency], 64)
+	if err != nil || rate <= 0 {
+		return 0, false
+	}
+	return amount / rate, true
+}
+
+// Key returns the Redis key for this fetcher
+func (f *BalanceFetcher) Key() string {
+	return fmt.Sprintf("fetcher:coinbase:%s", f.account)
+}
@@ -0,0 +1,185 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+)
+
+// Run this package's tests without real API rate limits
+func init() {
+	ratelimit.SetTestMode(true)
+}
+
+const (
+	testKey    = "test_key"
+	testSecret = "test_secret"
+)
+
+// fixedClock returns a clock stuck at a known instant
+func fixedClock() time.Time {
+	return time.Unix(1700000000, 0)
+}
+
+// checkSignature reports a test error unless r carries a valid signature for testSecret
+func checkSignature(t *testing.T, r *http.Request) {
+	t.Helper()
+
+	if got := r.Header.Get("CB-ACCESS-KEY"); got != testKey {
+		t.Errorf("CB-ACCESS-KEY = %q, want %q", got, testKey)
+	}
+	if got := r.Header.Get("CB-ACCESS-TIMESTAMP"); got != "1700000000" {
+		t.Errorf("CB-ACCESS-TIMESTAMP = %q, want 1700000000", got)
+	}
+	if r.Header.Get("CB-VERSION") == "" {
+		t.Error("CB-VERSION header is missing")
+	}
+
+	want := sign(testSecret, "1700000000", r.Method, r.URL.RequestURI(), "")
+	if got := r.Header.Get("CB-ACCESS-SIGN"); got != want {
+		t.Errorf("CB-ACCESS-SIGN for %s = %q, want %q", r.URL.RequestURI(), got, want)
+	}
+}
+
+// newTestServer serves two pages of accounts and a USD rate table
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		checkSignature(t, r)
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Query().Get("starting_after") == "" {
+			w.Write([]byte(`{
+				"pagination": {"next_uri": "/v2/accounts?limit=100&starting_after=btc"},
+				"data": [
+					{"id": "usd", "name": "Cash (USD)", "type": "fiat", "balance": {"amount": "250.50", "currency": "USD"}},
+					{"id": "btc", "name": "BTC Wallet", "type": "wallet", "balance": {"amount": "0.5", "currency": "BTC"}}
+				]
+			}`))
+			return
+		}
+
+		w.Write([]byte(`{
+			"pagination": {"next_uri": null},
+			"data": [
+				{"id": "eth", "name": "ETH Wallet", "type": "wallet", "balance": {"amount": "2.0", "currency": "ETH"}},
+				{"id": "doge", "name": "DOGE Wallet", "type": "wallet", "balance": {"amount": "0.0", "currency": "DOGE"}},
+				{"id": "obscure", "name": "XYZ Wallet", "type": "wallet", "balance": {"amount": "1000", "currency": "XYZ"}}
+			]
+		}`))
+	})
+	mux.HandleFunc("GET /v2/exchange-rates", func(w http.ResponseWriter, r *http.Request) {
+		checkSignature(t, r)
+		if got := r.URL.Query().Get("currency"); got != "USD" {
+			t.Errorf("currency = %q, want USD", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"currency": "USD", "rates": {"BTC": "0.00002", "ETH": "0.0005", "DOGE": "10"}}}`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestBalanceFetcher_Key(t *testing.T) {
+	fetcher := NewBalanceFetcher(testKey, testSecret, "main", "http://localhost")
+
+	if got := fetcher.Key(); got != "fetcher:coinbase:main" {
+		t.Errorf("Key() = %q, want %q", got, "fetcher:coinbase:main")
+	}
+}
+
+func TestBalanceFetcher_Fetch_Success(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	fetcher := NewBalanceFetcher(testKey, testSecret, "main", server.URL, WithClock(fixedClock))
+
+	value, err := fetcher.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	// 250.50 USD + 0.5 BTC at $50,000 + 2 ETH at $2,000; XYZ has no rate and is skipped
+	want := 250.50 + 25000 + 4000
+	if math.Abs(value-want) > 0.001 {
+		t.Errorf("Fetch() = %.2f, want %.2f", value, want)
+	}
+}
+
+func TestBalanceFetcher_Fetch_AuthError(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"errors": [{"id": "authentication_error", "message": "invalid signature"}]}`))
+		})
+
+		server := httptest.NewServer(handler)
+
+		fetcher := NewBalanceFetcher(testKey, "wrong_secret", "main", server.URL)
+		_, err := fetcher.Fetch(context.Background())
+		server.Close()
+
+		var fetchErr *fetcherpkg.FetchError
+		if !errors.As(err, &fetchErr) {
+			t.Fatalf("HTTP %d: Fetch() error = %v, want a FetchError", status, err)
+		}
+		if fetchErr.Type != fetcherpkg.ErrorTypeClient {
+			t.Errorf("HTTP %d: error type = %q, want %q", status, fetchErr.Type, fetcherpkg.ErrorTypeClient)
+		}
+		if fetchErr.Retryable {
+			t.Errorf("HTTP %d: auth error should not be retryable", status)
+		}
+	}
+}
+
+func TestBalanceFetcher_Fetch_InvalidBalance(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/accounts":
+			w.Write([]byte(`{"data": [{"id": "btc", "balance": {"amount": "lots", "currency": "BTC"}}]}`))
+		default:
+			w.Write([]byte(`{"data": {"currency": "USD", "rates": {"BTC": "0.00002"}}}`))
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewBalanceFetcher(testKey, testSecret, "main", server.URL)
+
+	_, err := fetcher.Fetch(context.Background())
+	if fetcherpkg.ErrorTypeOf(err) != fetcherpkg.ErrorTypeValidation {
+		t.Errorf("Fetch() error = %v, want validation error", err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Changing any signed part must change the signature
+	base := sign("secret", "1700000000", "GET", "/v2/accounts", "")
+	if len(base) != 64 {
+		t.Fatalf("sign() = %q, want 64 hex characters", base)
+	}
+
+	variants := []string{
+		sign("other", "1700000000", "GET", "/v2/accounts", ""),
+		sign("secret", "1700000001", "GET", "/v2/accounts", ""),
+		sign("secret", "1700000000", "POST", "/v2/accounts", ""),
+		sign("secret", "1700000000", "GET", "/v2/accounts?limit=100", ""),
+		sign("secret", "1700000000", "GET", "/v2/accounts", "{}"),
+	}
+	for i, v := range variants {
+		if v == base {
+			t.Errorf("variant %d has the same signature as the base request", i)
+		}
+	}
+}
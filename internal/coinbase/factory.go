@@ -0,0 +1,16 @@
+package coinbase
+
+import "financefetcher/internal/fetcher"
+
+func init() {
+	fetcher.Register("coinbase", newFromParams)
+}
+
+// newFromParams builds a BalanceFetcher from the "api_key", "api_secret", "account", and
+// "base_url" parameters
+func newFromParams(params fetcher.Params) (fetcher.Fetcher, error) {
+	if err := params.Require("api_key", "api_secret", "account", "base_url"); err != nil {
+		return nil, err
+	}
+	return NewBalanceFetcher(params["api_key"], params["api_secret"], params["account"], params["base_url"]), nil
+}
@@ -0,0 +1,22 @@
+package coinbase
+
+import (
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestFactory(t *testing.T) {
+	f, err := fetcherpkg.New("coinbase", fetcherpkg.Params{
+		"api_key":    "test_key",
+		"api_secret": "test_secret",
+		"account":    "main",
+		"base_url":   "http://localhost",
+	})
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	if got := f.Key(); got != "fetcher:coinbase:main" {
+		t.Errorf("Key() = %q, want %q", got, "fetcher:coinbase:main")
+	}
+}
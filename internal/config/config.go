@@ -2,39 +2,141 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+// Stock price providers selectable via STOCK_PROVIDER
+const (
+	StockProviderAlphaVantage = "alphavantage"
+	StockProviderFinnhub      = "finnhub"
+)
+
+// Ethereum balance providers selectable via ETH_PROVIDER
+const (
+	EthProviderEtherscan = "etherscan"
+	EthProviderRPC       = "rpc"
+)
+
+// defaultBaseURLs maps each service to its production base URL. Every service listed here
+// is configurable through a "<service>_base_url" key and a <SERVICE>_BASE_URL environment
+// variable, and is available from Config.BaseURL, so a new provider only needs an entry here.
+var defaultBaseURLs = map[string]string{
+	"etherscan":    "https://api.etherscan.io/v2/api",
+	"alphavantage": "https://www.alphavantage.co/query",
+	"rentcast":     "https://api.rentcast.io/v1",
+	"guideline":    "https://my.guideline.com",
+	"finnhub":      "https://finnhub.io/api/v1",
+	"coinbase":     "https://api.coinbase.com",
+}
+
 // PropertyConfig holds configuration for a property to be valued.
 type PropertyConfig struct {
-	Address        string  `mapstructure:"address"`
-	PropertyType   string  `mapstructure:"property_type"`
-	Bedrooms       int     `mapstructure:"bedrooms"`
-	Bathrooms      float64 `mapstructure:"bathrooms"`
-	SquareFootage  int     `mapstructure:"square_footage"`
+	Address       string  `mapstructure:"address"`
+	PropertyType  string  `mapstructure:"property_type"`
+	Bedrooms      int     `mapstructure:"bedrooms"`
+	Bathrooms     float64 `mapstructure:"bathrooms"`
+	SquareFootage int     `mapstructure:"square_footage"`
+
+	// Enabled set to false skips the property without deleting its config (defaults to true)
+	Enabled *bool `mapstructure:"enabled"`
+}
+
+// IsEnabled reports whether the property should be fetched; it is true unless enabled is
+// explicitly set to false
+func (p PropertyConfig) IsEnabled() bool {
+	return p.Enabled == nil || *p.Enabled
 }
 
 // Config holds all configuration for the finance fetcher application.
 type Config struct {
 	// API Keys for various services
-	EtherscanAPIKey     string `mapstructure:"etherscan_api_key"`
-	AlphavantageAPIKey  string `mapstructure:"alphavantage_api_key"`
-	RentcastAPIKey      string `mapstructure:"rentcast_api_key"`
-	GuidelineEmail      string `mapstructure:"guideline_email"`
-	GuidelinePassword   string `mapstructure:"guideline_password"`
+	EtherscanAPIKey    string `mapstructure:"etherscan_api_key"`
+	AlphavantageAPIKey string `mapstructure:"alphavantage_api_key"`
+	RentcastAPIKey     string `mapstructure:"rentcast_api_key"`
+	FinnhubAPIKey      string `mapstructure:"finnhub_api_key"`
+	GuidelineEmail     string `mapstructure:"guideline_email"`
+	GuidelinePassword  string `mapstructure:"guideline_password"`
+
+	// Coinbase API key and secret (optional; when set, the account's balances are fetched)
+	// and the account name used in its key
+	CoinbaseAPIKey    string `mapstructure:"coinbase_api_key"`
+	CoinbaseAPISecret string `mapstructure:"coinbase_api_secret"`
+	CoinbaseAccount   string `mapstructure:"coinbase_account"`
 
 	// Base URLs for API endpoints (configurable for testing)
-	EtherscanBaseURL     string `mapstructure:"etherscan_base_url"`
-	AlphavantageBaseURL  string `mapstructure:"alphavantage_base_url"`
-	RentcastBaseURL      string `mapstructure:"rentcast_base_url"`
-	GuidelineBaseURL     string `mapstructure:"guideline_base_url"`
+	EtherscanBaseURL    string `mapstructure:"etherscan_base_url"`
+	AlphavantageBaseURL string `mapstructure:"alphavantage_base_url"`
+	RentcastBaseURL     string `mapstructure:"rentcast_base_url"`
+	FinnhubBaseURL      string `mapstructure:"finnhub_base_url"`
+	GuidelineBaseURL    string `mapstructure:"guideline_base_url"`
+
+	// Base URLs for every service in defaultBaseURLs, keyed by service name
+	BaseURLs map[string]string `mapstructure:"-"`
+
+	// Which provider fetches stock prices ("alphavantage" or "finnhub")
+	StockProvider string `mapstructure:"stock_provider"`
+
+	// Which provider fetches wallet balances ("etherscan" or "rpc"), and the JSON-RPC
+	// endpoint used by "rpc" (Alchemy/Infura URLs embed the API key, so it is a secret)
+	EthProvider string `mapstructure:"eth_provider"`
+	EthRPCURL   string `mapstructure:"eth_rpc_url"`
+
+	// Rate limits (requests per minute and burst size)
+	AlphavantageRatePerMin float64 `mapstructure:"alphavantage_rate_per_min"`
+	AlphavantageBurst      int     `mapstructure:"alphavantage_burst"`
+
+	// How long a fetched ETH price is shared across wallet fetchers (0 disables caching)
+	EthPriceCacheTTL time.Duration `mapstructure:"eth_price_cache_ttl"`
+
+	// ETH set aside for gas when reporting Etherscan wallet balances (0 reports the full balance)
+	EthGasReserve float64 `mapstructure:"eth_gas_reserve"`
+
+	// Overall deadline for a single fetch run, or for each cycle with -interval; it must
+	// leave room for rate limiter waits
+	RunTimeout time.Duration `mapstructure:"run_timeout"`
+
+	// Maximum retries per API host across all fetchers in a single run (0 is unlimited)
+	RetryBudget int `mapstructure:"retry_budget"`
+
+	// Collapse configured items that resolve to the same key, keeping the first
+	DedupKeys bool `mapstructure:"dedup_keys"`
+
+	// Warn when a single holding exceeds this percentage of the total (0 disables)
+	MaxAllocationPct float64 `mapstructure:"max_allocation_pct"`
+
+	// Log every outgoing HTTP request and its status at debug level, with API keys redacted
+	DebugHTTP bool `mapstructure:"debug_http"`
+
+	// SQLite file recording every successful fetch for history (empty disables)
+	HistoryDB string `mapstructure:"history_db"`
+
+	// Output formatting locale for monetary values (e.g. "en-US", "de-DE")
+	OutputLocale string `mapstructure:"output_locale"`
+
+	// How monetary values are rounded to cents ("half-even", "half-up", or "truncate")
+	OutputRounding string `mapstructure:"output_rounding"`
 
 	// Items to fetch
-	EthereumWallets []string          `mapstructure:"ethereum_wallets"`
-	StockSymbols    []string          `mapstructure:"stock_symbols"`
-	Properties      []PropertyConfig  `mapstructure:"properties"`
+	EthereumWallets []string         `mapstructure:"ethereum_wallets"`
+	StockSymbols    []string         `mapstructure:"stock_symbols"`
+	Properties      []PropertyConfig `mapstructure:"properties"`
+
+	// Watchlist file with one stock symbol per line, read when StockSymbols is empty
+	StockSymbolsFile string `mapstructure:"stock_symbols_file"`
+
+	// Wallet addresses and stock symbols to skip without removing them from the lists above
+	DisabledItems []string `mapstructure:"disabled_items"`
+
+	// Display names by result key (e.g. "fetcher:etherscan:0x742d..." to "Cold wallet"),
+	// printed in place of the key. Keys are lowercased when loaded. Viper would split keys
+	// at dots (as in BRK.B), so the labels key is read separately (see readLabels).
+	Labels map[string]string `mapstructure:"-"`
 }
 
 // Load reads configuration from environment variables and optional config file.
@@ -42,7 +144,8 @@ type Config struct {
 //
 // Expected environment variables:
 //   - ETHERSCAN_API_KEY
-//   - ALPHAVANTAGE_API_KEY
+//   - ALPHAVANTAGE_API_KEY (when STOCK_PROVIDER is alphavantage)
+//   - FINNHUB_API_KEY (when STOCK_PROVIDER is finnhub)
 //   - RENTCAST_API_KEY
 //   - GUIDELINE_EMAIL
 //   - GUIDELINE_PASSWORD
@@ -50,7 +153,43 @@ type Config struct {
 //   - ALPHAVANTAGE_BASE_URL (optional, defaults to production)
 //   - RENTCAST_BASE_URL (optional, defaults to production)
 //   - GUIDELINE_BASE_URL (optional, defaults to production)
-func Load() (*Config, error) {
+//   - FINNHUB_BASE_URL (optional, defaults to production)
+//   - COINBASE_API_KEY and COINBASE_API_SECRET (optional, together enable the Coinbase balance)
+//   - COINBASE_ACCOUNT (optional, names the Coinbase account in its key, defaults to default)
+//   - COINBASE_BASE_URL (optional, defaults to production)
+//   - STOCK_PROVIDER (optional, alphavantage or finnhub, defaults to alphavantage)
+//   - ETH_PROVIDER (optional, etherscan or rpc, defaults to etherscan)
+//   - ETH_RPC_URL (when ETH_PROVIDER is rpc)
+//   - ALPHAVANTAGE_RATE_PER_MIN (optional, defaults to the free tier's 5)
+//   - ALPHAVANTAGE_BURST (optional, defaults to one second's worth of requests)
+//   - ETH_PRICE_CACHE_TTL (optional, defaults to 30s)
+//   - ETH_GAS_RESERVE (optional, ETH subtracted from each wallet balance, defaults to 0)
+//   - OUTPUT_LOCALE (optional, defaults to en-US)
+//   - OUTPUT_ROUNDING (optional, half-even, half-up, or truncate, defaults to half-even)
+//   - RUN_TIMEOUT (optional, deadline for a run or each -interval cycle, defaults to 30s)
+//   - RETRY_BUDGET (optional, retries per API host per run, defaults to 0 which is unlimited)
+//   - DEDUP_KEYS (optional, 1 skips items that duplicate an earlier item's key, defaults to off)
+//   - DEBUG_HTTP (optional, 1 logs each HTTP request at debug level with API keys redacted)
+//   - MAX_ALLOCATION_PCT (optional, 0-100, defaults to 0 which disables the check)
+//   - HISTORY_DB (optional, SQLite file recording every successful fetch)
+//   - STOCK_SYMBOLS_FILE (optional, watchlist file read when stock_symbols is empty)
+//   - DISABLED_ITEMS (optional, comma-separated wallets and symbols to skip)
+//
+// Each API key and credential can instead be read from a file by setting the
+// variable with a _FILE suffix (e.g. ETHERSCAN_API_KEY_FILE=/run/secrets/etherscan),
+// matching the Docker/Kubernetes secrets convention. The file is only consulted
+// when the value isn't already set directly. WithSecretProvider replaces the environment
+// and files with another source, such as Vault, for values that aren't set directly.
+//
+// Unknown top-level keys in the config file (e.g. a misspelled stock_symbol) are
+// rejected rather than silently ignored. At least one of ethereum_wallets,
+// stock_symbols, or properties must be configured.
+func Load(opts ...LoadOption) (*Config, error) {
+	options := loadOptions{secrets: EnvSecretProvider{}}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	v := viper.New()
 
 	// Set up environment variable support
@@ -58,10 +197,31 @@ func Load() (*Config, error) {
 	v.AutomaticEnv()
 
 	// Set defaults for base URLs
-	v.SetDefault("etherscan_base_url", "https://api.etherscan.io/v2/api")
-	v.SetDefault("alphavantage_base_url", "https://www.alphavantage.co/query")
-	v.SetDefault("rentcast_base_url", "https://api.rentcast.io/v1")
-	v.SetDefault("guideline_base_url", "https://my.guideline.com")
+	for service, url := range defaultBaseURLs {
+		v.SetDefault(baseURLKey(service), url)
+	}
+
+	// Stock prices come from AlphaVantage unless another provider is selected
+	v.SetDefault("stock_provider", StockProviderAlphaVantage)
+
+	// Wallet balances come from Etherscan unless a JSON-RPC endpoint is selected
+	v.SetDefault("eth_provider", EthProviderEtherscan)
+
+	// Default to the AlphaVantage free tier (5 requests per minute)
+	v.SetDefault("alphavantage_rate_per_min", 5)
+
+	// Share the ETH price across wallet fetchers for a short time
+	v.SetDefault("eth_price_cache_ttl", "30s")
+
+	// Give a single run 30 seconds to finish by default
+	v.SetDefault("run_timeout", "30s")
+
+	// Name the Coinbase account "default" unless told otherwise
+	v.SetDefault("coinbase_account", "default")
+
+	// Format output like 1,234,567.89 by default
+	v.SetDefault("output_locale", "en-US")
+	v.SetDefault("output_rounding", "half-even")
 
 	// Optionally read from config file if it exists
 	v.SetConfigName("config")
@@ -78,12 +238,53 @@ func Load() (*Config, error) {
 	v.BindEnv("rentcast_api_key", "RENTCAST_API_KEY")
 	v.BindEnv("guideline_email", "GUIDELINE_EMAIL")
 	v.BindEnv("guideline_password", "GUIDELINE_PASSWORD")
+	v.BindEnv("finnhub_api_key", "FINNHUB_API_KEY")
+	v.BindEnv("eth_rpc_url", "ETH_RPC_URL")
+	v.BindEnv("coinbase_api_key", "COINBASE_API_KEY")
+	v.BindEnv("coinbase_api_secret", "COINBASE_API_SECRET")
+	v.BindEnv("coinbase_account", "COINBASE_ACCOUNT")
 
 	// Bind environment variables for base URLs
-	v.BindEnv("etherscan_base_url", "ETHERSCAN_BASE_URL")
-	v.BindEnv("alphavantage_base_url", "ALPHAVANTAGE_BASE_URL")
-	v.BindEnv("rentcast_base_url", "RENTCAST_BASE_URL")
-	v.BindEnv("guideline_base_url", "GUIDELINE_BASE_URL")
+	for service := range defaultBaseURLs {
+		v.BindEnv(baseURLKey(service), strings.ToUpper(baseURLKey(service)))
+	}
+
+	// Bind environment variables for provider selection
+	v.BindEnv("stock_provider", "STOCK_PROVIDER")
+	v.BindEnv("eth_provider", "ETH_PROVIDER")
+
+	// Bind environment variables for rate limits
+	v.BindEnv("alphavantage_rate_per_min", "ALPHAVANTAGE_RATE_PER_MIN")
+	v.BindEnv("alphavantage_burst", "ALPHAVANTAGE_BURST")
+
+	// Bind environment variables for caching
+	v.BindEnv("eth_price_cache_ttl", "ETH_PRICE_CACHE_TTL")
+
+	// Bind environment variables for wallet reporting
+	v.BindEnv("eth_gas_reserve", "ETH_GAS_RESERVE")
+
+	// Bind environment variables for timeouts
+	v.BindEnv("run_timeout", "RUN_TIMEOUT")
+	v.BindEnv("retry_budget", "RETRY_BUDGET")
+	v.BindEnv("dedup_keys", "DEDUP_KEYS")
+
+	// Bind environment variables for output
+	v.BindEnv("output_locale", "OUTPUT_LOCALE")
+	v.BindEnv("output_rounding", "OUTPUT_ROUNDING")
+	v.BindEnv("debug_http", "DEBUG_HTTP")
+
+	// Bind environment variables for history
+	v.BindEnv("history_db", "HISTORY_DB")
+
+	// Bind environment variables for items
+	v.BindEnv("stock_symbols_file", "STOCK_SYMBOLS_FILE")
+	v.BindEnv("disabled_items", "DISABLED_ITEMS")
+	v.BindEnv("max_allocation_pct", "MAX_ALLOCATION_PCT")
+
+	// Catch typos in the config file before they silently drop settings
+	if err := checkUnknownKeys(v); err != nil {
+		return nil, err
+	}
 
 	// Unmarshal config into struct (handles both simple and complex fields)
 	config := &Config{}
@@ -91,14 +292,74 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	labels, err := readLabels(v.Get(labelsKey))
+	if err != nil {
+		return nil, err
+	}
+	config.Labels = labels
+
+	config.BaseURLs = make(map[string]string, len(defaultBaseURLs))
+	for service := range defaultBaseURLs {
+		config.BaseURLs[service] = v.GetString(baseURLKey(service))
+	}
+
+	// Fill in secrets that weren't set directly, from files or the configured provider
+	secrets := []struct {
+		envVar string
+		dest   *string
+	}{
+		{"ETHERSCAN_API_KEY", &config.EtherscanAPIKey},
+		{"ALPHAVANTAGE_API_KEY", &config.AlphavantageAPIKey},
+		{"RENTCAST_API_KEY", &config.RentcastAPIKey},
+		{"GUIDELINE_EMAIL", &config.GuidelineEmail},
+		{"GUIDELINE_PASSWORD", &config.GuidelinePassword},
+		{"FINNHUB_API_KEY", &config.FinnhubAPIKey},
+		{"ETH_RPC_URL", &config.EthRPCURL},
+		{"COINBASE_API_KEY", &config.CoinbaseAPIKey},
+		{"COINBASE_API_SECRET", &config.CoinbaseAPISecret},
+	}
+	for _, secret := range secrets {
+		if err := loadSecret(options.secrets, secret.envVar, secret.dest); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(config.StockSymbols) == 0 && config.StockSymbolsFile != "" {
+		symbols, err := readWatchlist(config.StockSymbolsFile)
+		if err != nil {
+			return nil, err
+		}
+		config.StockSymbols = symbols
+	}
+
+	switch config.StockProvider {
+	case StockProviderAlphaVantage, StockProviderFinnhub:
+	default:
+		return nil, fmt.Errorf("STOCK_PROVIDER must be %q or %q, got %q",
+			StockProviderAlphaVantage, StockProviderFinnhub, config.StockProvider)
+	}
+
+	switch config.EthProvider {
+	case EthProviderEtherscan, EthProviderRPC:
+	default:
+		return nil, fmt.Errorf("ETH_PROVIDER must be %q or %q, got %q",
+			EthProviderEtherscan, EthProviderRPC, config.EthProvider)
+	}
+
 	// Validate required fields
 	var missing []string
 	if config.EtherscanAPIKey == "" {
 		missing = append(missing, "ETHERSCAN_API_KEY")
 	}
-	if config.AlphavantageAPIKey == "" {
+	if config.StockProvider == StockProviderAlphaVantage && config.AlphavantageAPIKey == "" {
 		missing = append(missing, "ALPHAVANTAGE_API_KEY")
 	}
+	if config.StockProvider == StockProviderFinnhub && config.FinnhubAPIKey == "" {
+		missing = append(missing, "FINNHUB_API_KEY")
+	}
+	if config.EthProvider == EthProviderRPC && config.EthRPCURL == "" {
+		missing = append(missing, "ETH_RPC_URL")
+	}
 	if config.RentcastAPIKey == "" {
 		missing = append(missing, "RENTCAST_API_KEY")
 	}
@@ -109,9 +370,153 @@ func Load() (*Config, error) {
 		missing = append(missing, "GUIDELINE_PASSWORD")
 	}
 
+	// Coinbase is optional, but a key without its secret (or the reverse) can't sign requests
+	if config.CoinbaseAPIKey != "" && config.CoinbaseAPISecret == "" {
+		missing = append(missing, "COINBASE_API_SECRET")
+	}
+	if config.CoinbaseAPISecret != "" && config.CoinbaseAPIKey == "" {
+		missing = append(missing, "COINBASE_API_KEY")
+	}
+
 	if len(missing) > 0 {
 		return nil, fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
 	}
 
+	if len(config.EthereumWallets) == 0 && len(config.StockSymbols) == 0 && len(config.Properties) == 0 && !config.CoinbaseEnabled() {
+		return nil, fmt.Errorf("no items configured to fetch: set ethereum_wallets, stock_symbols, or properties in config.yaml, or Coinbase credentials")
+	}
+
+	if config.AlphavantageRatePerMin <= 0 {
+		return nil, fmt.Errorf("ALPHAVANTAGE_RATE_PER_MIN must be positive, got %v", config.AlphavantageRatePerMin)
+	}
+
+	if config.EthPriceCacheTTL < 0 {
+		return nil, fmt.Errorf("ETH_PRICE_CACHE_TTL must not be negative, got %v", config.EthPriceCacheTTL)
+	}
+
+	if config.EthGasReserve < 0 {
+		return nil, fmt.Errorf("ETH_GAS_RESERVE must not be negative, got %v", config.EthGasReserve)
+	}
+
+	if config.RetryBudget < 0 {
+		return nil, fmt.Errorf("RETRY_BUDGET must not be negative, got %d", config.RetryBudget)
+	}
+
+	if config.MaxAllocationPct < 0 || config.MaxAllocationPct > 100 {
+		return nil, fmt.Errorf("MAX_ALLOCATION_PCT must be between 0 and 100, got %v", config.MaxAllocationPct)
+	}
+
+	if config.RunTimeout <= 0 {
+		return nil, fmt.Errorf("RUN_TIMEOUT must be positive, got %v", config.RunTimeout)
+	}
+
 	return config, nil
-}
\ No newline at end of file
+}
+
+// BaseURL returns the configured base URL for service (e.g. "finnhub"), or "" if the
+// service isn't known
+func (c *Config) BaseURL(service string) string {
+	return c.BaseURLs[service]
+}
+
+// CoinbaseEnabled reports whether Coinbase credentials are configured
+func (c *Config) CoinbaseEnabled() bool {
+	return c.CoinbaseAPIKey != "" && c.CoinbaseAPISecret != ""
+}
+
+// IsDisabled reports whether item, a wallet address or stock symbol, is listed in
+// DisabledItems. Matching ignores case, since neither addresses nor symbols are case-sensitive.
+func (c *Config) IsDisabled(item string) bool {
+	return slices.ContainsFunc(c.DisabledItems, func(disabled string) bool {
+		return strings.EqualFold(strings.TrimSpace(disabled), item)
+	})
+}
+
+// baseURLKey returns the config key holding service's base URL
+func baseURLKey(service string) string {
+	return service + "_base_url"
+}
+
+// labelsKey is the config key holding display names by result key
+const labelsKey = "labels"
+
+// readLabels converts the raw labels setting into a flat map. Any nested maps left by
+// viper splitting a key at its dots are joined back together with dots.
+func readLabels(raw any) (map[string]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	if _, ok := raw.(map[string]any); !ok {
+		return nil, fmt.Errorf("labels must map keys to names, got %T", raw)
+	}
+
+	labels := make(map[string]string)
+	var walk func(prefix string, value any) error
+	walk = func(prefix string, value any) error {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, nested := range v {
+				if prefix != "" {
+					key = prefix + "." + key
+				}
+				if err := walk(key, nested); err != nil {
+					return err
+				}
+			}
+		case string:
+			labels[strings.ToLower(prefix)] = v
+		default:
+			return fmt.Errorf("label for %q must be a string, got %T", prefix, value)
+		}
+		return nil
+	}
+
+	if err := walk("", raw); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// checkUnknownKeys returns an error listing any top-level keys that don't map to a Config field
+func checkUnknownKeys(v *viper.Viper) error {
+	known := make(map[string]bool)
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		known[configType.Field(i).Tag.Get("mapstructure")] = true
+	}
+	for service := range defaultBaseURLs {
+		known[baseURLKey(service)] = true
+	}
+	known[labelsKey] = true
+
+	var unknown []string
+	for key := range v.AllSettings() {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return fmt.Errorf("unknown configuration keys in %s: %s", v.ConfigFileUsed(), strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// readWatchlist reads stock symbols from path, one per line. Surrounding whitespace is
+// trimmed, and blank lines and comments (from "#" to the end of the line) are skipped.
+func readWatchlist(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read STOCK_SYMBOLS_FILE: %w", err)
+	}
+
+	var symbols []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		if symbol := strings.TrimSpace(line); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols, nil
+}
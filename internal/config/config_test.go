@@ -1,10 +1,33 @@
 package config
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 )
 
+// TestMain runs the package's tests from a directory whose config.yaml lists one item
+// to fetch, since Load rejects configurations with nothing to fetch
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "config-test")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("stock_symbols:\n  - AAPL\n"), 0o600); err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestLoad_Success(t *testing.T) {
 	// Set up environment variables
 	envVars := map[string]string{
@@ -46,6 +69,8 @@ func TestLoad_Success(t *testing.T) {
 		{"AlphavantageBaseURL", cfg.AlphavantageBaseURL, "https://test.alphavantage.co"},
 		{"RentcastBaseURL", cfg.RentcastBaseURL, "https://test.rentcast.io"},
 		{"GuidelineBaseURL", cfg.GuidelineBaseURL, "https://test.guideline.com"},
+		{"BaseURL(etherscan)", cfg.BaseURL("etherscan"), "https://test.etherscan.io"},
+		{"BaseURL(guideline)", cfg.BaseURL("guideline"), "https://test.guideline.com"},
 	}
 
 	for _, tt := range tests {
@@ -100,6 +125,8 @@ func TestLoad_WithDefaults(t *testing.T) {
 		{"AlphavantageBaseURL", cfg.AlphavantageBaseURL, "https://www.alphavantage.co/query"},
 		{"RentcastBaseURL", cfg.RentcastBaseURL, "https://api.rentcast.io/v1"},
 		{"GuidelineBaseURL", cfg.GuidelineBaseURL, "https://my.guideline.com"},
+		{"BaseURL(finnhub)", cfg.BaseURL("finnhub"), "https://finnhub.io/api/v1"},
+		{"BaseURL(unknown)", cfg.BaseURL("unknown"), ""},
 	}
 
 	for _, tt := range tests {
@@ -226,4 +253,754 @@ func contains(s, substr string) bool {
 			}
 			return false
 		}())
-}
\ No newline at end of file
+}
+
+func TestLoad_AlphavantageRateLimit(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	t.Run("defaults to free tier", func(t *testing.T) {
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() returned unexpected error: %v", err)
+		}
+
+		if cfg.AlphavantageRatePerMin != 5 {
+			t.Errorf("AlphavantageRatePerMin = %v, want 5", cfg.AlphavantageRatePerMin)
+		}
+		if cfg.AlphavantageBurst != 0 {
+			t.Errorf("AlphavantageBurst = %d, want 0", cfg.AlphavantageBurst)
+		}
+	})
+
+	t.Run("premium override", func(t *testing.T) {
+		os.Setenv("ALPHAVANTAGE_RATE_PER_MIN", "75")
+		os.Setenv("ALPHAVANTAGE_BURST", "5")
+		defer os.Unsetenv("ALPHAVANTAGE_RATE_PER_MIN")
+		defer os.Unsetenv("ALPHAVANTAGE_BURST")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() returned unexpected error: %v", err)
+		}
+
+		if cfg.AlphavantageRatePerMin != 75 {
+			t.Errorf("AlphavantageRatePerMin = %v, want 75", cfg.AlphavantageRatePerMin)
+		}
+		if cfg.AlphavantageBurst != 5 {
+			t.Errorf("AlphavantageBurst = %d, want 5", cfg.AlphavantageBurst)
+		}
+	})
+
+	t.Run("rejects non-positive rate", func(t *testing.T) {
+		os.Setenv("ALPHAVANTAGE_RATE_PER_MIN", "0")
+		defer os.Unsetenv("ALPHAVANTAGE_RATE_PER_MIN")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("Load() expected error for zero rate, got nil")
+		}
+		if !contains(err.Error(), "ALPHAVANTAGE_RATE_PER_MIN") {
+			t.Errorf("Load() error = %q, want error containing %q", err.Error(), "ALPHAVANTAGE_RATE_PER_MIN")
+		}
+	})
+}
+
+func TestLoad_EthPriceCacheTTL(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.EthPriceCacheTTL != 30*time.Second {
+		t.Errorf("EthPriceCacheTTL = %v, want 30s", cfg.EthPriceCacheTTL)
+	}
+
+	os.Setenv("ETH_PRICE_CACHE_TTL", "2m")
+	defer os.Unsetenv("ETH_PRICE_CACHE_TTL")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.EthPriceCacheTTL != 2*time.Minute {
+		t.Errorf("EthPriceCacheTTL = %v, want 2m", cfg.EthPriceCacheTTL)
+	}
+}
+
+func TestLoad_OutputLocale(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.OutputLocale != "en-US" {
+		t.Errorf("OutputLocale = %q, want %q", cfg.OutputLocale, "en-US")
+	}
+
+	os.Setenv("OUTPUT_LOCALE", "de-DE")
+	defer os.Unsetenv("OUTPUT_LOCALE")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.OutputLocale != "de-DE" {
+		t.Errorf("OutputLocale = %q, want %q", cfg.OutputLocale, "de-DE")
+	}
+}
+
+func TestLoad_SecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "etherscan_api_key")
+	if err := os.WriteFile(keyFile, []byte("file_etherscan_key\n"), 0o600); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+
+	envVars := map[string]string{
+		"ETHERSCAN_API_KEY_FILE": keyFile,
+		"ALPHAVANTAGE_API_KEY":   "test_alphavantage_key",
+		"RENTCAST_API_KEY":       "test_rentcast_key",
+		"GUIDELINE_EMAIL":        "test@example.com",
+		"GUIDELINE_PASSWORD":     "test_password",
+	}
+
+	os.Unsetenv("ETHERSCAN_API_KEY")
+	for key, value := range envVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	t.Run("reads key from file", func(t *testing.T) {
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() returned unexpected error: %v", err)
+		}
+		if cfg.EtherscanAPIKey != "file_etherscan_key" {
+			t.Errorf("EtherscanAPIKey = %q, want %q", cfg.EtherscanAPIKey, "file_etherscan_key")
+		}
+	})
+
+	t.Run("direct value takes precedence", func(t *testing.T) {
+		os.Setenv("ETHERSCAN_API_KEY", "env_etherscan_key")
+		defer os.Unsetenv("ETHERSCAN_API_KEY")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() returned unexpected error: %v", err)
+		}
+		if cfg.EtherscanAPIKey != "env_etherscan_key" {
+			t.Errorf("EtherscanAPIKey = %q, want %q", cfg.EtherscanAPIKey, "env_etherscan_key")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		os.Setenv("ETHERSCAN_API_KEY_FILE", filepath.Join(dir, "does_not_exist"))
+		defer os.Setenv("ETHERSCAN_API_KEY_FILE", keyFile)
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("Load() expected error for missing secret file, got nil")
+		}
+		if !contains(err.Error(), "ETHERSCAN_API_KEY_FILE") {
+			t.Errorf("Load() error = %q, want error containing %q", err.Error(), "ETHERSCAN_API_KEY_FILE")
+		}
+	})
+}
+
+func TestLoad_StockProvider(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":  "test_etherscan_key",
+		"RENTCAST_API_KEY":   "test_rentcast_key",
+		"GUIDELINE_EMAIL":    "test@example.com",
+		"GUIDELINE_PASSWORD": "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+	os.Unsetenv("ALPHAVANTAGE_API_KEY")
+
+	// Finnhub needs its own key instead of the AlphaVantage one
+	os.Setenv("STOCK_PROVIDER", "finnhub")
+	defer os.Unsetenv("STOCK_PROVIDER")
+
+	_, err := Load()
+	if err == nil || !contains(err.Error(), "FINNHUB_API_KEY") {
+		t.Fatalf("Load() error = %v, want error mentioning FINNHUB_API_KEY", err)
+	}
+	if contains(err.Error(), "ALPHAVANTAGE_API_KEY") {
+		t.Errorf("Load() error = %q, should not require ALPHAVANTAGE_API_KEY for finnhub", err.Error())
+	}
+
+	os.Setenv("FINNHUB_API_KEY", "test_finnhub_key")
+	defer os.Unsetenv("FINNHUB_API_KEY")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.StockProvider != StockProviderFinnhub {
+		t.Errorf("StockProvider = %q, want %q", cfg.StockProvider, StockProviderFinnhub)
+	}
+	if cfg.FinnhubBaseURL != "https://finnhub.io/api/v1" {
+		t.Errorf("FinnhubBaseURL = %q, want default", cfg.FinnhubBaseURL)
+	}
+
+	os.Setenv("STOCK_PROVIDER", "polygon")
+	if _, err := Load(); err == nil || !contains(err.Error(), "STOCK_PROVIDER") {
+		t.Errorf("Load() error = %v, want error mentioning STOCK_PROVIDER", err)
+	}
+}
+
+func TestLoad_UnknownKeys(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	writeConfig := func(t *testing.T, contents string) {
+		t.Helper()
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(contents), 0o600); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+		t.Chdir(dir)
+	}
+
+	t.Run("accepts known keys", func(t *testing.T) {
+		writeConfig(t, "stock_symbols:\n  - AAPL\noutput_locale: de-DE\n")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() returned unexpected error: %v", err)
+		}
+		if len(cfg.StockSymbols) != 1 || cfg.StockSymbols[0] != "AAPL" {
+			t.Errorf("StockSymbols = %v, want [AAPL]", cfg.StockSymbols)
+		}
+	})
+
+	t.Run("rejects misspelled keys", func(t *testing.T) {
+		writeConfig(t, "stock_symbol:\n  - AAPL\nethereum_wallet:\n  - 0xabc\n")
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("Load() expected error for unknown keys, got nil")
+		}
+		if !contains(err.Error(), "ethereum_wallet, stock_symbol") {
+			t.Errorf("Load() error = %q, want error listing the unknown keys", err.Error())
+		}
+	})
+}
+
+func TestLoad_NoItems(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	// An empty directory has no config.yaml, so every item list is empty
+	t.Chdir(t.TempDir())
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Load() expected error when nothing is configured to fetch, got nil")
+	}
+	if !contains(err.Error(), "no items configured to fetch") {
+		t.Errorf("Load() error = %q, want error containing %q", err.Error(), "no items configured to fetch")
+	}
+}
+
+func TestLoad_EthProvider(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.EthProvider != EthProviderEtherscan {
+		t.Errorf("EthProvider = %q, want %q", cfg.EthProvider, EthProviderEtherscan)
+	}
+
+	// The RPC provider needs an endpoint
+	os.Setenv("ETH_PROVIDER", "rpc")
+	defer os.Unsetenv("ETH_PROVIDER")
+
+	if _, err := Load(); err == nil || !contains(err.Error(), "ETH_RPC_URL") {
+		t.Fatalf("Load() error = %v, want error mentioning ETH_RPC_URL", err)
+	}
+
+	os.Setenv("ETH_RPC_URL", "https://eth-mainnet.example.com/v2/key")
+	defer os.Unsetenv("ETH_RPC_URL")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.EthRPCURL != "https://eth-mainnet.example.com/v2/key" {
+		t.Errorf("EthRPCURL = %q, want the configured endpoint", cfg.EthRPCURL)
+	}
+
+	os.Setenv("ETH_PROVIDER", "geth")
+	if _, err := Load(); err == nil || !contains(err.Error(), "ETH_PROVIDER") {
+		t.Errorf("Load() error = %v, want error mentioning ETH_PROVIDER", err)
+	}
+}
+
+func TestLoad_RunTimeout(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.RunTimeout != 30*time.Second {
+		t.Errorf("RunTimeout = %v, want 30s", cfg.RunTimeout)
+	}
+
+	os.Setenv("RUN_TIMEOUT", "2m")
+	defer os.Unsetenv("RUN_TIMEOUT")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.RunTimeout != 2*time.Minute {
+		t.Errorf("RunTimeout = %v, want 2m", cfg.RunTimeout)
+	}
+
+	for _, value := range []string{"0s", "-5s"} {
+		os.Setenv("RUN_TIMEOUT", value)
+		if _, err := Load(); err == nil || !contains(err.Error(), "RUN_TIMEOUT") {
+			t.Errorf("Load() with RUN_TIMEOUT=%s error = %v, want error mentioning RUN_TIMEOUT", value, err)
+		}
+	}
+}
+
+func TestLoad_MaxAllocationPct(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.MaxAllocationPct != 0 {
+		t.Errorf("MaxAllocationPct = %v, want 0 (disabled)", cfg.MaxAllocationPct)
+	}
+
+	os.Setenv("MAX_ALLOCATION_PCT", "40")
+	defer os.Unsetenv("MAX_ALLOCATION_PCT")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.MaxAllocationPct != 40 {
+		t.Errorf("MaxAllocationPct = %v, want 40", cfg.MaxAllocationPct)
+	}
+
+	os.Setenv("MAX_ALLOCATION_PCT", "150")
+	if _, err := Load(); err == nil || !contains(err.Error(), "MAX_ALLOCATION_PCT") {
+		t.Errorf("Load() error = %v, want error mentioning MAX_ALLOCATION_PCT", err)
+	}
+}
+
+func TestLoad_EthGasReserve(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	os.Setenv("ETH_GAS_RESERVE", "0.01")
+	defer os.Unsetenv("ETH_GAS_RESERVE")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.EthGasReserve != 0.01 {
+		t.Errorf("EthGasReserve = %v, want 0.01", cfg.EthGasReserve)
+	}
+
+	os.Setenv("ETH_GAS_RESERVE", "-1")
+	if _, err := Load(); err == nil {
+		t.Error("Load() expected error for negative ETH_GAS_RESERVE, got nil")
+	}
+}
+
+func TestLoad_StockSymbolsFile(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	dir := t.TempDir()
+	watchlist := filepath.Join(dir, "watchlist.txt")
+	contents := "# Tech\nAAPL\n  MSFT  \n\nGOOG # Alphabet\n\t\n# Energy\nXOM\n"
+	if err := os.WriteFile(watchlist, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write watchlist: %v", err)
+	}
+
+	// The shared fixture lists stock_symbols inline, so load from a config without them
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("ethereum_wallets:\n  - 0xabc\n"), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Chdir(dir)
+
+	os.Setenv("STOCK_SYMBOLS_FILE", watchlist)
+	defer os.Unsetenv("STOCK_SYMBOLS_FILE")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+
+	want := []string{"AAPL", "MSFT", "GOOG", "XOM"}
+	if !slices.Equal(cfg.StockSymbols, want) {
+		t.Errorf("StockSymbols = %v, want %v", cfg.StockSymbols, want)
+	}
+
+	os.Setenv("STOCK_SYMBOLS_FILE", filepath.Join(dir, "missing.txt"))
+	if _, err := Load(); err == nil || !contains(err.Error(), "STOCK_SYMBOLS_FILE") {
+		t.Errorf("Load() error = %v, want error mentioning STOCK_SYMBOLS_FILE", err)
+	}
+}
+
+func TestLoad_DisabledItems(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	dir := t.TempDir()
+	contents := `stock_symbols: [AAPL, MSFT]
+disabled_items: [msft, "0xABC"]
+properties:
+  - address: "1 Main St"
+  - address: "2 Main St"
+    enabled: false
+`
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+
+	for item, want := range map[string]bool{"AAPL": false, "MSFT": true, "0xabc": true} {
+		if got := cfg.IsDisabled(item); got != want {
+			t.Errorf("IsDisabled(%q) = %v, want %v", item, got, want)
+		}
+	}
+
+	if len(cfg.Properties) != 2 {
+		t.Fatalf("expected 2 properties, got %d", len(cfg.Properties))
+	}
+	if !cfg.Properties[0].IsEnabled() {
+		t.Error("expected a property without enabled to default to enabled")
+	}
+	if cfg.Properties[1].IsEnabled() {
+		t.Error("expected a property with enabled: false to be disabled")
+	}
+}
+
+func TestLoad_Coinbase(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	// Coinbase is off by default
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.CoinbaseEnabled() {
+		t.Error("CoinbaseEnabled() = true without credentials, want false")
+	}
+	if cfg.CoinbaseAccount != "default" {
+		t.Errorf("CoinbaseAccount = %q, want default", cfg.CoinbaseAccount)
+	}
+	if got := cfg.BaseURL("coinbase"); got != "https://api.coinbase.com" {
+		t.Errorf("BaseURL(coinbase) = %q, want https://api.coinbase.com", got)
+	}
+
+	// A key without its secret can't sign requests
+	os.Setenv("COINBASE_API_KEY", "test_coinbase_key")
+	defer os.Unsetenv("COINBASE_API_KEY")
+
+	_, err = Load()
+	if err == nil || !contains(err.Error(), "COINBASE_API_SECRET") {
+		t.Fatalf("Load() error = %v, want error mentioning COINBASE_API_SECRET", err)
+	}
+
+	os.Setenv("COINBASE_API_SECRET", "test_coinbase_secret")
+	defer os.Unsetenv("COINBASE_API_SECRET")
+	os.Setenv("COINBASE_ACCOUNT", "main")
+	defer os.Unsetenv("COINBASE_ACCOUNT")
+
+	// Credentials alone count as something to fetch
+	t.Chdir(t.TempDir())
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if !cfg.CoinbaseEnabled() {
+		t.Error("CoinbaseEnabled() = false with credentials, want true")
+	}
+	if cfg.CoinbaseAccount != "main" {
+		t.Errorf("CoinbaseAccount = %q, want main", cfg.CoinbaseAccount)
+	}
+}
+
+func TestLoad_SecretProvider(t *testing.T) {
+	secrets := map[string]string{
+		"ETHERSCAN_API_KEY":    "vault_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "vault_alphavantage_key",
+		"RENTCAST_API_KEY":     "vault_rentcast_key",
+		"GUIDELINE_EMAIL":      "vault@example.com",
+		"GUIDELINE_PASSWORD":   "vault_password",
+	}
+
+	var requested []string
+	provider := SecretProviderFunc(func(name string) (string, error) {
+		requested = append(requested, name)
+		return secrets[name], nil
+	})
+
+	// Values set directly still take precedence over the provider
+	os.Setenv("RENTCAST_API_KEY", "env_rentcast_key")
+	defer os.Unsetenv("RENTCAST_API_KEY")
+
+	cfg, err := Load(WithSecretProvider(provider))
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+
+	if cfg.EtherscanAPIKey != "vault_etherscan_key" {
+		t.Errorf("EtherscanAPIKey = %q, want %q", cfg.EtherscanAPIKey, "vault_etherscan_key")
+	}
+	if cfg.GuidelinePassword != "vault_password" {
+		t.Errorf("GuidelinePassword = %q, want %q", cfg.GuidelinePassword, "vault_password")
+	}
+	if cfg.RentcastAPIKey != "env_rentcast_key" {
+		t.Errorf("RentcastAPIKey = %q, want %q", cfg.RentcastAPIKey, "env_rentcast_key")
+	}
+	if slices.Contains(requested, "RENTCAST_API_KEY") {
+		t.Error("provider was asked for RENTCAST_API_KEY, which was set directly")
+	}
+
+	t.Run("provider error", func(t *testing.T) {
+		failing := SecretProviderFunc(func(name string) (string, error) {
+			return "", errors.New("vault sealed")
+		})
+
+		_, err := Load(WithSecretProvider(failing))
+		if err == nil {
+			t.Fatal("Load() expected error when the provider fails, got nil")
+		}
+		if !contains(err.Error(), "vault sealed") || !contains(err.Error(), "ETHERSCAN_API_KEY") {
+			t.Errorf("Load() error = %q, want it to name the secret and the provider's error", err.Error())
+		}
+	})
+}
+
+func TestLoad_Labels(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	dir := t.TempDir()
+	contents := `stock_symbols: [AAPL, BRK.B]
+labels:
+  "fetcher:alphavantage:AAPL": "Apple"
+  "fetcher:alphavantage:BRK.B": "Berkshire"
+  "fetcher:etherscan:0xABC": "Cold wallet"
+`
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"fetcher:alphavantage:aapl":  "Apple",
+		"fetcher:alphavantage:brk.b": "Berkshire",
+		"fetcher:etherscan:0xabc":    "Cold wallet",
+	}
+	if len(cfg.Labels) != len(want) {
+		t.Errorf("Labels = %v, want %v", cfg.Labels, want)
+	}
+	for key, label := range want {
+		if cfg.Labels[key] != label {
+			t.Errorf("Labels[%q] = %q, want %q", key, cfg.Labels[key], label)
+		}
+	}
+}
+
+func TestLoad_DedupKeys(t *testing.T) {
+	requiredVars := map[string]string{
+		"ETHERSCAN_API_KEY":    "test_etherscan_key",
+		"ALPHAVANTAGE_API_KEY": "test_alphavantage_key",
+		"RENTCAST_API_KEY":     "test_rentcast_key",
+		"GUIDELINE_EMAIL":      "test@example.com",
+		"GUIDELINE_PASSWORD":   "test_password",
+	}
+
+	for key, value := range requiredVars {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if cfg.DedupKeys {
+		t.Error("DedupKeys = true, want false by default")
+	}
+
+	os.Setenv("DEDUP_KEYS", "1")
+	defer os.Unsetenv("DEDUP_KEYS")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() returned unexpected error: %v", err)
+	}
+	if !cfg.DedupKeys {
+		t.Error("DedupKeys = false, want true with DEDUP_KEYS=1")
+	}
+}
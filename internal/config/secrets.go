@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SecretProvider supplies API keys and credentials by their environment variable name
+// (e.g. "ETHERSCAN_API_KEY"), so they can come from a secrets manager such as Vault or
+// AWS Secrets Manager instead of the environment. Load only asks for secrets that aren't
+// already set in the environment or config file.
+type SecretProvider interface {
+	// Get returns the secret called name, or "" if the provider doesn't have it
+	Get(name string) (string, error)
+}
+
+// SecretProviderFunc adapts an ordinary function to a SecretProvider
+type SecretProviderFunc func(name string) (string, error)
+
+// Get calls fn(name)
+func (fn SecretProviderFunc) Get(name string) (string, error) {
+	return fn(name)
+}
+
+// EnvSecretProvider reads secrets from the environment, and is the default. A secret is
+// taken from the variable called name, or else from the file named by name+"_FILE"
+// (the Docker/Kubernetes secrets convention), with surrounding whitespace trimmed.
+type EnvSecretProvider struct{}
+
+// Get returns the secret called name from the environment
+func (EnvSecretProvider) Get(name string) (string, error) {
+	if value := os.Getenv(name); value != "" {
+		return value, nil
+	}
+
+	path := os.Getenv(name + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s_FILE: %w", name, err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// LoadOption configures Load
+type LoadOption func(*loadOptions)
+
+// loadOptions holds the settings LoadOptions change
+type loadOptions struct {
+	secrets SecretProvider
+}
+
+// WithSecretProvider reads API keys and credentials that aren't set directly from
+// provider instead of the environment
+func WithSecretProvider(provider SecretProvider) LoadOption {
+	return func(o *loadOptions) {
+		o.secrets = provider
+	}
+}
+
+// loadSecret fills dest with the secret called name from provider, if dest is still empty
+func loadSecret(provider SecretProvider, name string, dest *string) error {
+	if *dest != "" {
+		return nil
+	}
+
+	value, err := provider.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to load secret %s: %w", name, err)
+	}
+
+	*dest = value
+	return nil
+}
@@ -3,54 +3,442 @@ package coordinator
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+	"financefetcher/internal/registry"
+
+	"github.com/shopspring/decimal"
 )
 
+// Storer records the value of each successful fetch. *registry.Registry satisfies it;
+// a persistent store (e.g. SQLite or Redis) can implement it to keep history across runs.
+type Storer interface {
+	Set(key string, value float64, ts time.Time)
+}
+
 // Coordinator manages concurrent fetchers and aggregates results
 type Coordinator struct {
-	fetchers []fetcher.Fetcher
+	fetchers  []fetcher.Fetcher
+	registry  *registry.Registry
+	formatter Formatter
+	out       io.Writer
+	fallback  FallbackStore
+	storer    Storer
+
+	// nonBlocking makes fetchers fail fast with a rate limit error instead of waiting on the limiter
+	nonBlocking bool
+
+	// failFast cancels the remaining fetchers on the first non-retryable error
+	failFast bool
+
+	// minValue hides successful results below this value; zero disables the filter
+	minValue float64
+
+	// staleTotals counts the last known value of failed fetchers toward the total
+	staleTotals bool
+
+	// timing records each fetcher's start, end, and rate limiter wait in the RunSummary
+	timing bool
+
+	// onResult is called with each result as it is drained; nil disables it
+	onResult func(fetcher.Result)
+
+	// retryBudget caps retries per API host across all fetchers in a run; zero disables it
+	retryBudget int
+
+	// cycleTimeout bounds each RunEvery cycle; zero leaves cycles without a deadline
+	cycleTimeout time.Duration
+
+	// labels maps lowercased keys to display names set in config; nil disables it
+	labels map[string]string
+
+	// groupErrors holds failed results back and prints them together after the successes
+	groupErrors bool
+
+	// telemetry observes each fetch, e.g. to trace it; nil disables it
+	telemetry Telemetry
+}
+
+// Option configures a Coordinator at construction time
+type Option func(*Coordinator)
+
+// WithDedupKeys collapses fetchers that share a Key(), keeping the first and logging a
+// warning for each dropped duplicate. Without it, duplicates run as configured.
+func WithDedupKeys() Option {
+	return func(c *Coordinator) {
+		c.fetchers = dedupByKey(c.fetchers)
+	}
+}
+
+// New creates a new Coordinator with the given fetchers.
+// Nil fetchers are dropped with a warning rather than panicking during a run.
+func New(fetchers []fetcher.Fetcher, opts ...Option) *Coordinator {
+	c := &Coordinator{
+		fetchers:  dropNil(fetchers),
+		formatter: TextFormatter{CurrencySymbol: "$", Locale: LocaleEnUS},
+		out:       os.Stdout,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// dropNil returns fetchers without nil entries, including typed nil pointers such as a
+// (*etherscan.WalletFetcher)(nil) returned by a constructor, logging a warning for each
+func dropNil(fetchers []fetcher.Fetcher) []fetcher.Fetcher {
+	valid := make([]fetcher.Fetcher, 0, len(fetchers))
+
+	for i, f := range fetchers {
+		if isNilFetcher(f) {
+			slog.Warn("dropping nil fetcher", "index", i)
+			continue
+		}
+		valid = append(valid, f)
+	}
+
+	return valid
+}
+
+// isNilFetcher reports whether f is nil or an interface holding a nil pointer
+func isNilFetcher(f fetcher.Fetcher) bool {
+	if f == nil {
+		return true
+	}
+	v := reflect.ValueOf(f)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// dedupByKey returns fetchers with later duplicates of each Key() removed
+func dedupByKey(fetchers []fetcher.Fetcher) []fetcher.Fetcher {
+	seen := make(map[string]bool, len(fetchers))
+	unique := make([]fetcher.Fetcher, 0, len(fetchers))
+
+	for _, f := range fetchers {
+		key := f.Key()
+		if seen[key] {
+			slog.Warn("dropping fetcher with duplicate key", "key", key)
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, f)
+	}
+
+	return unique
+}
+
+// SetFormatter sets the formatter used to render each result (defaults to TextFormatter with "$" and en-US)
+func (c *Coordinator) SetFormatter(f Formatter) {
+	c.formatter = f
+}
+
+// SetOutput sets where formatted results are written (defaults to os.Stdout)
+func (c *Coordinator) SetOutput(w io.Writer) {
+	c.out = w
+}
+
+// SetFallbackStore sets a store consulted when a fetch fails with a retryable error.
+// If it holds a value for the fetcher's key, that value is reported as a stale result
+// instead of an error, keeping dashboards populated during API outages.
+func (c *Coordinator) SetFallbackStore(s FallbackStore) {
+	c.fallback = s
+}
+
+// SetStaleTotals enables or disables stale totals. Retryable failures are already served
+// from the fallback store; when enabled, any other failure whose key has a last known value
+// there is still reported as an error, but that value is added to the run's total so one
+// flaky asset doesn't swing the portfolio sum. Failures with no stored value stay excluded.
+func (c *Coordinator) SetStaleTotals(enabled bool) {
+	c.staleTotals = enabled
+}
+
+// SetTelemetry reports every fetch to t, e.g. telemetry.New to export OpenTelemetry
+// spans and metrics. Nil disables telemetry (the default).
+func (c *Coordinator) SetTelemetry(t Telemetry) {
+	c.telemetry = t
+}
+
+// SetRegistry sets a registry that is updated with the value of each successful fetch.
+// The registry outlives individual runs, so it always holds the latest value per key.
+func (c *Coordinator) SetRegistry(r *registry.Registry) {
+	c.registry = r
+}
+
+// SetStorer sets a store that records the value of each successful fetch, e.g. a
+// sqlitestore.Store keeping a history of every run. Results hidden by SetMinValue and
+// stale values served from the fallback store are not recorded.
+func (c *Coordinator) SetStorer(s Storer) {
+	c.storer = s
+}
+
+// SetOnResult sets a callback invoked with each result as it arrives, in addition to
+// printing it, so live integrations don't have to parse the output. Every result is
+// passed, including errors and values hidden by SetMinValue. The callback runs
+// synchronously on the single goroutine that drains results, so it needs no locking of
+// its own, but a slow callback delays every result behind it and the end of the run.
+func (c *Coordinator) SetOnResult(fn func(fetcher.Result)) {
+	c.onResult = fn
+}
+
+// SetNonBlocking enables or disables non-blocking mode. When enabled, a fetcher that would
+// have to wait for the rate limiter fails immediately with an ErrorTypeRateLimit FetchError,
+// which suits interactive callers that would rather skip a value than stall.
+func (c *Coordinator) SetNonBlocking(nonBlocking bool) {
+	c.nonBlocking = nonBlocking
+}
+
+// SetFailFast enables or disables fail-fast mode. When enabled, the first fetcher to return
+// a non-retryable error cancels the context shared by the remaining fetchers, and Run returns
+// that error once they have stopped. Retryable errors are reported as usual and never trip it.
+func (c *Coordinator) SetFailFast(failFast bool) {
+	c.failFast = failFast
+}
+
+// SetMinValue hides successful results whose value is below threshold: they are neither
+// printed nor recorded in the registry, which declutters portfolios with dust balances.
+// Errors are always shown, and hidden values still count toward the RunSummary.
+// A threshold of zero (the default) disables the filter.
+func (c *Coordinator) SetMinValue(threshold float64) {
+	c.minValue = threshold
+}
+
+// SetTiming enables or disables per-fetcher timing. When enabled, RunSummary.Timings
+// records each fetcher's start and end time, total duration, and how much of it was spent
+// waiting on the rate limiter, to show which source dominates a run and whether it is
+// throttled or slow to respond.
+func (c *Coordinator) SetTiming(enabled bool) {
+	c.timing = enabled
+}
+
+// SetRetryBudget caps how many retries all fetchers together may make against each API
+// host in a single run. Once a host's budget is spent, further failures against it return
+// immediately instead of retrying, so an outage doesn't multiply load on an already
+// struggling API. Zero (the default) leaves retries unlimited.
+func (c *Coordinator) SetRetryBudget(perSource int) {
+	c.retryBudget = perSource
+}
+
+// SetCycleTimeout bounds each cycle RunEvery starts, so one hung request can't keep a
+// cycle running forever and make every later tick skip. Fetchers still in flight when the
+// timeout passes fail with a timeout error, and the cycle's deadline lets the rate limiter
+// give up early on waits that couldn't finish in time. Zero (the default) leaves cycles
+// bounded only by RunEvery's context.
+func (c *Coordinator) SetCycleTimeout(timeout time.Duration) {
+	c.cycleTimeout = timeout
+}
+
+// SetGroupErrors enables or disables the grouped error report. When enabled, failed
+// results are not printed as they arrive; successes are printed first, then an "Errors:"
+// section lists each failure with its error type and message, sorted by key, which is
+// easier to scan than interleaved output when several fetchers fail. A failure counted
+// at its last known value (see SetStaleTotals) is still printed with the successes, so
+// the value it adds to the total and its age stay visible. A BatchFormatter already
+// prints every result together and is unaffected.
+func (c *Coordinator) SetGroupErrors(enabled bool) {
+	c.groupErrors = enabled
 }
 
-// New creates a new Coordinator with the given fetchers
-func New(fetchers []fetcher.Fetcher) *Coordinator {
-	return &Coordinator{
-		fetchers: fetchers,
+// SetLabels sets display names by key, e.g. from the config file's labels map, so output
+// can name a wallet or ticker without code changes. A label here takes precedence over
+// one the fetcher carries; unlabeled keys print as themselves. Keys match regardless of
+// case, since config keys are lowercased when loaded.
+func (c *Coordinator) SetLabels(labels map[string]string) {
+	c.labels = make(map[string]string, len(labels))
+	for key, label := range labels {
+		c.labels[strings.ToLower(key)] = label
 	}
 }
 
-// Run executes all fetchers concurrently and prints results to stdout
+// labelFor returns the display name for f: its configured label, else its own label
+func (c *Coordinator) labelFor(f fetcher.Fetcher) string {
+	if label, ok := c.labels[strings.ToLower(f.Key())]; ok && label != "" {
+		return label
+	}
+	return fetcher.LabelOf(f)
+}
+
+// Run executes all fetchers concurrently and prints results to the output writer
 // Each fetcher runs in its own goroutine and sends results to a shared channel
-// Results are printed as they arrive in the format:
+// Results are printed as they arrive using the configured Formatter, by default:
 //   - Success: "KEY: $VALUE"
 //   - Error: "KEY: ERROR - error message"
 func (c *Coordinator) Run(ctx context.Context) error {
+	_, err := c.runOnce(ctx)
+	return err
+}
+
+// RunWithSummary behaves like Run but also returns a RunSummary of the run,
+// so programmatic callers get totals and results without re-scanning output
+func (c *Coordinator) RunWithSummary(ctx context.Context) (RunSummary, error) {
+	return c.runOnce(ctx)
+}
+
+// RunEvery runs all fetchers immediately and then once per interval until ctx is cancelled.
+// If a cycle is still in progress when the next tick fires, that tick is skipped so cycles
+// never overlap (and never stack up behind the rate limiter). Each cycle is bounded by the
+// SetCycleTimeout timeout, if set. A summary is logged per cycle.
+// It returns nil once ctx is cancelled and the in-flight cycle has finished.
+func (c *Coordinator) RunEvery(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %v", interval)
+	}
 	if len(c.fetchers) == 0 {
 		return fmt.Errorf("no fetchers configured")
 	}
 
+	var running atomic.Bool
+	var wg sync.WaitGroup
+
+	runCycle := func(cycle int) {
+		if !running.CompareAndSwap(false, true) {
+			slog.Warn("skipping fetch cycle, previous cycle still in progress", "cycle", cycle)
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer running.Store(false)
+
+			cycleCtx := ctx
+			if c.cycleTimeout > 0 {
+				var cancel context.CancelFunc
+				cycleCtx, cancel = context.WithTimeout(ctx, c.cycleTimeout)
+				defer cancel()
+			}
+
+			runID := fetcher.NewRunID()
+			summary, err := c.runOnce(fetcher.WithRunID(cycleCtx, runID))
+			if err != nil {
+				slog.Error("fetch cycle failed", "cycle", cycle, "run_id", runID, "error", err)
+				return
+			}
+
+			slog.Info("fetch cycle complete",
+				"cycle", cycle,
+				"run_id", runID,
+				"succeeded", summary.SuccessCount,
+				"failed", summary.FailureCount,
+				"duration", summary.Duration)
+		}()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	cycle := 1
+	runCycle(cycle)
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return nil
+		case <-ticker.C:
+			cycle++
+			runCycle(cycle)
+		}
+	}
+}
+
+// runOnce executes a single fetch cycle and summarizes its results
+func (c *Coordinator) runOnce(ctx context.Context) (RunSummary, error) {
+	if len(c.fetchers) == 0 {
+		return RunSummary{}, fmt.Errorf("no fetchers configured")
+	}
+
+	start := time.Now()
+	summary := RunSummary{StartedAt: start, Results: make([]fetcher.Result, 0, len(c.fetchers))}
+
+	if c.nonBlocking {
+		ctx = ratelimit.WithNonBlocking(ctx)
+	}
+
+	// Each run starts with a fresh retry budget
+	if c.retryBudget > 0 {
+		ctx = fetcher.WithRetryBudget(ctx, fetcher.NewRetryBudget(c.retryBudget))
+	}
+
+	// Tag every log line from this cycle so concurrent cycles can be told apart
+	if fetcher.RunIDFromContext(ctx) == "" {
+		ctx = fetcher.WithRunID(ctx, fetcher.NewRunID())
+	}
+
+	// Fetchers compare rate limiter waits against this deadline and give up early
+	if deadline, ok := ctx.Deadline(); ok {
+		slog.Debug("starting fetch cycle", "run_id", fetcher.RunIDFromContext(ctx), "budget", time.Until(deadline))
+	}
+
+	// Fail-fast mode cancels in-flight fetchers through this context
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var failErr error
+
 	// Create a channel for collecting results
 	resultChan := make(chan fetcher.Result, len(c.fetchers))
 
 	// WaitGroup to track all worker goroutines
 	var wg sync.WaitGroup
 
+	// Timings are written by the workers and only read once they have all finished
+	var timingsMu sync.Mutex
+	if c.timing {
+		summary.Timings = make(map[string]FetchTiming, len(c.fetchers))
+	}
+
 	// Launch a goroutine for each fetcher
 	for _, f := range c.fetchers {
 		wg.Add(1)
 		go func(ft fetcher.Fetcher) {
 			defer wg.Done()
 
+			fetchCtx := ctx
+			var waits *ratelimit.WaitTracker
+			if c.timing {
+				fetchCtx, waits = ratelimit.TrackWaits(ctx)
+			}
+
 			// Execute the fetch operation
-			value, err := ft.Fetch(ctx)
+			start := time.Now()
+			value, err := c.fetch(fetchCtx, ft)
 
-			// Send result to the channel
-			resultChan <- fetcher.Result{
+			if c.timing {
+				end := time.Now()
+				timingsMu.Lock()
+				summary.Timings[ft.Key()] = FetchTiming{
+					Start:         start,
+					End:           end,
+					Duration:      end.Sub(start),
+					RateLimitWait: waits.Total(),
+				}
+				timingsMu.Unlock()
+			}
+
+			result := fetcher.Result{
 				Key:   ft.Key(),
+				Label: c.labelFor(ft),
 				Value: value,
 				Error: err,
 			}
+			if change, ok := fetcher.ChangeOf(ft); ok && err == nil {
+				result.PrevValue = &change.PrevValue
+				result.Delta = change.Delta
+				result.DeltaPct = change.DeltaPct
+			}
+
+			// Send result to the channel
+			resultChan <- c.withFallback(ctx, result)
 		}(f)
 	}
 
@@ -60,14 +448,74 @@ func (c *Coordinator) Run(ctx context.Context) error {
 		close(resultChan)
 	}()
 
-	// Collect and print results as they arrive
+	// A batch formatter prints every result at the end instead of as each arrives
+	batch, batched := c.formatter.(BatchFormatter)
+	var printed []fetcher.Result
+
+	// Grouped errors are printed in their own section once every success has been. Stale
+	// failures count toward the total, so they print with the successes.
+	groupErrors := c.groupErrors && !batched
+	var failed []fetcher.Result
+
+	// Collect and print results as they arrive. The loop ignores ctx and drains until every
+	// worker has reported, so results completed before a cancellation are still printed.
 	for result := range resultChan {
+		summary.Results = append(summary.Results, result)
+
+		if c.onResult != nil {
+			c.onResult(result)
+		}
+
+		hidden := result.Error == nil && c.minValue > 0 && result.Value < c.minValue
+		if !hidden {
+			if batched {
+				printed = append(printed, result)
+			} else if groupErrors && result.Error != nil && !result.Stale {
+				failed = append(failed, result)
+			} else {
+				fmt.Fprintln(c.out, c.formatter.Format(result))
+			}
+		}
+
 		if result.Error != nil {
-			fmt.Printf("%s: ERROR - %v\n", result.Key, result.Error)
-		} else {
-			fmt.Printf("%s: $%.2f\n", result.Key, result.Value)
+			summary.FailureCount++
+			if c.failFast && failErr == nil && !fetcher.IsRetryable(result.Error) {
+				failErr = fmt.Errorf("%s: %w", result.Key, result.Error)
+				cancel()
+			}
+			if result.Stale && !fetcher.IsRateKey(result.Key) {
+				summary.StaleTotalCount++
+				summary.ExactTotal = summary.ExactTotal.Add(decimal.NewFromFloat(result.Value))
+			}
+			continue
+		}
+
+		// A stale value is already in the store; re-recording it would make it look fresh
+		if result.Stale {
+			summary.StaleCount++
+		} else if !hidden {
+			now := time.Now()
+			if c.registry != nil {
+				c.registry.Set(result.Key, result.Value, now)
+			}
+			if c.storer != nil {
+				c.storer.Set(result.Key, result.Value, now)
+			}
+		}
+		summary.SuccessCount++
+		if !fetcher.IsRateKey(result.Key) {
+			summary.ExactTotal = summary.ExactTotal.Add(decimal.NewFromFloat(result.Value))
 		}
 	}
 
-	return nil
-}
\ No newline at end of file
+	if batched && len(printed) > 0 {
+		fmt.Fprintln(c.out, batch.FormatAll(printed))
+	}
+	if len(failed) > 0 {
+		fmt.Fprint(c.out, formatErrorReport(failed))
+	}
+
+	summary.Total = summary.ExactTotal.InexactFloat64()
+	summary.Duration = time.Since(start)
+	return summary, failErr
+}
@@ -3,13 +3,25 @@ package coordinator
 import (
 	"context"
 	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+	"financefetcher/internal/registry"
 	"financefetcher/internal/testutil"
 )
 
+// Run this package's tests without real API rate limits
+func init() {
+	ratelimit.SetTestMode(true)
+}
+
 func TestNew(t *testing.T) {
 	fetchers := []fetcher.Fetcher{
 		testutil.NewMockFetcher("test:key1", 100.0, nil),
@@ -108,6 +120,55 @@ func TestRun_ContextCancellation(t *testing.T) {
 	}
 }
 
+func TestRun_ContextCancellation_FlushesCompletedResults(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel once both fast fetchers have finished, while the slow one is still running
+	var fastDone sync.WaitGroup
+	fastDone.Add(2)
+	go func() {
+		fastDone.Wait()
+		cancel()
+	}()
+
+	fast := func(key string, value float64) fetcher.Fetcher {
+		return &testutil.MockFetcher{
+			FetchFunc: func(ctx context.Context) (float64, error) {
+				defer fastDone.Done()
+				return value, nil
+			},
+			KeyFunc: func() string { return key },
+		}
+	}
+	slowFetcher := &testutil.MockFetcher{
+		FetchFunc: func(ctx context.Context) (float64, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		},
+		KeyFunc: func() string { return "test:slow" },
+	}
+
+	var out strings.Builder
+	coord := New([]fetcher.Fetcher{fast("test:fast1", 100), fast("test:fast2", 200), slowFetcher})
+	coord.SetOutput(&out)
+	coord.SetFormatter(TextFormatter{CurrencySymbol: "$"})
+
+	summary, err := coord.RunWithSummary(ctx)
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"test:fast1: $100.00", "test:fast2: $200.00", "test:slow: ERROR"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q after cancellation:\n%s", want, out.String())
+		}
+	}
+	if summary.SuccessCount != 2 || summary.FailureCount != 1 {
+		t.Errorf("summary = %d succeeded, %d failed, want 2 and 1", summary.SuccessCount, summary.FailureCount)
+	}
+}
+
 func TestRun_ConcurrentExecution(t *testing.T) {
 	// Create fetchers that track execution order
 	executionOrder := make(chan string, 3)
@@ -168,4 +229,583 @@ func TestRun_ConcurrentExecution(t *testing.T) {
 
 	// Note: We don't check the order because concurrent execution
 	// means fetcher3 (fastest) should complete first, demonstrating concurrency
-}
\ No newline at end of file
+}
+
+func TestRun_UpdatesRegistry(t *testing.T) {
+	fetchers := []fetcher.Fetcher{
+		testutil.NewMockFetcher("test:key1", 100.50, nil),
+		testutil.NewMockFetcher("test:key2", 0, errors.New("fetch failed")),
+	}
+
+	reg := registry.New()
+	coord := New(fetchers)
+	coord.SetRegistry(reg)
+
+	if err := coord.Run(context.Background()); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+
+	entry, ok := reg.Get("test:key1")
+	if !ok {
+		t.Fatal("registry missing entry for successful fetch")
+	}
+	if entry.Value != 100.50 {
+		t.Errorf("registry value = %.2f, want 100.50", entry.Value)
+	}
+
+	if _, ok := reg.Get("test:key2"); ok {
+		t.Error("registry should not contain an entry for a failed fetch")
+	}
+}
+
+func TestRunEvery_RepeatsUntilCancelled(t *testing.T) {
+	var calls atomic.Int32
+
+	counting := &testutil.MockFetcher{
+		FetchFunc: func(ctx context.Context) (float64, error) {
+			calls.Add(1)
+			return 100.0, nil
+		},
+		KeyFunc: func() string {
+			return "test:counting"
+		},
+	}
+
+	coord := New([]fetcher.Fetcher{counting})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 110*time.Millisecond)
+	defer cancel()
+
+	if err := coord.RunEvery(ctx, 25*time.Millisecond); err != nil {
+		t.Fatalf("RunEvery() returned unexpected error: %v", err)
+	}
+
+	// One immediate run plus roughly one per 25ms tick
+	if got := calls.Load(); got < 3 {
+		t.Errorf("fetcher called %d times, want at least 3", got)
+	}
+}
+
+func TestRunEvery_SkipsOverlappingCycles(t *testing.T) {
+	var calls atomic.Int32
+	var inFlight atomic.Int32
+
+	slow := &testutil.MockFetcher{
+		FetchFunc: func(ctx context.Context) (float64, error) {
+			if inFlight.Add(1) > 1 {
+				t.Error("fetch cycles overlapped")
+			}
+			defer inFlight.Add(-1)
+
+			calls.Add(1)
+			time.Sleep(60 * time.Millisecond)
+			return 100.0, nil
+		},
+		KeyFunc: func() string {
+			return "test:slow"
+		},
+	}
+
+	coord := New([]fetcher.Fetcher{slow})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	if err := coord.RunEvery(ctx, 10*time.Millisecond); err != nil {
+		t.Fatalf("RunEvery() returned unexpected error: %v", err)
+	}
+
+	// Ticks every 10ms would start ~15 cycles if none were skipped
+	if got := calls.Load(); got > 4 {
+		t.Errorf("fetcher called %d times, want at most 4 with skipped ticks", got)
+	}
+}
+
+func TestRunEvery_CycleTimeout(t *testing.T) {
+	var calls atomic.Int32
+
+	// Hangs until its context ends, like a request to an unresponsive API
+	hung := &testutil.MockFetcher{
+		FetchFunc: func(ctx context.Context) (float64, error) {
+			calls.Add(1)
+			<-ctx.Done()
+			return 0, fetcher.ClassifyLimiterError(ctx.Err())
+		},
+		KeyFunc: func() string {
+			return "test:hung"
+		},
+	}
+
+	coord := New([]fetcher.Fetcher{hung})
+	coord.SetOutput(io.Discard)
+	coord.SetCycleTimeout(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	if err := coord.RunEvery(ctx, 30*time.Millisecond); err != nil {
+		t.Fatalf("RunEvery() returned unexpected error: %v", err)
+	}
+
+	// Without the timeout the first cycle would hang and every later tick would skip
+	if got := calls.Load(); got < 3 {
+		t.Errorf("fetcher called %d times, want at least 3 cycles after timeouts", got)
+	}
+}
+
+func TestRunEvery_InvalidInterval(t *testing.T) {
+	coord := New([]fetcher.Fetcher{testutil.NewMockFetcher("test:key", 1, nil)})
+
+	if err := coord.RunEvery(context.Background(), 0); err == nil {
+		t.Error("RunEvery() expected error for zero interval, got nil")
+	}
+}
+
+// limitedFetcher waits on the shared rate limiter before returning, like the real fetchers
+type limitedFetcher struct {
+	key string
+	api ratelimit.API
+}
+
+func (f *limitedFetcher) Fetch(ctx context.Context) (float64, error) {
+	if err := ratelimit.GetLimiter().Wait(ctx, f.api); err != nil {
+		return 0, fetcher.ClassifyLimiterError(err)
+	}
+	return 1, nil
+}
+
+func (f *limitedFetcher) Key() string {
+	return f.key
+}
+
+func TestRunWithSummary_Timing(t *testing.T) {
+	api := ratelimit.API("coordinator-timing-test")
+	ratelimit.GetLimiter().Configure(api, 600, 1)
+
+	slowFetcher := &testutil.MockFetcher{
+		FetchFunc: func(ctx context.Context) (float64, error) {
+			time.Sleep(50 * time.Millisecond)
+			return 1, nil
+		},
+		KeyFunc: func() string { return "test:slow" },
+	}
+
+	coord := New([]fetcher.Fetcher{
+		&limitedFetcher{key: "test:limited1", api: api},
+		&limitedFetcher{key: "test:limited2", api: api},
+		slowFetcher,
+	})
+	coord.SetOutput(io.Discard)
+
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+	if summary.Timings != nil {
+		t.Errorf("Timings = %v without SetTiming, want nil", summary.Timings)
+	}
+
+	coord.SetTiming(true)
+	summary, err = coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+	if len(summary.Timings) != 3 {
+		t.Fatalf("len(Timings) = %d, want 3", len(summary.Timings))
+	}
+
+	// The two limited fetchers share one token every 100ms, so one of them waits
+	limitedWait := summary.Timings["test:limited1"].RateLimitWait + summary.Timings["test:limited2"].RateLimitWait
+	if limitedWait < 50*time.Millisecond {
+		t.Errorf("limited fetchers waited %v in total, want at least 50ms", limitedWait)
+	}
+
+	slow := summary.Timings["test:slow"]
+	if slow.RateLimitWait != 0 || slow.RequestTime() < 40*time.Millisecond {
+		t.Errorf("slow timing = %+v, want ~50ms of request time and no rate limit wait", slow)
+	}
+	if slow.End.Sub(slow.Start) != slow.Duration {
+		t.Errorf("Duration = %v, want End - Start = %v", slow.Duration, slow.End.Sub(slow.Start))
+	}
+}
+
+func TestRun_NonBlocking(t *testing.T) {
+	api := ratelimit.API("coordinator-nonblocking-test")
+	ratelimit.GetLimiter().Configure(api, 1, 1)
+
+	var results []fetcher.Result
+	coord := New([]fetcher.Fetcher{
+		&limitedFetcher{key: "test:key1", api: api},
+		&limitedFetcher{key: "test:key2", api: api},
+	})
+	coord.SetFormatter(formatterFunc(func(r fetcher.Result) string {
+		results = append(results, r)
+		return r.Key
+	}))
+	coord.SetNonBlocking(true)
+
+	start := time.Now()
+	if err := coord.Run(context.Background()); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("non-blocking Run() took %v, want it not to wait on the limiter", elapsed)
+	}
+
+	var rateLimited int
+	for _, r := range results {
+		if fetcher.ErrorTypeOf(r.Error) == fetcher.ErrorTypeRateLimit {
+			rateLimited++
+		}
+	}
+	if rateLimited != 1 {
+		t.Errorf("rate limited results = %d, want 1 (one token available)", rateLimited)
+	}
+}
+
+// runIDFetcher records the run ID it sees in its context
+type runIDFetcher struct {
+	key  string
+	mu   sync.Mutex
+	seen []string
+}
+
+func (f *runIDFetcher) Fetch(ctx context.Context) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seen = append(f.seen, fetcher.RunIDFromContext(ctx))
+	return 1, nil
+}
+
+func (f *runIDFetcher) Key() string {
+	return f.key
+}
+
+func TestRun_AttachesRunID(t *testing.T) {
+	first := &runIDFetcher{key: "test:key1"}
+	second := &runIDFetcher{key: "test:key2"}
+	coord := New([]fetcher.Fetcher{first, second})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := coord.Run(ctx); err != nil {
+			t.Fatalf("Run() returned unexpected error: %v", err)
+		}
+	}
+
+	if first.seen[0] == "" {
+		t.Fatal("fetcher context has no run ID")
+	}
+	if first.seen[0] != second.seen[0] {
+		t.Errorf("fetchers in the same run saw different run IDs: %q and %q", first.seen[0], second.seen[0])
+	}
+	if first.seen[0] == first.seen[1] {
+		t.Errorf("consecutive runs shared run ID %q", first.seen[0])
+	}
+
+	// A run ID supplied by the caller is kept
+	if err := coord.Run(fetcher.WithRunID(ctx, "caller-run")); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+	if got := first.seen[2]; got != "caller-run" {
+		t.Errorf("run ID = %q, want caller-supplied %q", got, "caller-run")
+	}
+}
+
+func TestNew_DropsNilFetchers(t *testing.T) {
+	var typedNil *testutil.MockFetcher
+
+	coord := New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("test:key1", 100, nil),
+		nil,
+		typedNil,
+	})
+	coord.SetOutput(io.Discard)
+
+	if got := len(coord.fetchers); got != 1 {
+		t.Fatalf("len(fetchers) = %d, want 1 after dropping nil entries", got)
+	}
+
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+	if summary.SuccessCount != 1 {
+		t.Errorf("SuccessCount = %d, want 1", summary.SuccessCount)
+	}
+}
+
+func TestNew_DedupKeys(t *testing.T) {
+	fetchers := []fetcher.Fetcher{
+		testutil.NewMockFetcher("test:key1", 100.0, nil),
+		testutil.NewMockFetcher("test:key2", 200.0, nil),
+		testutil.NewMockFetcher("test:key1", 300.0, nil),
+	}
+
+	coord := New(fetchers, WithDedupKeys())
+	if len(coord.fetchers) != 2 {
+		t.Fatalf("len(fetchers) = %d, want 2", len(coord.fetchers))
+	}
+
+	// The first fetcher for a key is kept
+	value, _ := coord.fetchers[0].Fetch(context.Background())
+	if coord.fetchers[0].Key() != "test:key1" || value != 100.0 {
+		t.Errorf("fetchers[0] = %s (%v), want test:key1 (100)", coord.fetchers[0].Key(), value)
+	}
+
+	// Without the option, intentional duplicates are preserved
+	if got := len(New(fetchers).fetchers); got != 3 {
+		t.Errorf("len(fetchers) without dedup = %d, want 3", got)
+	}
+}
+
+func TestRun_FailFast(t *testing.T) {
+	authErr := fetcher.NewClientError(401, "invalid api key")
+
+	slowFetcher := &testutil.MockFetcher{
+		FetchFunc: func(ctx context.Context) (float64, error) {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return 100.0, nil
+			}
+		},
+		KeyFunc: func() string {
+			return "test:slow"
+		},
+	}
+
+	coord := New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("test:auth", 0, authErr),
+		slowFetcher,
+	})
+	coord.SetOutput(io.Discard)
+	coord.SetFailFast(true)
+
+	start := time.Now()
+	err := coord.Run(context.Background())
+	if !errors.Is(err, authErr) {
+		t.Fatalf("Run() error = %v, want %v", err, authErr)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Run() took %v, want remaining fetchers cancelled promptly", elapsed)
+	}
+}
+
+func TestRun_FailFast_IgnoresRetryableErrors(t *testing.T) {
+	coord := New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("test:down", 0, fetcher.NewServerError(503)),
+		testutil.NewMockFetcher("test:ok", 100.0, nil),
+	})
+	coord.SetOutput(io.Discard)
+	coord.SetFailFast(true)
+
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+	if summary.SuccessCount != 1 || summary.FailureCount != 1 {
+		t.Errorf("summary = %d succeeded, %d failed, want 1 and 1", summary.SuccessCount, summary.FailureCount)
+	}
+}
+
+func TestRun_Storer(t *testing.T) {
+	store := registry.New()
+
+	coord := New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("test:ok", 100, nil),
+		testutil.NewMockFetcher("test:fail", 0, fetcher.NewClientError(401, "invalid api key")),
+	})
+	coord.SetOutput(io.Discard)
+	coord.SetStorer(store)
+
+	if err := coord.Run(context.Background()); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+
+	if entry, ok := store.Get("test:ok"); !ok || entry.Value != 100 {
+		t.Errorf("store has %+v, %v for test:ok, want 100", entry, ok)
+	}
+	if _, ok := store.Get("test:fail"); ok {
+		t.Error("store recorded a failed fetch")
+	}
+}
+
+func TestRun_MinValue(t *testing.T) {
+	reg := registry.New()
+	var out strings.Builder
+
+	coord := New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("test:dust", 0.42, nil),
+		testutil.NewMockFetcher("test:big", 150.0, nil),
+		testutil.NewMockFetcher("test:broken", 0, errors.New("fetch failed")),
+	})
+	coord.SetOutput(&out)
+	coord.SetRegistry(reg)
+	coord.SetMinValue(1.0)
+
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+
+	got := out.String()
+	if strings.Contains(got, "test:dust") {
+		t.Errorf("output = %q, want dust balance hidden", got)
+	}
+	if !strings.Contains(got, "test:big: $150.00") {
+		t.Errorf("output = %q, want test:big shown", got)
+	}
+	if !strings.Contains(got, "test:broken: ERROR") {
+		t.Errorf("output = %q, want error shown regardless of value", got)
+	}
+
+	if _, ok := reg.Get("test:dust"); ok {
+		t.Error("registry has test:dust, want hidden value not stored")
+	}
+	if _, ok := reg.Get("test:big"); !ok {
+		t.Error("registry missing test:big")
+	}
+
+	if summary.SuccessCount != 2 {
+		t.Errorf("SuccessCount = %d, want 2 (hidden values still counted)", summary.SuccessCount)
+	}
+}
+
+func TestRun_OnResult(t *testing.T) {
+	var out strings.Builder
+	var seen []string
+
+	coord := New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("test:dust", 0.42, nil),
+		testutil.NewMockFetcher("test:big", 150.0, nil),
+		testutil.NewMockFetcher("test:broken", 0, errors.New("fetch failed")),
+	})
+	coord.SetOutput(&out)
+	coord.SetMinValue(1.0)
+	coord.SetOnResult(func(result fetcher.Result) {
+		// Results are printed after the callback returns, one at a time
+		if strings.Contains(out.String(), result.Key) {
+			t.Errorf("%s was printed before its callback ran", result.Key)
+		}
+		seen = append(seen, result.Key)
+	})
+
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+
+	// Every result is passed in arrival order, including errors and hidden values
+	want := make([]string, len(summary.Results))
+	for i, result := range summary.Results {
+		want[i] = result.Key
+	}
+	if !slices.Equal(seen, want) {
+		t.Errorf("callback saw %v, want %v", seen, want)
+	}
+}
+
+func TestRun_Labels(t *testing.T) {
+	var out strings.Builder
+
+	coord := New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("fetcher:etherscan:0xABC", 100.0, nil),
+		testutil.NewMockFetcher("fetcher:alphavantage:AAPL", 200.0, nil),
+		fetcher.LabeledFetcher{Fetcher: testutil.NewMockFetcher("fetcher:alphavantage:MSFT", 300.0, nil), Name: "Microsoft"},
+		fetcher.LabeledFetcher{Fetcher: testutil.NewMockFetcher("fetcher:finnhub:GOOG", 400.0, nil), Name: "Google"},
+	})
+	coord.SetOutput(&out)
+
+	// Config keys arrive lowercased, so matching ignores case
+	coord.SetLabels(map[string]string{
+		"fetcher:etherscan:0xabc":   "Cold wallet",
+		"fetcher:finnhub:goog":      "Alphabet",
+		"fetcher:rentcast:unlisted": "Not fetched",
+	})
+
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"fetcher:etherscan:0xABC":   "Cold wallet",
+		"fetcher:alphavantage:AAPL": "",
+		"fetcher:alphavantage:MSFT": "Microsoft",
+		"fetcher:finnhub:GOOG":      "Alphabet",
+	}
+	for _, result := range summary.Results {
+		if result.Label != want[result.Key] {
+			t.Errorf("Label for %s = %q, want %q", result.Key, result.Label, want[result.Key])
+		}
+	}
+
+	for _, line := range []string{"Cold wallet: $100.00", "fetcher:alphavantage:AAPL: $200.00", "Microsoft: $300.00", "Alphabet: $400.00"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output missing %q:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestRun_GroupErrors(t *testing.T) {
+	var out strings.Builder
+
+	coord := New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("fetcher:rentcast:main_st", 0, fetcher.NewClientError(404, "address not found")),
+		testutil.NewMockFetcher("fetcher:alphavantage:AAPL", 200.0, nil),
+		testutil.NewMockFetcher("fetcher:etherscan:0xabc", 0, fetcher.NewServerError(503)),
+		testutil.NewMockFetcher("fetcher:finnhub:GOOG", 0, errors.New("boom")),
+		testutil.NewMockFetcher("fetcher:alphavantage:MSFT", 300.0, nil),
+	})
+	coord.SetOutput(&out)
+	coord.SetLabels(map[string]string{"fetcher:etherscan:0xabc": "Cold wallet"})
+	coord.SetGroupErrors(true)
+
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+	if summary.FailureCount != 3 {
+		t.Errorf("FailureCount = %d, want 3", summary.FailureCount)
+	}
+
+	output := out.String()
+	successes, report, found := strings.Cut(output, "Errors:\n")
+	if !found {
+		t.Fatalf("output has no Errors: section:\n%s", output)
+	}
+
+	if strings.Contains(successes, "ERROR") {
+		t.Errorf("errors printed before the Errors: section:\n%s", output)
+	}
+	for _, line := range []string{"fetcher:alphavantage:AAPL: $200.00", "fetcher:alphavantage:MSFT: $300.00"} {
+		if !strings.Contains(successes, line) {
+			t.Errorf("successes missing %q:\n%s", line, output)
+		}
+	}
+
+	// Sorted by key, each with its type and the message without the type repeated
+	want := "  Cold wallet [server]: server returned an error (status 503)\n" +
+		"  fetcher:finnhub:GOOG [unknown]: boom\n" +
+		"  fetcher:rentcast:main_st [client]: address not found (status 404)\n"
+	if report != want {
+		t.Errorf("Errors: section =\n%s\nwant\n%s", report, want)
+	}
+}
+
+func TestRun_GroupErrors_NoFailures(t *testing.T) {
+	var out strings.Builder
+
+	coord := New([]fetcher.Fetcher{testutil.NewMockFetcher("fetcher:alphavantage:AAPL", 200.0, nil)})
+	coord.SetOutput(&out)
+	coord.SetGroupErrors(true)
+
+	if err := coord.Run(context.Background()); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+	if strings.Contains(out.String(), "Errors:") {
+		t.Errorf("output has an Errors: section without failures:\n%s", out.String())
+	}
+}
@@ -0,0 +1,49 @@
+package coordinator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"financefetcher/internal/fetcher"
+)
+
+// formatErrorReport renders failed results as a delimited section, one line per failure
+// sorted by key:
+//
+//	Errors:
+//	  NAME [TYPE]: message
+//
+// NAME is the result's label when present, otherwise its key. The message leaves out the
+// type, which is already in brackets.
+func formatErrorReport(failed []fetcher.Result) string {
+	failed = slices.Clone(failed)
+	slices.SortFunc(failed, func(a, b fetcher.Result) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	var b strings.Builder
+	b.WriteString("Errors:\n")
+	for _, result := range failed {
+		fmt.Fprintf(&b, "  %s [%s]: %s\n", result.DisplayName(), fetcher.ErrorTypeOf(result.Error), errorMessage(result.Error))
+	}
+	return b.String()
+}
+
+// errorMessage returns err's message without the type prefix FetchError.Error adds.
+// Errors wrapping a FetchError keep their full text so the added context isn't lost.
+func errorMessage(err error) string {
+	fetchErr, ok := err.(*fetcher.FetchError)
+	if !ok {
+		return err.Error()
+	}
+
+	msg := fetchErr.Message
+	if fetchErr.StatusCode > 0 {
+		msg = fmt.Sprintf("%s (status %d)", msg, fetchErr.StatusCode)
+	}
+	if fetchErr.Attempts > 1 && !fetchErr.Exhausted {
+		msg += fmt.Sprintf(" (failed after %d attempts)", fetchErr.Attempts)
+	}
+	return msg
+}
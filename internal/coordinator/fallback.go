@@ -0,0 +1,55 @@
+package coordinator
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/registry"
+)
+
+// FallbackStore looks up the last known value for a key.
+// *registry.Registry satisfies it; a persistent store (e.g. Redis) can implement it
+// to serve values across restarts.
+type FallbackStore interface {
+	Get(key string) (registry.Entry, bool)
+}
+
+// withFallback replaces a retryable failure with the last known value from the
+// fallback store, flagged as stale. With stale totals enabled, other failures keep
+// their error but also carry the last known value (flagged as stale) for the total.
+// Successful results and cache misses are returned unchanged.
+func (c *Coordinator) withFallback(ctx context.Context, result fetcher.Result) fetcher.Result {
+	if c.fallback == nil || result.Error == nil {
+		return result
+	}
+
+	retryable := fetcher.IsRetryable(result.Error)
+	if !retryable && !c.staleTotals {
+		return result
+	}
+
+	entry, ok := c.fallback.Get(result.Key)
+	if !ok {
+		return result
+	}
+
+	msg := "fetch failed, using last known value"
+	if !retryable {
+		msg = "fetch failed, counting last known value in total"
+	}
+	slog.Warn(msg,
+		"run_id", fetcher.RunIDFromContext(ctx),
+		"key", result.Key,
+		"updated_at", entry.UpdatedAt,
+		"error", result.Error)
+
+	result.Value = entry.Value
+	result.Stale = true
+	result.Age = time.Since(entry.UpdatedAt)
+	if retryable {
+		result.Error = nil
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/registry"
+	"financefetcher/internal/testutil"
+)
+
+func TestRunWithSummary_FallbackStore(t *testing.T) {
+	updatedAt := time.Now().Add(-time.Hour)
+	store := registry.New()
+	store.Set("test:retryable", 150.0, updatedAt)
+	store.Set("test:permanent", 75.0, updatedAt)
+
+	fetchers := []fetcher.Fetcher{
+		testutil.NewMockFetcher("test:retryable", 0, fetcher.NewServerError(503)),
+		testutil.NewMockFetcher("test:permanent", 0, fetcher.NewClientError(404, "not found")),
+		testutil.NewMockFetcher("test:uncached", 0, fetcher.NewServerError(503)),
+		testutil.NewMockFetcher("test:fresh", 10.0, nil),
+	}
+
+	coord := New(fetchers)
+	coord.SetFallbackStore(store)
+	coord.SetRegistry(store)
+
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+
+	results := make(map[string]fetcher.Result)
+	for _, r := range summary.Results {
+		results[r.Key] = r
+	}
+
+	// Retryable failure with a cached value is served stale
+	if r := results["test:retryable"]; r.Error != nil || !r.Stale || r.Value != 150.0 {
+		t.Errorf("retryable result = %+v, want stale 150", r)
+	}
+	if age := results["test:retryable"].Age; age < time.Hour || age > time.Hour+time.Minute {
+		t.Errorf("stale Age = %v, want about 1h", age)
+	}
+	if r := results["test:fresh"]; r.Stale || r.Age != 0 {
+		t.Errorf("fresh result = %+v, want Stale=false, Age=0", r)
+	}
+
+	// Non-retryable failures and cache misses stay errors
+	if r := results["test:permanent"]; r.Error == nil || r.Stale {
+		t.Errorf("permanent result = %+v, want error", r)
+	}
+	if r := results["test:uncached"]; r.Error == nil || r.Stale {
+		t.Errorf("uncached result = %+v, want error", r)
+	}
+
+	if summary.SuccessCount != 2 || summary.StaleCount != 1 || summary.FailureCount != 2 {
+		t.Errorf("counts = %d success / %d stale / %d failed, want 2 / 1 / 2",
+			summary.SuccessCount, summary.StaleCount, summary.FailureCount)
+	}
+
+	// The stale value must not be re-recorded as fresh
+	if entry, _ := store.Get("test:retryable"); !entry.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("stale entry UpdatedAt = %v, want unchanged %v", entry.UpdatedAt, updatedAt)
+	}
+}
+
+func TestRun_NoFallbackStore(t *testing.T) {
+	coord := New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("test:key1", 0, errors.New("boom")),
+	})
+
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+	if summary.FailureCount != 1 || summary.StaleCount != 0 {
+		t.Errorf("counts = %d failed / %d stale, want 1 / 0", summary.FailureCount, summary.StaleCount)
+	}
+}
+
+func TestRunWithSummary_StaleTotals(t *testing.T) {
+	store := registry.New()
+	store.Set("test:permanent", 75.0, time.Now().Add(-2*time.Minute))
+
+	fetchers := []fetcher.Fetcher{
+		testutil.NewMockFetcher("test:permanent", 0, fetcher.NewClientError(404, "not found")),
+		testutil.NewMockFetcher("test:uncached", 0, fetcher.NewClientError(404, "not found")),
+		testutil.NewMockFetcher("test:fresh", 10.0, nil),
+	}
+
+	var out strings.Builder
+	coord := New(fetchers)
+	coord.SetOutput(&out)
+	coord.SetFallbackStore(store)
+	coord.SetStaleTotals(true)
+
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+
+	// The failure is still reported, but its last known value counts toward the total
+	if summary.FailureCount != 2 || summary.StaleTotalCount != 1 {
+		t.Errorf("counts = %d failed / %d stale in total, want 2 / 1", summary.FailureCount, summary.StaleTotalCount)
+	}
+	if summary.Total != 85.0 {
+		t.Errorf("Total = %v, want 85", summary.Total)
+	}
+	if want := "test:permanent: ERROR - client error (status 404): not found (last known $75.00, 2m ago)"; !strings.Contains(out.String(), want) {
+		t.Errorf("output = %q, want line %q", out.String(), want)
+	}
+}
+
+func TestRunWithSummary_StaleTotals_GroupErrors(t *testing.T) {
+	store := registry.New()
+	store.Set("test:permanent", 75.0, time.Now().Add(-2*time.Minute))
+
+	fetchers := []fetcher.Fetcher{
+		testutil.NewMockFetcher("test:permanent", 0, fetcher.NewClientError(404, "not found")),
+		testutil.NewMockFetcher("test:uncached", 0, fetcher.NewClientError(404, "not found")),
+		testutil.NewMockFetcher("test:fresh", 10.0, nil),
+	}
+
+	var out strings.Builder
+	coord := New(fetchers)
+	coord.SetOutput(&out)
+	coord.SetFallbackStore(store)
+	coord.SetStaleTotals(true)
+	coord.SetGroupErrors(true)
+
+	if _, err := coord.RunWithSummary(context.Background()); err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+
+	// The failure counted in the total keeps its last known value and age
+	successes, report, found := strings.Cut(out.String(), "Errors:\n")
+	if !found {
+		t.Fatalf("output has no Errors: section:\n%s", out.String())
+	}
+	if want := "test:permanent: ERROR - client error (status 404): not found (last known $75.00, 2m ago)"; !strings.Contains(successes, want) {
+		t.Errorf("output before Errors: = %q, want line %q", successes, want)
+	}
+	if want := "  test:uncached [client]: not found (status 404)\n"; report != want {
+		t.Errorf("Errors: section = %q, want %q", report, want)
+	}
+}
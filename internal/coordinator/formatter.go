@@ -0,0 +1,150 @@
+package coordinator
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"financefetcher/internal/fetcher"
+)
+
+// Formatter renders a single fetch result as one line of output
+type Formatter interface {
+	Format(result fetcher.Result) string
+}
+
+// BatchFormatter is a Formatter that renders all of a run's results at once, e.g. to sort
+// them. The coordinator prints nothing as results arrive and instead prints FormatAll's
+// output, with the results Format would have printed, when the run ends.
+type BatchFormatter interface {
+	Formatter
+	FormatAll(results []fetcher.Result) string
+}
+
+// TextFormatter is the default formatter. It renders results as:
+//   - Success: "NAME: {CurrencySymbol}VALUE" with two decimal places
+//   - Changed since the last run: "NAME: {CurrencySymbol}VALUE (+1.2%)", or the absolute
+//     change "(+{CurrencySymbol}5.00)" when the previous value was zero
+//   - Stale: "NAME: {CurrencySymbol}VALUE (stale, 2m ago)" for values served from a cache or fallback store
+//   - Error: "NAME: ERROR - error message"
+//   - Error counted at its last known value: "NAME: ERROR - error message (last known {CurrencySymbol}VALUE, 2m ago)"
+//
+// NAME is the result's label when present, otherwise its key.
+type TextFormatter struct {
+	// CurrencySymbol is printed before each value, e.g. "$" or "€"
+	CurrencySymbol string
+
+	// Locale controls the decimal and thousands separators.
+	// The zero Locale prints plain values like 1234567.89.
+	Locale Locale
+
+	// Rounding controls how values are rounded to cents (defaults to RoundHalfEven)
+	Rounding RoundingMode
+}
+
+// Format implements the Formatter interface
+func (f TextFormatter) Format(result fetcher.Result) string {
+	amount := f.CurrencySymbol + f.formatAmount(result.Value)
+
+	if result.Error != nil {
+		line := fmt.Sprintf("%s: ERROR - %v", result.DisplayName(), result.Error)
+		if result.Stale {
+			line += fmt.Sprintf(" (last known %s%s)", amount, ageSuffix(result.Age))
+		}
+		return line
+	}
+
+	line := fmt.Sprintf("%s: %s", result.DisplayName(), amount)
+	if result.PrevValue != nil {
+		line += " (" + f.formatChange(result) + ")"
+	}
+	if result.Stale {
+		line += fmt.Sprintf(" (stale%s)", ageSuffix(result.Age))
+	}
+	return line
+}
+
+// formatChange renders a result's change as "+1.2%", falling back to a signed amount
+// like "+$5.00" when there is no previous value to take a percentage of
+func (f TextFormatter) formatChange(result fetcher.Result) string {
+	if *result.PrevValue != 0 {
+		return fmt.Sprintf("%+.1f%%", result.DeltaPct)
+	}
+	sign := "+"
+	if result.Delta < 0 {
+		sign = "-"
+	}
+	return sign + f.CurrencySymbol + f.formatAmount(math.Abs(result.Delta))
+}
+
+// formatAmount rounds value to cents with the formatter's rounding mode and renders it in its locale
+func (f TextFormatter) formatAmount(value float64) string {
+	return f.Locale.FormatDecimal(f.Rounding.Round(value))
+}
+
+// ageSuffix renders a stale value's age as ", 2m ago", or "" when the age is unknown
+func ageSuffix(age time.Duration) string {
+	if age <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %s ago", formatAge(age))
+}
+
+// formatAge renders d in its largest whole unit, e.g. "45s", "2m", "3h", or "2d"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+}
+
+// goldenPrecision is the number of decimals GoldenFormatter prints by default
+const goldenPrecision = 2
+
+// GoldenFormatter renders a run as a stable, machine-diffable snapshot suitable for
+// committing as a golden file: one "key=value" line per result, sorted by key, with a
+// fixed number of decimals and no currency symbol or grouping. Failures render as
+// "key=ERROR:<type>" (e.g. "key=ERROR:rate_limit") without the message, which can vary
+// between runs. Keys are used rather than labels so renaming a label doesn't churn the file.
+type GoldenFormatter struct {
+	// Precision is the number of decimals printed (defaults to 2 when zero)
+	Precision int
+}
+
+// Format implements the Formatter interface
+func (f GoldenFormatter) Format(result fetcher.Result) string {
+	if result.Error != nil {
+		return fmt.Sprintf("%s=ERROR:%s", result.Key, fetcher.ErrorTypeOf(result.Error))
+	}
+
+	precision := f.Precision
+	if precision == 0 {
+		precision = goldenPrecision
+	}
+	return result.Key + "=" + strconv.FormatFloat(result.Value, 'f', precision, 64)
+}
+
+// FormatAll implements the BatchFormatter interface, sorting results by key so the
+// snapshot doesn't depend on the order fetchers finished in. Results are sorted before
+// formatting, since sorting the lines would put "a:b=" before "a=".
+func (f GoldenFormatter) FormatAll(results []fetcher.Result) string {
+	results = slices.Clone(results)
+	slices.SortStableFunc(results, func(a, b fetcher.Result) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	lines := make([]string, len(results))
+	for i, result := range results {
+		lines[i] = f.Format(result)
+	}
+	return strings.Join(lines, "\n")
+}
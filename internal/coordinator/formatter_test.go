@@ -0,0 +1,301 @@
+package coordinator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/registry"
+	"financefetcher/internal/testutil"
+)
+
+func TestTextFormatter_Format(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter TextFormatter
+		result    fetcher.Result
+		want      string
+	}{
+		{
+			name:      "dollars",
+			formatter: TextFormatter{CurrencySymbol: "$"},
+			result:    fetcher.Result{Key: "fetcher:alphavantage:AAPL", Value: 178.234},
+			want:      "fetcher:alphavantage:AAPL: $178.23",
+		},
+		{
+			// A fractional position valued exactly in decimal, e.g. 187.43 * 2.37
+			name:      "fractional position",
+			formatter: TextFormatter{CurrencySymbol: "$", Rounding: RoundHalfUp},
+			result:    fetcher.Result{Key: "fetcher:alphavantage:position:AAPL", Value: 444.2091},
+			want:      "fetcher:alphavantage:position:AAPL: $444.21",
+		},
+		{
+			// 4.35 * 1.5 in decimal; the float64 product 6.5249999999999995 would print $6.52
+			name:      "half cent rounds up",
+			formatter: TextFormatter{CurrencySymbol: "$", Rounding: RoundHalfUp},
+			result:    fetcher.Result{Key: "fetcher:alphavantage:position:AAPL", Value: 6.525},
+			want:      "fetcher:alphavantage:position:AAPL: $6.53",
+		},
+		{
+			name:      "euros",
+			formatter: TextFormatter{CurrencySymbol: "€"},
+			result:    fetcher.Result{Key: "fetcher:alphavantage:SAP", Value: 120.5},
+			want:      "fetcher:alphavantage:SAP: €120.50",
+		},
+		{
+			name:      "grouped",
+			formatter: TextFormatter{CurrencySymbol: "$", Locale: LocaleEnUS},
+			result:    fetcher.Result{Key: "fetcher:rentcast:123_main_st", Value: 350000},
+			want:      "fetcher:rentcast:123_main_st: $350,000.00",
+		},
+		{
+			name:      "labeled",
+			formatter: TextFormatter{CurrencySymbol: "$"},
+			result:    fetcher.Result{Key: "fetcher:alphavantage:AAPL", Label: "Apple stock", Value: 178.234},
+			want:      "Apple stock: $178.23",
+		},
+		{
+			name:      "stale",
+			formatter: TextFormatter{CurrencySymbol: "$"},
+			result:    fetcher.Result{Key: "fetcher:alphavantage:AAPL", Value: 178.234, Stale: true},
+			want:      "fetcher:alphavantage:AAPL: $178.23 (stale)",
+		},
+		{
+			name:      "stale with age",
+			formatter: TextFormatter{CurrencySymbol: "$"},
+			result:    fetcher.Result{Key: "fetcher:alphavantage:AAPL", Value: 178.234, Stale: true, Age: 2*time.Minute + 10*time.Second},
+			want:      "fetcher:alphavantage:AAPL: $178.23 (stale, 2m ago)",
+		},
+		{
+			name:      "change",
+			formatter: TextFormatter{CurrencySymbol: "$"},
+			result:    fetcher.Result{Key: "fetcher:alphavantage:AAPL", Value: 178.234, PrevValue: ptr(176.12), Delta: 2.114, DeltaPct: 1.2003},
+			want:      "fetcher:alphavantage:AAPL: $178.23 (+1.2%)",
+		},
+		{
+			name:      "change from zero",
+			formatter: TextFormatter{CurrencySymbol: "$"},
+			result:    fetcher.Result{Key: "fetcher:etherscan:0xabc", Value: 5, PrevValue: ptr(0), Delta: 5},
+			want:      "fetcher:etherscan:0xabc: $5.00 (+$5.00)",
+		},
+		{
+			name:      "error",
+			formatter: TextFormatter{CurrencySymbol: "$"},
+			result:    fetcher.Result{Key: "fetcher:alphavantage:AAPL", Error: errors.New("fetch failed")},
+			want:      "fetcher:alphavantage:AAPL: ERROR - fetch failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formatter.Format(tt.result); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// recordingFormatter is a custom Formatter used to verify injection
+type recordingFormatter struct {
+	formatted []string
+}
+
+func (f *recordingFormatter) Format(result fetcher.Result) string {
+	f.formatted = append(f.formatted, result.Key)
+	return "custom " + result.Key
+}
+
+func TestCoordinator_SetFormatter(t *testing.T) {
+	custom := &recordingFormatter{}
+
+	coord := New([]fetcher.Fetcher{testutil.NewMockFetcher("test:key1", 1, nil)})
+	coord.SetFormatter(custom)
+
+	if err := coord.Run(context.Background()); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+
+	if len(custom.formatted) != 1 || custom.formatted[0] != "test:key1" {
+		t.Errorf("custom formatter saw %v, want [test:key1]", custom.formatted)
+	}
+}
+
+func TestCoordinator_LabeledFetcher(t *testing.T) {
+	var lines []string
+	formatter := formatterFunc(func(result fetcher.Result) string {
+		line := TextFormatter{CurrencySymbol: "$"}.Format(result)
+		lines = append(lines, line)
+		return line
+	})
+
+	reg := registry.New()
+	labeled := fetcher.LabeledFetcher{
+		Fetcher: testutil.NewMockFetcher("fetcher:alphavantage:AAPL", 178.23, nil),
+		Name:    "Apple stock",
+	}
+
+	coord := New([]fetcher.Fetcher{labeled})
+	coord.SetFormatter(formatter)
+	coord.SetRegistry(reg)
+
+	if err := coord.Run(context.Background()); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+
+	if len(lines) != 1 || lines[0] != "Apple stock: $178.23" {
+		t.Errorf("output = %v, want [Apple stock: $178.23]", lines)
+	}
+	if _, ok := reg.Get("fetcher:alphavantage:AAPL"); !ok {
+		t.Error("registry should store the result under the fetcher key")
+	}
+}
+
+func TestCoordinator_ChangeFetcher(t *testing.T) {
+	var out bytes.Buffer
+
+	store := registry.New()
+	store.Set("fetcher:alphavantage:AAPL", 200, time.Now())
+
+	coord := New([]fetcher.Fetcher{fetcher.LabeledFetcher{
+		Fetcher: fetcher.NewChangeFetcher(testutil.NewMockFetcher("fetcher:alphavantage:AAPL", 190, nil), store),
+		Name:    "AAPL",
+	}})
+	coord.SetOutput(&out)
+	coord.SetFormatter(TextFormatter{CurrencySymbol: "$"})
+
+	if err := coord.Run(context.Background()); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+
+	if got, want := out.String(), "AAPL: $190.00 (-5.0%)\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+// ptr returns a pointer to v
+func ptr(v float64) *float64 {
+	return &v
+}
+
+// formatterFunc adapts a function to the Formatter interface
+type formatterFunc func(fetcher.Result) string
+
+func (f formatterFunc) Format(result fetcher.Result) string {
+	return f(result)
+}
+
+func TestCoordinator_SetOutput(t *testing.T) {
+	var out bytes.Buffer
+
+	coord := New([]fetcher.Fetcher{testutil.NewMockFetcher("test:key1", 1234.5, nil)})
+	coord.SetOutput(&out)
+
+	if err := coord.Run(context.Background()); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+
+	want := "test:key1: $1,234.50\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{45 * time.Second, "45s"},
+		{2*time.Minute + 59*time.Second, "2m"},
+		{3 * time.Hour, "3h"},
+		{50 * time.Hour, "2d"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAge(tt.age); got != tt.want {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestGoldenFormatter_Format(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter GoldenFormatter
+		result    fetcher.Result
+		want      string
+	}{
+		{
+			name:   "default precision",
+			result: fetcher.Result{Key: "fetcher:alphavantage:AAPL", Label: "Apple", Value: 1234567.891},
+			want:   "fetcher:alphavantage:AAPL=1234567.89",
+		},
+		{
+			name:      "custom precision",
+			formatter: GoldenFormatter{Precision: 4},
+			result:    fetcher.Result{Key: "fetcher:alphavantage:fx:USD-EUR", Value: 0.92},
+			want:      "fetcher:alphavantage:fx:USD-EUR=0.9200",
+		},
+		{
+			name:   "error type only",
+			result: fetcher.Result{Key: "fetcher:rentcast:1_main_st", Error: fetcher.NewRateLimitError(429).WithContext("failed at 10:42")},
+			want:   "fetcher:rentcast:1_main_st=ERROR:rate_limit",
+		},
+		{
+			name:   "plain error",
+			result: fetcher.Result{Key: "fetcher:finnhub:MSFT", Error: errors.New("boom")},
+			want:   "fetcher:finnhub:MSFT=ERROR:unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formatter.Format(tt.result); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoldenFormatter_FormatAll_SortsByKey(t *testing.T) {
+	// Sorting the rendered lines would put "...:gas=" before "...:0xabc=", since ':' < '='
+	results := []fetcher.Result{
+		{Key: "fetcher:etherscan:0xabc:gas", Value: 2},
+		{Key: "fetcher:etherscan:0xabc", Value: 1},
+	}
+
+	want := "fetcher:etherscan:0xabc=1.00\nfetcher:etherscan:0xabc:gas=2.00"
+	if got := (GoldenFormatter{}).FormatAll(results); got != want {
+		t.Errorf("FormatAll() = %q, want %q", got, want)
+	}
+	if results[0].Key != "fetcher:etherscan:0xabc:gas" {
+		t.Error("FormatAll() reordered the caller's results")
+	}
+}
+
+func TestRun_GoldenFormatter(t *testing.T) {
+	var out bytes.Buffer
+
+	coord := New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("test:c", 3, nil),
+		testutil.NewMockFetcher("test:a", 1.5, nil),
+		testutil.NewMockFetcher("test:b", 0, fetcher.NewServerError(503)),
+		testutil.NewMockFetcher("test:dust", 0.01, nil),
+	})
+	coord.SetOutput(&out)
+	coord.SetMinValue(0.5)
+	coord.SetFormatter(GoldenFormatter{})
+
+	if err := coord.Run(context.Background()); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+
+	// Sorted by key regardless of arrival order, with hidden values left out
+	want := "test:a=1.50\ntest:b=ERROR:server\ntest:c=3.00\n"
+	if got := out.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
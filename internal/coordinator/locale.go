@@ -0,0 +1,106 @@
+package coordinator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Locale describes how monetary amounts are written in a given region
+type Locale struct {
+	// DecimalSeparator separates whole units from cents, e.g. "." in 1,234.56
+	DecimalSeparator string
+	// GroupSeparator separates each group of three digits, e.g. "," in 1,234.56.
+	// An empty GroupSeparator disables grouping.
+	GroupSeparator string
+}
+
+var (
+	// LocaleEnUS formats amounts as 1,234,567.89
+	LocaleEnUS = Locale{DecimalSeparator: ".", GroupSeparator: ","}
+	// LocaleDeDE formats amounts as 1.234.567,89
+	LocaleDeDE = Locale{DecimalSeparator: ",", GroupSeparator: "."}
+	// LocaleFrFR formats amounts as 1 234 567,89
+	LocaleFrFR = Locale{DecimalSeparator: ",", GroupSeparator: " "}
+	// LocaleDeCH formats amounts as 1'234'567.89
+	LocaleDeCH = Locale{DecimalSeparator: ".", GroupSeparator: "'"}
+	// LocalePlain formats amounts without grouping as 1234567.89
+	LocalePlain = Locale{DecimalSeparator: "."}
+)
+
+// locales maps config names to supported locales
+var locales = map[string]Locale{
+	"en-US": LocaleEnUS,
+	"de-DE": LocaleDeDE,
+	"fr-FR": LocaleFrFR,
+	"de-CH": LocaleDeCH,
+	"plain": LocalePlain,
+}
+
+// LookupLocale returns the locale registered under name (e.g. "en-US", "de-DE")
+func LookupLocale(name string) (Locale, error) {
+	locale, ok := locales[name]
+	if !ok {
+		names := make([]string, 0, len(locales))
+		for n := range locales {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Locale{}, fmt.Errorf("unknown locale %q (supported: %s)", name, strings.Join(names, ", "))
+	}
+	return locale, nil
+}
+
+// FormatAmount renders value with two decimal places using the locale's separators.
+// A zero Locale renders like "%.2f".
+func (l Locale) FormatAmount(value float64) string {
+	return l.formatFixed(fmt.Sprintf("%.2f", value))
+}
+
+// FormatDecimal renders an exact decimal amount, rounded half away from zero to two
+// decimal places, using the locale's separators
+func (l Locale) FormatDecimal(value decimal.Decimal) string {
+	return l.formatFixed(value.StringFixed(2))
+}
+
+// formatFixed applies the locale's separators to a plain "-1234.56" style amount
+func (l Locale) formatFixed(formatted string) string {
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign = "-"
+		formatted = formatted[1:]
+	}
+
+	whole, frac, _ := strings.Cut(formatted, ".")
+
+	decimalSep := l.DecimalSeparator
+	if decimalSep == "" {
+		decimalSep = "."
+	}
+
+	return sign + groupDigits(whole, l.GroupSeparator) + decimalSep + frac
+}
+
+// groupDigits inserts sep between each group of three digits, counting from the right
+func groupDigits(digits, sep string) string {
+	if sep == "" || len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package coordinator
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestLocale_FormatAmount(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale Locale
+		value  float64
+		want   string
+	}{
+		{"en-US large", LocaleEnUS, 1234567.891, "1,234,567.89"},
+		{"en-US small", LocaleEnUS, 178.23, "178.23"},
+		{"en-US exact thousand", LocaleEnUS, 350000, "350,000.00"},
+		{"en-US negative", LocaleEnUS, -1234.5, "-1,234.50"},
+		{"de-DE large", LocaleDeDE, 1234567.891, "1.234.567,89"},
+		{"fr-FR large", LocaleFrFR, 1234567.891, "1 234 567,89"},
+		{"de-CH large", LocaleDeCH, 1234567.891, "1'234'567.89"},
+		{"plain", LocalePlain, 1234567.891, "1234567.89"},
+		{"zero locale", Locale{}, 1234567.891, "1234567.89"},
+		{"zero value", LocaleDeDE, 0, "0,00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.locale.FormatAmount(tt.value); got != tt.want {
+				t.Errorf("FormatAmount(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupLocale(t *testing.T) {
+	locale, err := LookupLocale("de-DE")
+	if err != nil {
+		t.Fatalf("LookupLocale() returned unexpected error: %v", err)
+	}
+	if locale != LocaleDeDE {
+		t.Errorf("LookupLocale(%q) = %+v, want %+v", "de-DE", locale, LocaleDeDE)
+	}
+
+	if _, err := LookupLocale("xx-XX"); err == nil {
+		t.Error("LookupLocale() expected error for unknown locale, got nil")
+	}
+}
+
+func TestLocale_FormatDecimal(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale Locale
+		value  string
+		want   string
+	}{
+		{"en-US grouped", LocaleEnUS, "1234567.891", "1,234,567.89"},
+		{"de-DE grouped", LocaleDeDE, "1234567.891", "1.234.567,89"},
+		{"rounds half away from zero", LocaleEnUS, "2.675", "2.68"},
+		{"negative", LocaleEnUS, "-1234.5", "-1,234.50"},
+		{"zero locale", Locale{}, "1234.5", "1234.50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.locale.FormatDecimal(decimal.RequireFromString(tt.value)); got != tt.want {
+				t.Errorf("FormatDecimal(%s) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
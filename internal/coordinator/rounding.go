@@ -0,0 +1,52 @@
+package coordinator
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode selects how amounts are rounded to cents before formatting. Rounding is
+// applied to the value's shortest decimal representation, so 2.005 is treated as exactly
+// halfway even though the nearest float64 is slightly below it.
+type RoundingMode string
+
+const (
+	// RoundHalfEven rounds halves to the nearest even cent (2.005 → 2.00, 2.015 → 2.02).
+	// It is the default, matching the banker's rounding of %.2f without its float artifacts.
+	RoundHalfEven RoundingMode = "half-even"
+
+	// RoundHalfUp rounds halves away from zero (2.005 → 2.01, -2.005 → -2.01), as is
+	// usual in accounting
+	RoundHalfUp RoundingMode = "half-up"
+
+	// RoundTruncate drops everything past the cent (2.009 → 2.00, -2.009 → -2.00)
+	RoundTruncate RoundingMode = "truncate"
+)
+
+// ParseRoundingMode returns the rounding mode named by s ("half-even", "half-up", or
+// "truncate"); an empty string selects RoundHalfEven
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch mode := RoundingMode(s); mode {
+	case "":
+		return RoundHalfEven, nil
+	case RoundHalfEven, RoundHalfUp, RoundTruncate:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown rounding mode %q (supported: %s, %s, %s)", s, RoundHalfEven, RoundHalfUp, RoundTruncate)
+	}
+}
+
+// Round rounds value to two decimal places using the mode. The zero mode rounds half-even.
+func (m RoundingMode) Round(value float64) decimal.Decimal {
+	d := decimal.NewFromFloat(value)
+
+	switch m {
+	case RoundHalfUp:
+		return d.Round(2)
+	case RoundTruncate:
+		return d.Truncate(2)
+	default:
+		return d.RoundBank(2)
+	}
+}
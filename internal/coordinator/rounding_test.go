@@ -0,0 +1,68 @@
+package coordinator
+
+import (
+	"testing"
+
+	"financefetcher/internal/fetcher"
+)
+
+func TestRoundingMode_Round(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  RoundingMode
+		value float64
+		want  string
+	}{
+		{"half-even boundary down", RoundHalfEven, 2.005, "2.00"},
+		{"half-even boundary up", RoundHalfEven, 2.015, "2.02"},
+		{"half-even negative", RoundHalfEven, -2.005, "-2.00"},
+		{"half-up boundary", RoundHalfUp, 2.005, "2.01"},
+		{"half-up boundary even", RoundHalfUp, 2.015, "2.02"},
+		{"half-up negative", RoundHalfUp, -2.005, "-2.01"},
+		{"truncate boundary", RoundTruncate, 2.005, "2.00"},
+		{"truncate just below next cent", RoundTruncate, 2.009, "2.00"},
+		{"truncate negative", RoundTruncate, -2.009, "-2.00"},
+		{"zero mode is half-even", "", 2.005, "2.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mode.Round(tt.value).StringFixed(2); got != tt.want {
+				t.Errorf("%q.Round(%v) = %s, want %s", tt.mode, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextFormatter_Rounding(t *testing.T) {
+	result := fetcher.Result{Key: "fetcher:test:a", Value: 1002.005}
+
+	for mode, want := range map[RoundingMode]string{
+		RoundHalfEven: "fetcher:test:a: $1,002.00",
+		RoundHalfUp:   "fetcher:test:a: $1,002.01",
+		RoundTruncate: "fetcher:test:a: $1,002.00",
+	} {
+		f := TextFormatter{CurrencySymbol: "$", Locale: LocaleEnUS, Rounding: mode}
+		if got := f.Format(result); got != want {
+			t.Errorf("Format() with %s = %q, want %q", mode, got, want)
+		}
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	for input, want := range map[string]RoundingMode{
+		"":          RoundHalfEven,
+		"half-even": RoundHalfEven,
+		"half-up":   RoundHalfUp,
+		"truncate":  RoundTruncate,
+	} {
+		got, err := ParseRoundingMode(input)
+		if err != nil || got != want {
+			t.Errorf("ParseRoundingMode(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := ParseRoundingMode("ceiling"); err == nil {
+		t.Error("ParseRoundingMode(\"ceiling\") expected an error, got nil")
+	}
+}
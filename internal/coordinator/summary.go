@@ -0,0 +1,80 @@
+package coordinator
+
+import (
+	"time"
+
+	"financefetcher/internal/fetcher"
+
+	"github.com/shopspring/decimal"
+)
+
+// RunSummary describes the outcome of a single fetch cycle
+type RunSummary struct {
+	// Total is the sum of all successfully fetched values, plus the last known values of
+	// failures when stale totals are enabled, converted from ExactTotal. Exchange rates
+	// (see fetcher.IsRateKey) are not amounts of money and are left out.
+	Total float64
+
+	// ExactTotal is the same sum computed in decimal arithmetic. Fetchers still return
+	// float64, but each value is converted at its shortest decimal representation
+	// (178.23 stays 178.23) before summing, so rounding error doesn't accumulate across
+	// many values. Format it with Locale.FormatDecimal for exact output.
+	ExactTotal decimal.Decimal
+
+	// SuccessCount and FailureCount count fetchers that returned a value or an error
+	SuccessCount int
+	FailureCount int
+
+	// StaleCount counts successes served from the fallback store (included in SuccessCount)
+	StaleCount int
+
+	// StaleTotalCount counts failures whose last known value was added to the total
+	// because stale totals are enabled (included in FailureCount)
+	StaleTotalCount int
+
+	// StartedAt is when the cycle began
+	StartedAt time.Time
+
+	// Duration is how long the whole cycle took
+	Duration time.Duration
+
+	// Results holds every result in the order it arrived
+	Results []fetcher.Result
+
+	// Timings holds each fetcher's timing keyed by Key() when timing is enabled
+	// (see Coordinator.SetTiming), otherwise nil. Fetchers sharing a key keep the last one.
+	Timings map[string]FetchTiming
+}
+
+// FetchTiming records when a single fetcher ran and where its time went
+type FetchTiming struct {
+	Start time.Time
+	End   time.Time
+
+	// Duration is End minus Start
+	Duration time.Duration
+
+	// RateLimitWait is how long the fetcher was blocked on the rate limiter
+	RateLimitWait time.Duration
+}
+
+// RequestTime returns the part of Duration not spent waiting on the rate limiter:
+// network latency, retries, and response handling
+func (t FetchTiming) RequestTime() time.Duration {
+	return t.Duration - t.RateLimitWait
+}
+
+// FailuresByType buckets the failed results by the ErrorType of their error, so callers
+// can tell a rate-limit storm from a validation problem or a server outage.
+// Errors that aren't FetchErrors are grouped under ErrorTypeUnknown.
+func (s RunSummary) FailuresByType() map[fetcher.ErrorType][]fetcher.Result {
+	failures := make(map[fetcher.ErrorType][]fetcher.Result)
+	for _, result := range s.Results {
+		if result.Error == nil {
+			continue
+		}
+		errType := fetcher.ErrorTypeOf(result.Error)
+		failures[errType] = append(failures[errType], result)
+	}
+	return failures
+}
@@ -0,0 +1,126 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/testutil"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRunWithSummary(t *testing.T) {
+	fetchers := []fetcher.Fetcher{
+		testutil.NewMockFetcher("test:key1", 100.0, nil),
+		testutil.NewMockFetcher("test:key2", 250.5, nil),
+		testutil.NewMockFetcher("test:key3", 0, errors.New("fetch failed")),
+	}
+
+	coord := New(fetchers)
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+
+	if summary.Total != 350.5 {
+		t.Errorf("Total = %v, want 350.5", summary.Total)
+	}
+	if summary.SuccessCount != 2 {
+		t.Errorf("SuccessCount = %d, want 2", summary.SuccessCount)
+	}
+	if summary.FailureCount != 1 {
+		t.Errorf("FailureCount = %d, want 1", summary.FailureCount)
+	}
+	if len(summary.Results) != 3 {
+		t.Errorf("len(Results) = %d, want 3", len(summary.Results))
+	}
+	if summary.Duration <= 0 {
+		t.Errorf("Duration = %v, want positive", summary.Duration)
+	}
+}
+
+func TestRunWithSummary_ExcludesRates(t *testing.T) {
+	fetchers := []fetcher.Fetcher{
+		testutil.NewMockFetcher("fetcher:alphavantage:AAPL", 100.0, nil),
+		testutil.NewMockFetcher("fetcher:alphavantage:fx:USD-EUR", 0.92, nil),
+	}
+
+	summary, err := New(fetchers).RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+
+	// The rate is still a successful result, but it isn't dollars
+	if summary.Total != 100.0 {
+		t.Errorf("Total = %v, want 100 without the FX rate", summary.Total)
+	}
+	if summary.SuccessCount != 2 {
+		t.Errorf("SuccessCount = %d, want 2", summary.SuccessCount)
+	}
+}
+
+func TestRunWithSummary_NoFetchers(t *testing.T) {
+	coord := New([]fetcher.Fetcher{})
+
+	if _, err := coord.RunWithSummary(context.Background()); err == nil {
+		t.Error("RunWithSummary() expected error for no fetchers, got nil")
+	}
+}
+
+func TestRunWithSummary_ExactTotal(t *testing.T) {
+	// Summing 0.1 ten times in float64 gives 0.9999999999999999
+	fetchers := make([]fetcher.Fetcher, 10)
+	for i := range fetchers {
+		fetchers[i] = testutil.NewMockFetcher(fmt.Sprintf("test:key%d", i), 0.1, nil)
+	}
+
+	summary, err := New(fetchers).RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+
+	if !summary.ExactTotal.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("ExactTotal = %s, want 1", summary.ExactTotal)
+	}
+	if summary.Total != 1 {
+		t.Errorf("Total = %v, want 1", summary.Total)
+	}
+}
+
+func TestRunSummary_FailuresByType(t *testing.T) {
+	summary := RunSummary{
+		Results: []fetcher.Result{
+			{Key: "test:ok", Value: 100.0},
+			{Key: "test:limited1", Error: fetcher.NewRateLimitError(429)},
+			{Key: "test:limited2", Error: fmt.Errorf("wrapped: %w", fetcher.NewRateLimitError(429))},
+			{Key: "test:down", Error: fetcher.NewServerError(503)},
+			{Key: "test:plain", Error: errors.New("boom")},
+		},
+	}
+
+	failures := summary.FailuresByType()
+
+	want := map[fetcher.ErrorType][]string{
+		fetcher.ErrorTypeRateLimit: {"test:limited1", "test:limited2"},
+		fetcher.ErrorTypeServer:    {"test:down"},
+		fetcher.ErrorTypeUnknown:   {"test:plain"},
+	}
+	if len(failures) != len(want) {
+		t.Fatalf("FailuresByType() has %d types, want %d: %v", len(failures), len(want), failures)
+	}
+	for errType, keys := range want {
+		got := failures[errType]
+		if len(got) != len(keys) {
+			t.Errorf("FailuresByType()[%s] has %d results, want %d", errType, len(got), len(keys))
+			continue
+		}
+		for i, key := range keys {
+			if got[i].Key != key {
+				t.Errorf("FailuresByType()[%s][%d].Key = %q, want %q", errType, i, got[i].Key, key)
+			}
+		}
+	}
+}
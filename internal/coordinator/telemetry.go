@@ -0,0 +1,27 @@
+package coordinator
+
+import (
+	"context"
+
+	"financefetcher/internal/fetcher"
+)
+
+// Telemetry observes each fetch, e.g. to export traces and metrics. StartFetch is called
+// with the fetcher's key before it runs and returns the context to fetch with and a
+// function that receives the outcome. telemetry.OTel adapts OpenTelemetry to it, so the
+// coordinator doesn't depend on OTel itself.
+type Telemetry interface {
+	StartFetch(ctx context.Context, key string) (context.Context, func(value float64, err error))
+}
+
+// fetch runs f.Fetch, reporting it to the coordinator's telemetry when one is set
+func (c *Coordinator) fetch(ctx context.Context, f fetcher.Fetcher) (float64, error) {
+	if c.telemetry == nil {
+		return f.Fetch(ctx)
+	}
+
+	ctx, done := c.telemetry.StartFetch(ctx, f.Key())
+	value, err := f.Fetch(ctx)
+	done(value, err)
+	return value, err
+}
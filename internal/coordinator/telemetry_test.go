@@ -0,0 +1,71 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/testutil"
+)
+
+// recordingTelemetry records each fetch's outcome by key
+type recordingTelemetry struct {
+	mu       sync.Mutex
+	started  map[string]bool
+	outcomes map[string]error
+}
+
+func (r *recordingTelemetry) StartFetch(ctx context.Context, key string) (context.Context, func(float64, error)) {
+	r.mu.Lock()
+	r.started[key] = true
+	r.mu.Unlock()
+
+	return ctx, func(_ float64, err error) {
+		r.mu.Lock()
+		r.outcomes[key] = err
+		r.mu.Unlock()
+	}
+}
+
+func TestRun_Telemetry(t *testing.T) {
+	rec := &recordingTelemetry{started: make(map[string]bool), outcomes: make(map[string]error)}
+	testErr := fetcher.NewServerError(503)
+
+	coord := New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("fetcher:alphavantage:AAPL", 178.5, nil),
+		testutil.NewMockFetcher("fetcher:etherscan:0xabc", 0, testErr),
+	})
+	coord.SetOutput(io.Discard)
+	coord.SetTelemetry(rec)
+
+	if err := coord.Run(context.Background()); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+
+	if len(rec.started) != 2 || len(rec.outcomes) != 2 {
+		t.Fatalf("telemetry saw %d starts and %d outcomes, want 2 of each", len(rec.started), len(rec.outcomes))
+	}
+	if err := rec.outcomes["fetcher:alphavantage:AAPL"]; err != nil {
+		t.Errorf("AAPL outcome = %v, want success", err)
+	}
+	if err := rec.outcomes["fetcher:etherscan:0xabc"]; !errors.Is(err, testErr) {
+		t.Errorf("0xabc outcome = %v, want the fetcher's error", err)
+	}
+}
+
+func TestRun_TelemetryDisabledByDefault(t *testing.T) {
+	testErr := errors.New("fetch failed")
+	coord := New([]fetcher.Fetcher{testutil.NewMockFetcher("test:key", 0, testErr)})
+	coord.SetOutput(io.Discard)
+
+	summary, err := coord.RunWithSummary(context.Background())
+	if err != nil {
+		t.Fatalf("RunWithSummary() returned unexpected error: %v", err)
+	}
+	if !errors.Is(summary.Results[0].Error, testErr) {
+		t.Errorf("result error = %v, want the fetcher's error passed through", summary.Results[0].Error)
+	}
+}
@@ -0,0 +1,179 @@
+package etherscan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+)
+
+// blockRewardResult is the part of a getblockreward result needed to date a block
+type blockRewardResult struct {
+	BlockNumber string `json:"blockNumber"`
+	TimeStamp   string `json:"timeStamp"` // Unix seconds
+}
+
+// dailyPrice is one day of Etherscan's ethdailyprice history
+type dailyPrice struct {
+	UTCDate string `json:"UTCDate"`
+	Value   string `json:"value"`
+}
+
+// FetchAtBlock returns the wallet's balance at blockNumber in USD, for point-in-time
+// valuations such as tax lots. The balance is valued at ETH's daily price on the day the
+// block was mined when Etherscan has one, and otherwise at the current price, with a
+// warning. The gas reserve does not apply to historical balances.
+//
+// Both balancehistory and ethdailyprice are Etherscan API Pro endpoints. On a free API
+// key FetchAtBlock fails with a client error saying so.
+func (f *WalletFetcher) FetchAtBlock(ctx context.Context, blockNumber int64) (float64, error) {
+	if blockNumber < 0 {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("invalid block number %d", blockNumber))
+	}
+	block := strconv.FormatInt(blockNumber, 10)
+
+	result, err := f.query(ctx, "account", "balancehistory", map[string]string{
+		"address": f.address,
+		"blockno": block,
+	}, "failed to fetch balance at block "+block+" for "+f.address)
+	if err != nil {
+		return 0, err
+	}
+
+	var wei string
+	if err := json.Unmarshal(result.Result, &wei); err != nil || wei == "" || result.Status != "1" {
+		reason := resultReason(result)
+		if isProOnly(reason) {
+			return 0, fetcher.NewClientError(0, fmt.Sprintf("balance at block %s requires an Etherscan API Pro key: %s", block, reason))
+		}
+		if isRateLimited(reason) {
+			return 0, statusError(result).WithContext("failed to fetch balance at block " + block + " for " + f.address)
+		}
+		return 0, fetcher.NewValidationError(fmt.Sprintf("balance at block %s not found for %s: %s", block, f.address, reason))
+	}
+
+	ethUSD, ok, err := f.fetchEthPriceAtBlock(ctx, block)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		slog.Warn("no historical ETH price for block, using the current price", "run_id", fetcher.RunIDFromContext(ctx), "address", f.address, "block", blockNumber)
+		if ethUSD, err = f.fetchEthPrice(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	return weiToUSD(wei, ethUSD)
+}
+
+// fetchEthPriceAtBlock returns ETH's daily USD price on the day block was mined. It reports
+// false, without an error, when Etherscan can't date the block or has no price for that
+// day, including when the key lacks API Pro access to ethdailyprice. Being rate limited is
+// an error, so a throttled run isn't silently valued at the current price.
+func (f *WalletFetcher) fetchEthPriceAtBlock(ctx context.Context, block string) (float64, bool, error) {
+	result, err := f.query(ctx, "block", "getblockreward", map[string]string{
+		"blockno": block,
+	}, "failed to fetch block "+block)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var reward blockRewardResult
+	if result.Status != "1" || json.Unmarshal(result.Result, &reward) != nil {
+		if isRateLimited(resultReason(result)) {
+			return 0, false, statusError(result).WithContext("failed to fetch block " + block)
+		}
+		slog.Debug("could not date block", "run_id", fetcher.RunIDFromContext(ctx), "block", block, "reason", resultReason(result))
+		return 0, false, nil
+	}
+	seconds, err := strconv.ParseInt(reward.TimeStamp, 10, 64)
+	if err != nil {
+		return 0, false, nil
+	}
+	day := time.Unix(seconds, 0).UTC().Format(time.DateOnly)
+
+	result, err = f.query(ctx, "stats", "ethdailyprice", map[string]string{
+		"startdate": day,
+		"enddate":   day,
+		"sort":      "asc",
+	}, "failed to fetch ETH price for "+day)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var prices []dailyPrice
+	if result.Status != "1" || json.Unmarshal(result.Result, &prices) != nil || len(prices) == 0 {
+		if isRateLimited(resultReason(result)) {
+			return 0, false, statusError(result).WithContext("failed to fetch ETH price for " + day)
+		}
+		slog.Debug("no daily ETH price", "run_id", fetcher.RunIDFromContext(ctx), "day", day, "reason", resultReason(result))
+		return 0, false, nil
+	}
+
+	price, err := strconv.ParseFloat(prices[0].Value, 64)
+	if err != nil || price <= 0 {
+		return 0, false, nil
+	}
+
+	return price, true, nil
+}
+
+// query sends one rate-limited Etherscan request and returns its envelope. Transport and
+// HTTP errors are classified with errContext; Etherscan-level failures (status "0") are
+// left to the caller.
+func (f *WalletFetcher) query(ctx context.Context, module, action string, params map[string]string, errContext string) (statusResponse, error) {
+	var result statusResponse
+
+	waited, err := ratelimit.GetLimiter().WaitTimed(ctx, ratelimit.APIEtherscan)
+	if err != nil {
+		return result, fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIEtherscan, "action", action, "wait_duration", waited)
+
+	query := map[string]string{
+		"chainid": mainnetChainID,
+		"module":  module,
+		"action":  action,
+		"apikey":  f.apiKey,
+	}
+	for k, v := range params {
+		query[k] = v
+	}
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParams(query).
+		SetResult(&result).
+		Get("")
+
+	if err != nil {
+		return result, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext(errContext)
+	}
+
+	if !resp.IsSuccess() {
+		return result, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext(errContext)
+	}
+
+	return result, nil
+}
+
+// resultReason returns the error string Etherscan put in result, or the message if there is none
+func resultReason(result statusResponse) string {
+	var reason string
+	if err := json.Unmarshal(result.Result, &reason); err != nil || reason == "" {
+		return result.Message
+	}
+	return reason
+}
+
+// isProOnly reports whether an Etherscan error says the endpoint needs an API Pro key,
+// e.g. "Sorry, it looks like you are trying to access an API Pro endpoint."
+func isProOnly(reason string) bool {
+	return strings.Contains(strings.ToLower(reason), "api pro")
+}
@@ -0,0 +1,125 @@
+package etherscan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+const (
+	proOnlyResult     = `{"status":"0","message":"NOTOK","result":"Sorry, it looks like you are trying to access an API Pro endpoint. Contact us to upgrade to API Pro."}`
+	rateLimitedResult = `{"status":"0","message":"NOTOK","result":"Max rate limit reached"}`
+)
+
+// newAtBlockServer answers balancehistory, getblockreward, ethdailyprice, and ethprice.
+// An empty response for an action answers it as Etherscan does for a free API key.
+func newAtBlockServer(t *testing.T, responses map[string]string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		action := query.Get("action")
+
+		switch action {
+		case "balancehistory":
+			if query.Get("blockno") != "8000000" {
+				t.Errorf("blockno = %q, want 8000000", query.Get("blockno"))
+			}
+		case "ethdailyprice":
+			if query.Get("startdate") != "2019-06-01" || query.Get("enddate") != "2019-06-01" {
+				t.Errorf("dates = %s..%s, want 2019-06-01..2019-06-01", query.Get("startdate"), query.Get("enddate"))
+			}
+		}
+
+		body, ok := responses[action]
+		if !ok || body == "" {
+			body = proOnlyResult
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+}
+
+func TestWalletFetcher_FetchAtBlock(t *testing.T) {
+	balance := `{"status":"1","message":"OK","result":"2000000000000000000"}`
+	blockReward := `{"status":"1","message":"OK","result":{"blockNumber":"8000000","timeStamp":"1559347200"}}`
+	dailyPrice := `{"status":"1","message":"OK","result":[{"UTCDate":"2019-06-01","unixTimeStamp":"1559347200","value":"265.50"}]}`
+	currentPrice := `{"status":"1","message":"OK","result":{"ethusd":"3000.00"}}`
+
+	tests := []struct {
+		name      string
+		responses map[string]string
+		want      float64
+		wantType  fetcherpkg.ErrorType
+	}{
+		{
+			name:      "historical price",
+			responses: map[string]string{"balancehistory": balance, "getblockreward": blockReward, "ethdailyprice": dailyPrice, "ethprice": currentPrice},
+			want:      531.00,
+		},
+		{
+			name:      "no historical price falls back to current",
+			responses: map[string]string{"balancehistory": balance, "getblockreward": blockReward, "ethprice": currentPrice},
+			want:      6000.00,
+		},
+		{
+			name:      "free tier",
+			responses: map[string]string{"getblockreward": blockReward, "ethprice": currentPrice},
+			wantType:  fetcherpkg.ErrorTypeClient,
+		},
+		{
+			name:      "balance not found",
+			responses: map[string]string{"balancehistory": `{"status":"0","message":"NOTOK","result":"Error! Block number too large"}`},
+			wantType:  fetcherpkg.ErrorTypeValidation,
+		},
+		{
+			name:      "rate limited",
+			responses: map[string]string{"balancehistory": rateLimitedResult},
+			wantType:  fetcherpkg.ErrorTypeRateLimit,
+		},
+		{
+			name:      "rate limited dating the block",
+			responses: map[string]string{"balancehistory": balance, "getblockreward": rateLimitedResult, "ethprice": currentPrice},
+			wantType:  fetcherpkg.ErrorTypeRateLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newAtBlockServer(t, tt.responses)
+			defer server.Close()
+
+			fetcher := NewWalletFetcher("test_key", "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb", server.URL)
+
+			got, err := fetcher.FetchAtBlock(context.Background(), 8000000)
+			if tt.wantType != "" {
+				if fetcherpkg.ErrorTypeOf(err) != tt.wantType {
+					t.Fatalf("FetchAtBlock() error = %v, want %s error", err, tt.wantType)
+				}
+				if tt.wantType == fetcherpkg.ErrorTypeClient && !strings.Contains(err.Error(), "API Pro") {
+					t.Errorf("FetchAtBlock() error = %q, want it to mention API Pro", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchAtBlock() returned unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FetchAtBlock() = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalletFetcher_FetchAtBlock_NegativeBlock(t *testing.T) {
+	fetcher := NewWalletFetcher("test_key", "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb", "http://localhost")
+
+	_, err := fetcher.FetchAtBlock(context.Background(), -1)
+	if fetcherpkg.ErrorTypeOf(err) != fetcherpkg.ErrorTypeValidation {
+		t.Errorf("FetchAtBlock(-1) error = %v, want validation error", err)
+	}
+}
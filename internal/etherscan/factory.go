@@ -0,0 +1,15 @@
+package etherscan
+
+import "financefetcher/internal/fetcher"
+
+func init() {
+	fetcher.Register("etherscan", newFromParams)
+}
+
+// newFromParams builds a WalletFetcher from the "api_key", "address", and "base_url" parameters
+func newFromParams(params fetcher.Params) (fetcher.Fetcher, error) {
+	if err := params.Require("api_key", "address", "base_url"); err != nil {
+		return nil, err
+	}
+	return NewWalletFetcher(params["api_key"], params["address"], params["base_url"]), nil
+}
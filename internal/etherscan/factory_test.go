@@ -0,0 +1,25 @@
+package etherscan
+
+import (
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestFactory(t *testing.T) {
+	f, err := fetcherpkg.New("etherscan", fetcherpkg.Params{
+		"api_key":  "test_key",
+		"address":  "0x123",
+		"base_url": "http://localhost",
+	})
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	if got := f.Key(); got != "fetcher:etherscan:0x123" {
+		t.Errorf("Key() = %q, want %q", got, "fetcher:etherscan:0x123")
+	}
+
+	if _, err := fetcherpkg.New("etherscan", fetcherpkg.Params{"address": "0x123"}); err == nil {
+		t.Error("New() expected error for missing api_key and base_url, got nil")
+	}
+}
@@ -0,0 +1,173 @@
+package etherscan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+	"financefetcher/internal/wei"
+)
+
+const (
+	// txListPageSize is how many transactions each txlist request returns
+	txListPageSize = 1000
+
+	// maxTxListPages caps the requests per fetch. Etherscan only pages through the first
+	// 10,000 results of a query, so busier addresses need a later sinceBlock.
+	maxTxListPages = 10
+
+	// latestBlock is the end block Etherscan's documentation uses to mean "up to now"
+	latestBlock = "99999999"
+)
+
+// TxListResponse represents the Etherscan API response for an address's normal transactions.
+// Result is a list of transactions on success but an error string on failure, so it is
+// decoded separately.
+type TxListResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// TxRow is a single transaction; only the fields needed to compute its fee are decoded
+type TxRow struct {
+	Hash     string `json:"hash"`
+	From     string `json:"from"`
+	GasUsed  string `json:"gasUsed"`
+	GasPrice string `json:"gasPrice"` // Effective price per unit of gas, in wei
+}
+
+// GasSpendFetcher fetches how much an address has spent on transaction fees since a
+// block, in USD. Only transactions the address sent are counted, including failed ones,
+// which still pay for their gas. The total is valued at the current ETH price, not the
+// price when each transaction was sent.
+//
+// Every page of history is a separate rate-limited request, so a busy address can take
+// several requests (up to maxTxListPages) per fetch.
+type GasSpendFetcher struct {
+	wallet     *WalletFetcher
+	sinceBlock uint64
+}
+
+// NewGasSpendFetcher creates a fetcher for the fees address has paid from sinceBlock on.
+// Options apply to the underlying wallet fetcher, whose client and price source it shares.
+func NewGasSpendFetcher(apiKey, address string, sinceBlock uint64, baseURL string, opts ...Option) *GasSpendFetcher {
+	return &GasSpendFetcher{
+		wallet:     NewWalletFetcher(apiKey, address, baseURL, opts...),
+		sinceBlock: sinceBlock,
+	}
+}
+
+// Fetch sums gasUsed * gasPrice over the address's outgoing transactions and returns
+// the total in USD
+func (f *GasSpendFetcher) Fetch(ctx context.Context) (float64, error) {
+	ethUSD, err := f.wallet.fetchEthPrice(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	totalWei := new(big.Int)
+	for page := 1; ; page++ {
+		if page > maxTxListPages {
+			return 0, fetcher.NewValidationError(fmt.Sprintf("%s has more than %d transactions since block %d; use a later start block",
+				f.wallet.address, maxTxListPages*txListPageSize, f.sinceBlock))
+		}
+
+		txs, err := f.fetchTransactions(ctx, page)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, tx := range txs {
+			// Fees are paid by the sender, so incoming transactions cost this address nothing
+			if !strings.EqualFold(tx.From, f.wallet.address) {
+				continue
+			}
+
+			fee, err := transactionFee(tx)
+			if err != nil {
+				return 0, err
+			}
+			totalWei.Add(totalWei, fee)
+		}
+
+		if len(txs) < txListPageSize {
+			break
+		}
+	}
+
+	return wei.ToUSD(totalWei, ethUSD), nil
+}
+
+// fetchTransactions returns one page of the address's transactions since sinceBlock,
+// oldest first
+func (f *GasSpendFetcher) fetchTransactions(ctx context.Context, page int) ([]TxRow, error) {
+	// Apply rate limiting; each page is its own request
+	limiter := ratelimit.GetLimiter()
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIEtherscan)
+	if err != nil {
+		return nil, fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIEtherscan, "action", "txlist", "wait_duration", waited)
+
+	slog.Debug("fetching transactions from Etherscan", "run_id", fetcher.RunIDFromContext(ctx), "address", f.wallet.address, "page", page)
+
+	var result TxListResponse
+
+	resp, err := f.wallet.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"chainid":    mainnetChainID,
+			"module":     "account",
+			"action":     "txlist",
+			"address":    f.wallet.address,
+			"startblock": strconv.FormatUint(f.sinceBlock, 10),
+			"endblock":   latestBlock,
+			"page":       strconv.Itoa(page),
+			"offset":     strconv.Itoa(txListPageSize),
+			"sort":       "asc",
+			"apikey":     f.wallet.apiKey,
+		}).
+		SetResult(&result).
+		Get("")
+
+	if err != nil {
+		return nil, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch transactions for " + f.wallet.address)
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch transactions for " + f.wallet.address)
+	}
+
+	// Etherscan reports an empty history as status 0 with an empty list
+	var txs []TxRow
+	if err := json.Unmarshal(result.Result, &txs); err != nil {
+		return nil, statusError(statusResponse(result)).WithContext("failed to list transactions for " + f.wallet.address)
+	}
+
+	return txs, nil
+}
+
+// transactionFee returns gasUsed * gasPrice in wei
+func transactionFee(tx TxRow) (*big.Int, error) {
+	gasUsed, ok := new(big.Int).SetString(tx.GasUsed, 10)
+	if !ok {
+		return nil, fetcher.NewValidationError(fmt.Sprintf("failed to parse gasUsed %q for transaction %s", tx.GasUsed, tx.Hash))
+	}
+	gasPrice, ok := new(big.Int).SetString(tx.GasPrice, 10)
+	if !ok {
+		return nil, fetcher.NewValidationError(fmt.Sprintf("failed to parse gasPrice %q for transaction %s", tx.GasPrice, tx.Hash))
+	}
+	return gasUsed.Mul(gasUsed, gasPrice), nil
+}
+
+// Key returns the Redis key for this fetcher
+func (f *GasSpendFetcher) Key() string {
+	return fmt.Sprintf("fetcher:etherscan:gas:%s", f.wallet.address)
+}
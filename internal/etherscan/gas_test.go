@@ -0,0 +1,174 @@
+package etherscan
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"financefetcher/internal/fetcher"
+)
+
+func TestGasSpendFetcher_Fetch(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		query := r.URL.Query()
+		switch query.Get("action") {
+		case "ethprice":
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "2000.00"}}`))
+		case "txlist":
+			if query.Get("startblock") != "18000000" {
+				t.Errorf("startblock = %q, want 18000000", query.Get("startblock"))
+			}
+			if query.Get("sort") != "asc" {
+				t.Errorf("sort = %q, want asc", query.Get("sort"))
+			}
+			// 21000 gas at 50 gwei = 0.00105 ETH; the incoming transfer's fee was paid by its sender
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": [
+				{"hash": "0x1", "from": "0xABC", "gasUsed": "21000", "gasPrice": "50000000000"},
+				{"hash": "0x2", "from": "0xdef", "gasUsed": "21000", "gasPrice": "50000000000"},
+				{"hash": "0x3", "from": "0xabc", "gasUsed": "100000", "gasPrice": "30000000000"}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	f := NewGasSpendFetcher("test_key", "0xabc", 18000000, server.URL)
+
+	value, err := f.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	// (0.00105 + 0.003) ETH * $2000
+	if want := 8.10; value != want {
+		t.Errorf("Fetch() = %.2f, want %.2f", value, want)
+	}
+	if got, want := f.Key(), "fetcher:etherscan:gas:0xabc"; got != want {
+		t.Errorf("Key() = %q, want %q", got, want)
+	}
+}
+
+func TestGasSpendFetcher_Fetch_Paginates(t *testing.T) {
+	var pages atomic.Int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		query := r.URL.Query()
+		switch query.Get("action") {
+		case "ethprice":
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "1000.00"}}`))
+		case "txlist":
+			pages.Add(1)
+
+			// A full first page means there may be more; the second page is the last
+			count := txListPageSize
+			if query.Get("page") == "2" {
+				count = 1
+			}
+
+			rows := make([]string, count)
+			for i := range rows {
+				// 1e15 wei (0.001 ETH) each
+				rows[i] = fmt.Sprintf(`{"hash": "0x%d", "from": "0xabc", "gasUsed": "1000000", "gasPrice": "1000000000"}`, i)
+			}
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": [` + strings.Join(rows, ",") + `]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	f := NewGasSpendFetcher("test_key", "0xabc", 0, server.URL)
+
+	value, err := f.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	if got := pages.Load(); got != 2 {
+		t.Errorf("txlist requests = %d, want 2", got)
+	}
+
+	// 1001 transactions * 0.001 ETH * $1000
+	if want := 1001.0; value != want {
+		t.Errorf("Fetch() = %.2f, want %.2f", value, want)
+	}
+}
+
+func TestGasSpendFetcher_Fetch_NoTransactions(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("action") == "ethprice" {
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "2000.00"}}`))
+			return
+		}
+		w.Write([]byte(`{"status": "0", "message": "No transactions found", "result": []}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	value, err := NewGasSpendFetcher("test_key", "0xabc", 0, server.URL).Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+	if value != 0 {
+		t.Errorf("Fetch() = %.2f, want 0", value)
+	}
+}
+
+func TestGasSpendFetcher_Fetch_APIError(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("action") == "ethprice" {
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "2000.00"}}`))
+			return
+		}
+		w.Write([]byte(`{"status": "0", "message": "NOTOK", "result": "Invalid API Key"}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	_, err := NewGasSpendFetcher("bad_key", "0xabc", 0, server.URL).Fetch(context.Background())
+	if fetcher.ErrorTypeOf(err) != fetcher.ErrorTypeValidation {
+		t.Fatalf("Fetch() error = %v, want validation error", err)
+	}
+	if !strings.Contains(err.Error(), "Invalid API Key") {
+		t.Errorf("Fetch() error = %q, want it to include Etherscan's message", err.Error())
+	}
+}
+
+func TestGasSpendFetcher_Fetch_RateLimited(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("action") == "ethprice" {
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "2000.00"}}`))
+			return
+		}
+		w.Write([]byte(`{"status": "0", "message": "NOTOK", "result": "Max rate limit reached"}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	_, err := NewGasSpendFetcher("test_key", "0xabc", 0, server.URL).Fetch(context.Background())
+	if fetcher.ErrorTypeOf(err) != fetcher.ErrorTypeRateLimit {
+		t.Fatalf("Fetch() error = %v, want rate limit error", err)
+	}
+	if !strings.Contains(err.Error(), "Max rate limit reached") {
+		t.Errorf("Fetch() error = %q, want it to include Etherscan's message", err.Error())
+	}
+}
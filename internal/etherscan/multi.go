@@ -0,0 +1,146 @@
+package etherscan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+
+	"resty.dev/v3"
+)
+
+const (
+	// maxAddressesPerBalanceMulti is the maximum number of addresses Etherscan accepts per balancemulti call
+	maxAddressesPerBalanceMulti = 20
+)
+
+// BalanceMultiResponse represents the Etherscan API response for multiple account balances.
+// On success Result holds a list of BalanceMultiEntry; on failure Status is "0" and Result
+// holds an error string such as "Max rate limit reached".
+type BalanceMultiResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// BalanceMultiEntry is one account's balance in a BalanceMultiResponse
+type BalanceMultiEntry struct {
+	Account string `json:"account"`
+	Balance string `json:"balance"` // Balance in wei as a string
+}
+
+// MultiWalletFetcher fetches the USD balances of many wallets using Etherscan's
+// balancemulti action, which returns up to 20 balances per request. A 20-wallet
+// portfolio needs 2 requests (one ethprice, one balancemulti) instead of 40.
+type MultiWalletFetcher struct {
+	apiKey    string
+	addresses []string
+	client    *resty.Client
+}
+
+// NewMultiWalletFetcher creates a new fetcher for the given wallet addresses
+func NewMultiWalletFetcher(apiKey string, addresses []string, baseURL string) *MultiWalletFetcher {
+	client := fetcher.NewHTTPClient(baseURL, fetcher.WithRateLimitedRetries(ratelimit.APIEtherscan))
+
+	return &MultiWalletFetcher{
+		apiKey:    apiKey,
+		addresses: addresses,
+		client:    client,
+	}
+}
+
+// FetchAll retrieves the USD balance of every address, returning a map of address to value.
+// The ETH price is fetched once and shared across all addresses. Addresses are queried in
+// chunks of 20; if a chunk fails, the balances from other chunks are still returned along
+// with the joined errors.
+func (f *MultiWalletFetcher) FetchAll(ctx context.Context) (map[string]float64, error) {
+	ethUSD, err := cachedEthPrice(ctx, f.client, f.apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]float64, len(f.addresses))
+	var errs []error
+
+	for start := 0; start < len(f.addresses); start += maxAddressesPerBalanceMulti {
+		end := min(start+maxAddressesPerBalanceMulti, len(f.addresses))
+
+		balances, err := f.fetchBalances(ctx, f.addresses[start:end])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, address := range f.addresses[start:end] {
+			wei, ok := balances[strings.ToLower(address)]
+			if !ok {
+				errs = append(errs, fetcher.NewValidationError(fmt.Sprintf("balance not found in response for %s", address)))
+				continue
+			}
+
+			usdValue, err := weiToUSD(wei, ethUSD)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			values[address] = usdValue
+		}
+	}
+
+	return values, errors.Join(errs...)
+}
+
+// fetchBalances gets the wei balances for up to 20 addresses, keyed by lowercased address
+func (f *MultiWalletFetcher) fetchBalances(ctx context.Context, addresses []string) (map[string]string, error) {
+	// Apply rate limiting
+	limiter := ratelimit.GetLimiter()
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIEtherscan)
+	if err != nil {
+		return nil, fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIEtherscan, "action", "balancemulti", "wait_duration", waited)
+
+	slog.Debug("fetching wallet balances from Etherscan", "run_id", fetcher.RunIDFromContext(ctx), "addresses", len(addresses))
+
+	var result BalanceMultiResponse
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"chainid": mainnetChainID,
+			"module":  "account",
+			"action":  "balancemulti",
+			"address": strings.Join(addresses, ","),
+			"tag":     "latest",
+			"apikey":  f.apiKey,
+		}).
+		SetResult(&result).
+		Get("")
+
+	if err != nil {
+		return nil, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch wallet balances")
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch wallet balances")
+	}
+
+	// Etherscan reports errors with HTTP 200, status "0" and the reason in result
+	var entries []BalanceMultiEntry
+	if result.Status != "1" || json.Unmarshal(result.Result, &entries) != nil {
+		return nil, statusError(statusResponse(result)).WithContext("failed to fetch wallet balances")
+	}
+
+	// Etherscan may return checksummed or lowercased addresses, so match case-insensitively
+	balances := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		balances[strings.ToLower(entry.Account)] = entry.Balance
+	}
+
+	return balances, nil
+}
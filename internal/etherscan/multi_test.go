@@ -0,0 +1,171 @@
+package etherscan
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"financefetcher/internal/fetcher"
+)
+
+func TestMultiWalletFetcher_FetchAll(t *testing.T) {
+	var requests atomic.Int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		switch r.URL.Query().Get("action") {
+		case "ethprice":
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "2000.00"}}`))
+		case "balancemulti":
+			// Etherscan echoes addresses back lowercased
+			w.Write([]byte(`{
+				"status": "1",
+				"message": "OK",
+				"result": [
+					{"account": "0xabc", "balance": "1000000000000000000"},
+					{"account": "0xdef", "balance": "500000000000000000"}
+				]
+			}`))
+		default:
+			t.Errorf("unexpected action %q", r.URL.Query().Get("action"))
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewMultiWalletFetcher("test_key", []string{"0xABC", "0xDEF"}, server.URL)
+
+	values, err := fetcher.FetchAll(context.Background())
+	if err != nil {
+		t.Fatalf("FetchAll() returned unexpected error: %v", err)
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server received %d requests, want 2", got)
+	}
+
+	want := map[string]float64{
+		"0xABC": 2000.00,
+		"0xDEF": 1000.00,
+	}
+	for address, value := range want {
+		if values[address] != value {
+			t.Errorf("values[%q] = %.2f, want %.2f", address, values[address], value)
+		}
+	}
+}
+
+func TestMultiWalletFetcher_FetchAll_Chunks(t *testing.T) {
+	var balanceRequests atomic.Int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		if r.URL.Query().Get("action") == "ethprice" {
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "1000.00"}}`))
+			return
+		}
+
+		balanceRequests.Add(1)
+		addresses := strings.Split(r.URL.Query().Get("address"), ",")
+		if len(addresses) > 20 {
+			t.Errorf("balancemulti called with %d addresses, want at most 20", len(addresses))
+		}
+
+		entries := make([]string, len(addresses))
+		for i, address := range addresses {
+			entries[i] = fmt.Sprintf(`{"account": %q, "balance": "1000000000000000000"}`, address)
+		}
+		w.Write([]byte(`{"status": "1", "message": "OK", "result": [` + strings.Join(entries, ",") + `]}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	addresses := make([]string, 25)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("0x%d", i)
+	}
+
+	fetcher := NewMultiWalletFetcher("test_key", addresses, server.URL)
+
+	values, err := fetcher.FetchAll(context.Background())
+	if err != nil {
+		t.Fatalf("FetchAll() returned unexpected error: %v", err)
+	}
+
+	if got := balanceRequests.Load(); got != 2 {
+		t.Errorf("balancemulti called %d times, want 2", got)
+	}
+	if len(values) != len(addresses) {
+		t.Errorf("FetchAll() returned %d values, want %d", len(values), len(addresses))
+	}
+}
+
+func TestMultiWalletFetcher_FetchAll_MissingAddress(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		if r.URL.Query().Get("action") == "ethprice" {
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "1000.00"}}`))
+			return
+		}
+		w.Write([]byte(`{"status": "1", "message": "OK", "result": [{"account": "0xabc", "balance": "1000000000000000000"}]}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewMultiWalletFetcher("test_key", []string{"0xabc", "0xdef"}, server.URL)
+
+	values, err := fetcher.FetchAll(context.Background())
+	if err == nil {
+		t.Error("FetchAll() expected error for missing address, got nil")
+	}
+	if values["0xabc"] != 1000.00 {
+		t.Errorf("values[%q] = %.2f, want 1000.00", "0xabc", values["0xabc"])
+	}
+}
+
+func TestMultiWalletFetcher_FetchAll_RateLimited(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		if r.URL.Query().Get("action") == "ethprice" {
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "1000.00"}}`))
+			return
+		}
+		// Etherscan reports throttling with HTTP 200 and a string result
+		w.Write([]byte(`{"status": "0", "message": "NOTOK", "result": "Max rate limit reached"}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	f := NewMultiWalletFetcher("test_key", []string{"0xabc"}, server.URL)
+
+	values, err := f.FetchAll(context.Background())
+	if got := fetcher.ErrorTypeOf(err); got != fetcher.ErrorTypeRateLimit {
+		t.Fatalf("FetchAll() error = %v, want a rate limit error", err)
+	}
+	if !fetcher.IsRetryable(err) {
+		t.Errorf("FetchAll() error = %v, want retryable", err)
+	}
+	if !strings.Contains(err.Error(), "Max rate limit reached") {
+		t.Errorf("FetchAll() error = %q, want Etherscan's reason", err)
+	}
+	if len(values) != 0 {
+		t.Errorf("FetchAll() values = %v, want none", values)
+	}
+}
@@ -0,0 +1,393 @@
+package etherscan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+)
+
+// TokenBalanceResponse represents the Etherscan API response for an ERC-20 token balance.
+// Result is the balance in the token's smallest unit as a string on success, and the error
+// reason when Status is "0".
+type TokenBalanceResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// TokenTxResponse represents the Etherscan API response for a wallet's ERC-20 transfer history.
+// Result is a list of TokenTxRow on success but an error string on failure, so it is decoded
+// separately.
+type TokenTxResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// TokenTxRow is a single ERC-20 transfer; only the fields needed to identify the token are decoded
+type TokenTxRow struct {
+	ContractAddress string `json:"contractAddress"`
+	TokenSymbol     string `json:"tokenSymbol"`
+	TokenDecimal    string `json:"tokenDecimal"`
+}
+
+const (
+	// tokenDiscoveryPageSize is how many recent transfers are scanned for token contracts
+	tokenDiscoveryPageSize = 100
+
+	// maxDiscoveredTokens caps the balance lookups discovery adds to each fetch, since
+	// wallets routinely receive spam token airdrops
+	maxDiscoveredTokens = 20
+)
+
+// TokenPriceFunc returns a price fetcher for a discovered token, or nil if the token
+// can't be priced and should be left out of the total
+type TokenPriceFunc func(symbol, contract string) fetcher.Fetcher
+
+// TokenBalanceReader reads a wallet's raw balances of many ERC-20 tokens in one request,
+// such as ethrpc.TokenBalanceReader's on-chain multicall. Balances are returned in the
+// order of contracts, with nil for any token whose balance couldn't be read.
+type TokenBalanceReader interface {
+	TokenBalances(ctx context.Context, owner string, contracts []string) ([]*big.Int, error)
+}
+
+// TokenSpec describes an ERC-20 token held by a wallet
+type TokenSpec struct {
+	// Symbol is a display name used in logs, e.g. "USDC"
+	Symbol string
+
+	// Contract is the token's contract address
+	Contract string
+
+	// Decimals is the number of decimals the token uses (e.g. 6 for USDC, 18 for most tokens)
+	Decimals int
+
+	// Price returns the USD price of one whole token. When nil, the token is priced by
+	// Symbol through the wallet's price source (see WithPriceSource).
+	Price fetcher.Fetcher
+}
+
+// PortfolioWalletFetcher fetches a wallet's total USD value across native ETH and a list
+// of ERC-20 tokens. The ETH balance must succeed; a token whose balance or price can't
+// be fetched is logged and left out of the total.
+type PortfolioWalletFetcher struct {
+	wallet *WalletFetcher
+	tokens []TokenSpec
+
+	// discoverPrices enables token discovery and prices the tokens it finds; nil disables it
+	discoverPrices TokenPriceFunc
+
+	// balanceReader reads all token balances in one request; nil uses one Etherscan call per token
+	balanceReader TokenBalanceReader
+}
+
+// NewPortfolioWalletFetcher creates a fetcher for the combined ETH and token value of address.
+// Options apply to the underlying wallet fetcher, whose client also serves the token requests.
+func NewPortfolioWalletFetcher(apiKey, address string, tokens []TokenSpec, baseURL string, opts ...Option) *PortfolioWalletFetcher {
+	return &PortfolioWalletFetcher{
+		wallet: NewWalletFetcher(apiKey, address, baseURL, opts...),
+		tokens: tokens,
+	}
+}
+
+// EnableTokenDiscovery makes each fetch also scan the wallet's recent ERC-20 transfers
+// (Etherscan's tokentx) for tokens it holds, valuing any not already configured using
+// the fetcher prices returns. Discovery costs one extra request plus a balance lookup per
+// token, all through the Etherscan rate limiter, so it is off by default. At most
+// maxDiscoveredTokens tokens are added per fetch.
+func (f *PortfolioWalletFetcher) EnableTokenDiscovery(prices TokenPriceFunc) {
+	f.discoverPrices = prices
+}
+
+// SetTokenBalanceReader reads token balances through reader (e.g. an
+// ethrpc.NewTokenBalanceReader multicall) in a single request instead of one Etherscan
+// tokenbalance call per token. If the batch fails, the fetch falls back to the per-token
+// Etherscan calls. A nil reader restores the Etherscan-only behavior.
+func (f *PortfolioWalletFetcher) SetTokenBalanceReader(reader TokenBalanceReader) {
+	f.balanceReader = reader
+}
+
+// Fetch retrieves the wallet's ETH value plus the value of each configured token
+func (f *PortfolioWalletFetcher) Fetch(ctx context.Context) (float64, error) {
+	total, err := f.wallet.Fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tokens := f.tokens
+	if f.discoverPrices != nil {
+		discovered, err := f.discoverTokens(ctx)
+		if err != nil {
+			return 0, err
+		}
+		tokens = append(tokens[:len(tokens):len(tokens)], discovered...)
+		if err := ctx.Err(); err != nil {
+			return 0, fetcher.ClassifyLimiterError(err)
+		}
+	}
+
+	balances := f.batchTokenBalances(ctx, tokens)
+
+	for i, token := range tokens {
+		var balance *big.Int
+		if balances != nil {
+			balance = balances[i]
+		}
+
+		value, err := f.tokenValue(ctx, token, balance)
+		if err != nil {
+			// Only a token's own data problems are skipped; a canceled or timed-out run
+			// would otherwise report an under-counted total as a success
+			if abortErr := abortError(ctx, err); abortErr != nil {
+				return 0, abortErr
+			}
+			slog.Warn("skipping token in wallet total", "run_id", fetcher.RunIDFromContext(ctx), "address", f.wallet.address, "token", token.Symbol, "contract", token.Contract, "error", err)
+			continue
+		}
+		total += value
+	}
+
+	return total, nil
+}
+
+// abortError returns the error that should end the fetch when err means the whole run is
+// being abandoned (ctx is done, or err is itself a cancellation or timeout) or Etherscan is
+// rate limiting the key, and nil when err only concerns one token. Skipping tokens while
+// rate limited would report an under-counted total that retrying could have avoided.
+func abortError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fetcher.ClassifyLimiterError(ctxErr)
+	}
+	switch fetcher.ErrorTypeOf(err) {
+	case fetcher.ErrorTypeCanceled, fetcher.ErrorTypeTimeout, fetcher.ErrorTypeRateLimit:
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return nil
+}
+
+// discoverTokens returns the tokens found in the wallet's recent transfers that aren't
+// already configured and can be priced. A failed lookup is logged and discovers nothing,
+// unless abortError says it should end the fetch.
+func (f *PortfolioWalletFetcher) discoverTokens(ctx context.Context) ([]TokenSpec, error) {
+	rows, err := f.fetchTokenTransfers(ctx)
+	if err != nil {
+		if abortErr := abortError(ctx, err); abortErr != nil {
+			return nil, abortErr
+		}
+		slog.Warn("token discovery failed", "run_id", fetcher.RunIDFromContext(ctx), "address", f.wallet.address, "error", err)
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(f.tokens))
+	for _, token := range f.tokens {
+		seen[strings.ToLower(token.Contract)] = true
+	}
+
+	var discovered []TokenSpec
+	for _, row := range rows {
+		contract := strings.ToLower(row.ContractAddress)
+		if contract == "" || seen[contract] {
+			continue
+		}
+		seen[contract] = true
+
+		decimals, err := strconv.Atoi(row.TokenDecimal)
+		if err != nil {
+			slog.Debug("skipping discovered token with invalid decimals", "token", row.TokenSymbol, "contract", contract, "decimals", row.TokenDecimal)
+			continue
+		}
+
+		price := f.discoverPrices(row.TokenSymbol, contract)
+		if price == nil {
+			slog.Debug("skipping discovered token without a price source", "token", row.TokenSymbol, "contract", contract)
+			continue
+		}
+
+		if len(discovered) == maxDiscoveredTokens {
+			slog.Warn("token discovery limit reached, ignoring remaining tokens", "run_id", fetcher.RunIDFromContext(ctx), "address", f.wallet.address, "limit", maxDiscoveredTokens)
+			break
+		}
+		discovered = append(discovered, TokenSpec{Symbol: row.TokenSymbol, Contract: contract, Decimals: decimals, Price: price})
+	}
+
+	return discovered, nil
+}
+
+// fetchTokenTransfers gets the wallet's most recent ERC-20 transfers, newest first
+func (f *PortfolioWalletFetcher) fetchTokenTransfers(ctx context.Context) ([]TokenTxRow, error) {
+	// Apply rate limiting
+	limiter := ratelimit.GetLimiter()
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIEtherscan)
+	if err != nil {
+		return nil, fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIEtherscan, "action", "tokentx", "wait_duration", waited)
+
+	var result TokenTxResponse
+
+	resp, err := f.wallet.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"chainid": mainnetChainID,
+			"module":  "account",
+			"action":  "tokentx",
+			"address": f.wallet.address,
+			"page":    "1",
+			"offset":  strconv.Itoa(tokenDiscoveryPageSize),
+			"sort":    "desc",
+			"apikey":  f.wallet.apiKey,
+		}).
+		SetResult(&result).
+		Get("")
+
+	if err != nil {
+		return nil, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch token transfers")
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch token transfers")
+	}
+
+	// Etherscan reports an empty history as status 0 with an empty list
+	var rows []TokenTxRow
+	if err := json.Unmarshal(result.Result, &rows); err != nil {
+		return nil, statusError(statusResponse(result)).WithContext("failed to fetch token transfers")
+	}
+
+	return rows, nil
+}
+
+// batchTokenBalances reads every token balance with the balance reader, if one is set.
+// It returns nil, leaving each balance to be fetched from Etherscan, when there is no
+// reader or the batch fails.
+func (f *PortfolioWalletFetcher) batchTokenBalances(ctx context.Context, tokens []TokenSpec) []*big.Int {
+	if f.balanceReader == nil || len(tokens) == 0 {
+		return nil
+	}
+
+	contracts := make([]string, len(tokens))
+	for i, token := range tokens {
+		contracts[i] = token.Contract
+	}
+
+	balances, err := f.balanceReader.TokenBalances(ctx, f.wallet.address, contracts)
+	if err == nil && len(balances) != len(contracts) {
+		err = fmt.Errorf("got %d balances for %d tokens", len(balances), len(contracts))
+	}
+	if err != nil {
+		slog.Warn("batched token balance lookup failed, falling back to Etherscan", "run_id", fetcher.RunIDFromContext(ctx), "address", f.wallet.address, "error", err)
+		return nil
+	}
+
+	return balances
+}
+
+// tokenValue returns the USD value of the wallet's balance of token. A nil balance is
+// fetched from Etherscan first.
+func (f *PortfolioWalletFetcher) tokenValue(ctx context.Context, token TokenSpec, balance *big.Int) (float64, error) {
+	if balance == nil {
+		var err error
+		balance, err = f.fetchTokenBalance(ctx, token.Contract)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if balance.Sign() == 0 {
+		return 0, nil
+	}
+
+	price, err := f.tokenPrice(ctx, token)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch %s price: %w", token.Symbol, err)
+	}
+
+	return tokenUnitsToUSD(balance, token.Decimals, price), nil
+}
+
+// tokenPrice returns the USD price of one whole token from its own price fetcher, falling
+// back to the wallet's price source
+func (f *PortfolioWalletFetcher) tokenPrice(ctx context.Context, token TokenSpec) (float64, error) {
+	if token.Price != nil {
+		return token.Price.Fetch(ctx)
+	}
+	if f.wallet.priceSource != nil {
+		return f.wallet.priceSource.PriceUSD(ctx, token.Symbol)
+	}
+	return 0, fetcher.NewValidationError("no price fetcher or price source configured")
+}
+
+// fetchTokenBalance gets the wallet's raw balance of the ERC-20 token at contract
+func (f *PortfolioWalletFetcher) fetchTokenBalance(ctx context.Context, contract string) (*big.Int, error) {
+	// Apply rate limiting
+	limiter := ratelimit.GetLimiter()
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIEtherscan)
+	if err != nil {
+		return nil, fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIEtherscan, "action", "tokenbalance", "wait_duration", waited)
+
+	slog.Debug("fetching token balance from Etherscan", "run_id", fetcher.RunIDFromContext(ctx), "address", f.wallet.address, "contract", contract)
+
+	var result TokenBalanceResponse
+
+	resp, err := f.wallet.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"chainid":         mainnetChainID,
+			"module":          "account",
+			"action":          "tokenbalance",
+			"contractaddress": contract,
+			"address":         f.wallet.address,
+			"tag":             "latest",
+			"apikey":          f.wallet.apiKey,
+		}).
+		SetResult(&result).
+		Get("")
+
+	if err != nil {
+		return nil, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch token balance for " + contract)
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch token balance for " + contract)
+	}
+
+	var units string
+	if result.Status != "1" || json.Unmarshal(result.Result, &units) != nil {
+		return nil, statusError(statusResponse(result)).WithContext("failed to fetch token balance for " + contract)
+	}
+
+	balance, ok := new(big.Int).SetString(units, 10)
+	if !ok {
+		return nil, fetcher.NewValidationError(fmt.Sprintf("failed to parse token balance for %s: %q", contract, units))
+	}
+
+	return balance, nil
+}
+
+// tokenUnitsToUSD converts a raw token balance with the given decimals to USD at price per token
+func tokenUnitsToUSD(units *big.Int, decimals int, price float64) float64 {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+
+	tokens := new(big.Float).SetInt(units)
+	tokens.Quo(tokens, new(big.Float).SetInt(scale))
+	tokensFloat, _ := tokens.Float64()
+
+	return tokensFloat * price
+}
+
+// Key returns the Redis key for this fetcher
+func (f *PortfolioWalletFetcher) Key() string {
+	return fmt.Sprintf("fetcher:etherscan:portfolio:%s", f.wallet.address)
+}
@@ -0,0 +1,364 @@
+package etherscan
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/testutil"
+)
+
+// newPortfolioServer answers ethprice, balance (1 ETH), tokenbalance, and tokentx calls.
+// Token balances are looked up by contract address; tokentx reports transfers of USDC,
+// LINK (twice), and an unpriced spam token.
+func newPortfolioServer(t *testing.T, tokenBalances map[string]string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Query().Get("action") {
+		case "ethprice":
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "2000.00"}}`))
+		case "balance":
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": "1000000000000000000"}`))
+		case "tokenbalance":
+			balance, ok := tokenBalances[r.URL.Query().Get("contractaddress")]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": "` + balance + `"}`))
+		case "tokentx":
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": [
+				{"contractAddress": "0xUSDC", "tokenSymbol": "USDC", "tokenDecimal": "6"},
+				{"contractAddress": "0xlink", "tokenSymbol": "LINK", "tokenDecimal": "18"},
+				{"contractAddress": "0xspam", "tokenSymbol": "SPAM", "tokenDecimal": "18"},
+				{"contractAddress": "0xlink", "tokenSymbol": "LINK", "tokenDecimal": "18"}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func TestPortfolioWalletFetcher_Fetch(t *testing.T) {
+	server := newPortfolioServer(t, map[string]string{
+		"0xusdc": "2500000",                // 2.5 USDC (6 decimals)
+		"0xlink": "3000000000000000000",    // 3 LINK (18 decimals)
+		"0xbad":  "not-a-number",           // unparseable, skipped
+		"0xdead": "1000000000000000000000", // price lookup fails, skipped
+	})
+	defer server.Close()
+
+	tokens := []TokenSpec{
+		{Symbol: "USDC", Contract: "0xusdc", Decimals: 6, Price: testutil.NewMockFetcher("usdc", 1.0, nil)},
+		{Symbol: "LINK", Contract: "0xlink", Decimals: 18, Price: testutil.NewMockFetcher("link", 15.0, nil)},
+		{Symbol: "BAD", Contract: "0xbad", Decimals: 18, Price: testutil.NewMockFetcher("bad", 1.0, nil)},
+		{Symbol: "DEAD", Contract: "0xdead", Decimals: 18, Price: testutil.NewMockFetcher("dead", 0, errors.New("no price"))},
+	}
+
+	f := NewPortfolioWalletFetcher("test_key", "0x123", tokens, server.URL)
+
+	value, err := f.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	// 1 ETH * $2000 + 2.5 USDC * $1 + 3 LINK * $15
+	if want := 2047.5; value != want {
+		t.Errorf("Fetch() = %.2f, want %.2f", value, want)
+	}
+	if got, want := f.Key(), "fetcher:etherscan:portfolio:0x123"; got != want {
+		t.Errorf("Key() = %q, want %q", got, want)
+	}
+}
+
+func TestPortfolioWalletFetcher_Fetch_PriceSource(t *testing.T) {
+	server := newPortfolioServer(t, map[string]string{
+		"0xusdc": "2500000",             // 2.5 USDC (6 decimals)
+		"0xlink": "3000000000000000000", // 3 LINK (18 decimals)
+	})
+	defer server.Close()
+
+	// One source prices ETH and LINK; USDC keeps its own price fetcher
+	prices := map[string]float64{"ETH": 3000, "LINK": 20}
+	source := fetcher.PriceSourceFunc(func(ctx context.Context, symbol string) (float64, error) {
+		price, ok := prices[symbol]
+		if !ok {
+			return 0, errors.New("unknown symbol " + symbol)
+		}
+		return price, nil
+	})
+
+	tokens := []TokenSpec{
+		{Symbol: "USDC", Contract: "0xusdc", Decimals: 6, Price: testutil.NewMockFetcher("usdc", 1.0, nil)},
+		{Symbol: "LINK", Contract: "0xlink", Decimals: 18},
+	}
+	f := NewPortfolioWalletFetcher("test_key", "0x123", tokens, server.URL, WithPriceSource(source))
+
+	value, err := f.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	// 1 ETH * $3000 + 2.5 USDC * $1 + 3 LINK * $20
+	if want := 3062.5; value != want {
+		t.Errorf("Fetch() = %.2f, want %.2f", value, want)
+	}
+}
+
+// fakeBalanceReader returns fixed balances, or err, and records the contracts it was asked for
+type fakeBalanceReader struct {
+	balances  []*big.Int
+	err       error
+	contracts []string
+}
+
+func (r *fakeBalanceReader) TokenBalances(ctx context.Context, owner string, contracts []string) ([]*big.Int, error) {
+	r.contracts = contracts
+	return r.balances, r.err
+}
+
+func TestPortfolioWalletFetcher_TokenBalanceReader(t *testing.T) {
+	var tokenRequests atomic.Int32
+	upstream := newPortfolioServer(t, map[string]string{
+		"0xlink": "3000000000000000000", // 3 LINK (18 decimals)
+	})
+	defer upstream.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("action") == "tokenbalance" {
+			tokenRequests.Add(1)
+		}
+		upstream.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	tokens := []TokenSpec{
+		{Symbol: "USDC", Contract: "0xusdc", Decimals: 6, Price: testutil.NewMockFetcher("usdc", 1.0, nil)},
+		{Symbol: "LINK", Contract: "0xlink", Decimals: 18, Price: testutil.NewMockFetcher("link", 15.0, nil)},
+	}
+
+	tests := []struct {
+		name          string
+		reader        *fakeBalanceReader
+		want          float64
+		tokenRequests int32
+	}{
+		{
+			// 1 ETH * $2000 + 4 USDC * $1 + 3 LINK * $15, all from the batch
+			name:   "batch",
+			reader: &fakeBalanceReader{balances: []*big.Int{big.NewInt(4000000), big.NewInt(3e18)}},
+			want:   2049,
+		},
+		{
+			// LINK couldn't be read in the batch, so it alone comes from Etherscan
+			name:          "unreadable token",
+			reader:        &fakeBalanceReader{balances: []*big.Int{big.NewInt(4000000), nil}},
+			want:          2049,
+			tokenRequests: 1,
+		},
+		{
+			// USDC has no Etherscan balance in this server, so only LINK counts
+			name:          "batch failure falls back to Etherscan",
+			reader:        &fakeBalanceReader{err: errors.New("rpc down")},
+			want:          2045,
+			tokenRequests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenRequests.Store(0)
+			f := NewPortfolioWalletFetcher("test_key", "0x123", tokens, server.URL)
+			f.SetTokenBalanceReader(tt.reader)
+
+			value, err := f.Fetch(context.Background())
+			if err != nil {
+				t.Fatalf("Fetch() returned unexpected error: %v", err)
+			}
+			if value != tt.want {
+				t.Errorf("Fetch() = %.2f, want %.2f", value, tt.want)
+			}
+			if got := tokenRequests.Load(); got != tt.tokenRequests {
+				t.Errorf("made %d tokenbalance requests, want %d", got, tt.tokenRequests)
+			}
+			if want := []string{"0xusdc", "0xlink"}; !slices.Equal(tt.reader.contracts, want) {
+				t.Errorf("reader asked for %v, want %v", tt.reader.contracts, want)
+			}
+		})
+	}
+}
+
+func TestPortfolioWalletFetcher_Fetch_EthFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	tokens := []TokenSpec{{Symbol: "USDC", Contract: "0xusdc", Decimals: 6, Price: testutil.NewMockFetcher("usdc", 1.0, nil)}}
+	f := NewPortfolioWalletFetcher("test_key", "0x123", tokens, server.URL)
+
+	if _, err := f.Fetch(context.Background()); err == nil {
+		t.Error("Fetch() expected error when the ETH balance fails, got nil")
+	}
+}
+
+func TestPortfolioWalletFetcher_TokenDiscovery(t *testing.T) {
+	server := newPortfolioServer(t, map[string]string{
+		"0xusdc": "2500000",             // 2.5 USDC (6 decimals)
+		"0xlink": "3000000000000000000", // 3 LINK (18 decimals)
+	})
+	defer server.Close()
+
+	// USDC is configured, so only LINK is added by discovery; SPAM has no price source
+	tokens := []TokenSpec{{Symbol: "USDC", Contract: "0xusdc", Decimals: 6, Price: testutil.NewMockFetcher("usdc", 1.0, nil)}}
+	f := NewPortfolioWalletFetcher("test_key", "0x123", tokens, server.URL)
+
+	var priced []string
+	f.EnableTokenDiscovery(func(symbol, contract string) fetcher.Fetcher {
+		priced = append(priced, symbol)
+		if symbol == "LINK" {
+			return testutil.NewMockFetcher("link", 15.0, nil)
+		}
+		return nil
+	})
+
+	value, err := f.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	// 1 ETH * $2000 + 2.5 USDC * $1 + 3 LINK * $15
+	if want := 2047.5; value != want {
+		t.Errorf("Fetch() = %.2f, want %.2f", value, want)
+	}
+	if want := []string{"LINK", "SPAM"}; !slices.Equal(priced, want) {
+		t.Errorf("discovery priced %v, want %v", priced, want)
+	}
+	if len(f.tokens) != 1 {
+		t.Errorf("discovery modified the configured tokens: %+v", f.tokens)
+	}
+}
+
+// cancelingFetcher cancels the run when asked for a price, as an interrupted run would
+type cancelingFetcher struct {
+	cancel context.CancelFunc
+}
+
+func (f *cancelingFetcher) Fetch(ctx context.Context) (float64, error) {
+	f.cancel()
+	return 0, ctx.Err()
+}
+
+func (f *cancelingFetcher) Key() string { return "canceling" }
+
+func TestPortfolioWalletFetcher_Fetch_AbortsOnCancelOrTimeout(t *testing.T) {
+	server := newPortfolioServer(t, map[string]string{
+		"0xusdc": "2500000",
+		"0xlink": "3000000000000000000",
+	})
+	defer server.Close()
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		tokens := []TokenSpec{
+			{Symbol: "USDC", Contract: "0xusdc", Decimals: 6, Price: &cancelingFetcher{cancel: cancel}},
+			{Symbol: "LINK", Contract: "0xlink", Decimals: 18, Price: testutil.NewMockFetcher("link", 15.0, nil)},
+		}
+
+		_, err := NewPortfolioWalletFetcher("test_key", "0x123", tokens, server.URL).Fetch(ctx)
+		if got := fetcher.ErrorTypeOf(err); got != fetcher.ErrorTypeCanceled {
+			t.Errorf("Fetch() error = %v, want canceled error instead of a partial total", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		tokens := []TokenSpec{
+			{Symbol: "USDC", Contract: "0xusdc", Decimals: 6, Price: testutil.NewMockFetcher("usdc", 0, fetcher.NewTimeoutError(context.DeadlineExceeded))},
+		}
+
+		_, err := NewPortfolioWalletFetcher("test_key", "0x123", tokens, server.URL).Fetch(context.Background())
+		if got := fetcher.ErrorTypeOf(err); got != fetcher.ErrorTypeTimeout {
+			t.Errorf("Fetch() error = %v, want timeout error instead of a partial total", err)
+		}
+	})
+}
+
+func TestPortfolioWalletFetcher_Fetch_AbortsOnRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   string
+		discover bool
+	}{
+		{name: "token balance", action: "tokenbalance"},
+		{name: "token discovery", action: "tokentx", discover: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			portfolio := newPortfolioServer(t, map[string]string{"0xusdc": "2500000"})
+			defer portfolio.Close()
+
+			// Etherscan throttles with HTTP 200 and status 0 rather than a 429
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("action") == tt.action {
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(rateLimitedResult))
+					return
+				}
+				portfolio.Config.Handler.ServeHTTP(w, r)
+			}))
+			defer server.Close()
+
+			tokens := []TokenSpec{{Symbol: "USDC", Contract: "0xusdc", Decimals: 6, Price: testutil.NewMockFetcher("usdc", 1.0, nil)}}
+			f := NewPortfolioWalletFetcher("test_key", "0x123", tokens, server.URL, WithClientOptions(fetcher.WithoutRetries()))
+			if tt.discover {
+				f.EnableTokenDiscovery(func(symbol, contract string) fetcher.Fetcher { return nil })
+			}
+
+			_, err := f.Fetch(context.Background())
+			if got := fetcher.ErrorTypeOf(err); got != fetcher.ErrorTypeRateLimit {
+				t.Errorf("Fetch() error = %v, want rate limit error instead of a partial total", err)
+			}
+		})
+	}
+}
+
+func TestPortfolioWalletFetcher_Fetch_SkipsTokenWithErrorStatus(t *testing.T) {
+	portfolio := newPortfolioServer(t, map[string]string{"0xusdc": "2500000"})
+	defer portfolio.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("contractaddress") == "0xbad" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Error! Invalid contract address format"}`))
+			return
+		}
+		portfolio.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	tokens := []TokenSpec{
+		{Symbol: "USDC", Contract: "0xusdc", Decimals: 6, Price: testutil.NewMockFetcher("usdc", 1.0, nil)},
+		{Symbol: "BAD", Contract: "0xbad", Decimals: 18, Price: testutil.NewMockFetcher("bad", 1.0, nil)},
+	}
+
+	value, err := NewPortfolioWalletFetcher("test_key", "0x123", tokens, server.URL).Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	// 1 ETH * $2000 + 2.5 USDC * $1; the bad token is skipped
+	if want := 2002.5; value != want {
+		t.Errorf("Fetch() = %.2f, want %.2f", value, want)
+	}
+}
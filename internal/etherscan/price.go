@@ -0,0 +1,47 @@
+package etherscan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+
+	"resty.dev/v3"
+)
+
+// PriceFetcher fetches the ETH/USD price from Etherscan. It shares the ETH price cache
+// with wallet fetchers, so it suits other balance providers that need a price source,
+// either as a Fetcher or as a fetcher.PriceSource that only prices ETH.
+type PriceFetcher struct {
+	apiKey string
+	client *resty.Client
+}
+
+// NewPriceFetcher creates a new ETH/USD price fetcher
+func NewPriceFetcher(apiKey, baseURL string) *PriceFetcher {
+	return &PriceFetcher{
+		apiKey: apiKey,
+		client: fetcher.NewHTTPClient(baseURL, fetcher.WithRateLimitedRetries(ratelimit.APIEtherscan)),
+	}
+}
+
+// Fetch retrieves the ETH/USD price
+func (f *PriceFetcher) Fetch(ctx context.Context) (float64, error) {
+	return cachedEthPrice(ctx, f.client, f.apiKey)
+}
+
+// PriceUSD implements fetcher.PriceSource. Etherscan only quotes ETH, so any other
+// symbol is rejected with a validation error.
+func (f *PriceFetcher) PriceUSD(ctx context.Context, symbol string) (float64, error) {
+	if !strings.EqualFold(symbol, "ETH") {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("Etherscan has no USD price for %s, only ETH", symbol))
+	}
+	return f.Fetch(ctx)
+}
+
+// Key returns the Redis key for this fetcher
+func (f *PriceFetcher) Key() string {
+	return "fetcher:etherscan:ethprice"
+}
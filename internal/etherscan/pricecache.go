@@ -0,0 +1,172 @@
+package etherscan
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"financefetcher/internal/fetcher"
+)
+
+const (
+	// mainnetChainID is the Etherscan chain id for Ethereum mainnet
+	mainnetChainID = "1"
+
+	// defaultPriceCacheTTL is how long a fetched ETH price is reused across wallet fetchers
+	defaultPriceCacheTTL = 30 * time.Second
+)
+
+// ethPriceCache is shared by all wallet fetchers so a multi-wallet run
+// looks up the ETH price once instead of once per wallet
+var ethPriceCache = newPriceCache(defaultPriceCacheTTL)
+
+// SetPriceCacheTTL sets how long a fetched ETH price is shared across wallet fetchers.
+// A TTL of zero disables caching so every fetch looks up the price.
+func SetPriceCacheTTL(ttl time.Duration) {
+	ethPriceCache.setTTL(ttl)
+}
+
+// InvalidatePrice drops the cached ETH price for the Etherscan endpoint at baseURL so the
+// next fetch looks it up again, e.g. to force a refresh after a known price spike
+func InvalidatePrice(baseURL string) {
+	ethPriceCache.invalidate(strings.TrimRight(baseURL, "/") + "|" + mainnetChainID)
+}
+
+// InvalidateAllPrices drops every cached ETH price so the next fetch for each endpoint
+// looks it up again
+func InvalidateAllPrices() {
+	ethPriceCache.invalidateAll()
+}
+
+// cachedPrice is a price along with the time it was fetched
+type cachedPrice struct {
+	price     float64
+	fetchedAt time.Time
+}
+
+// priceCall is a price lookup in flight; done is closed once price and err are set
+type priceCall struct {
+	done  chan struct{}
+	price float64
+	err   error
+
+	// canceled records that the caller making the lookup gave up, so its error says
+	// nothing about the price and waiters should look it up themselves
+	canceled bool
+
+	// generation is the cache's invalidation generation when the lookup started
+	generation uint64
+}
+
+// priceCache holds recently fetched prices keyed by endpoint and chain id
+type priceCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	entries  map[string]cachedPrice
+	inflight map[string]*priceCall
+
+	// generation counts invalidations, so a lookup that started before one doesn't
+	// cache the price it was asked to forget
+	generation uint64
+}
+
+// newPriceCache creates an empty cache with the given TTL
+func newPriceCache(ttl time.Duration) *priceCache {
+	return &priceCache{
+		ttl:      ttl,
+		entries:  make(map[string]cachedPrice),
+		inflight: make(map[string]*priceCall),
+	}
+}
+
+// setTTL changes the TTL and drops existing entries so the new TTL applies immediately
+func (c *priceCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ttl = ttl
+	c.entries = make(map[string]cachedPrice)
+}
+
+// invalidate drops the entry for key, if any. A lookup for key already in flight still
+// answers its waiters but isn't cached, and later callers start a fresh lookup.
+func (c *priceCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	delete(c.entries, key)
+	delete(c.inflight, key)
+}
+
+// invalidateAll drops every entry, leaving lookups in flight uncached like invalidate
+func (c *priceCache) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = make(map[string]cachedPrice)
+	c.inflight = make(map[string]*priceCall)
+}
+
+// get returns the cached price for key if it is fresh, otherwise calls fetch and caches the result.
+// The lock only guards the maps, so a slow lookup never holds up other keys. Concurrent
+// misses for one key share a single lookup; if the caller making it is canceled, the
+// others stop waiting and one of them looks the price up again. Failed fetches are not cached.
+func (c *priceCache) get(ctx context.Context, key string, fetch func(ctx context.Context) (float64, error)) (float64, error) {
+	for {
+		c.mu.Lock()
+
+		if entry, ok := c.entries[key]; ok && time.Since(entry.fetchedAt) < c.ttl {
+			c.mu.Unlock()
+			slog.Debug("using cached ETH price", "run_id", fetcher.RunIDFromContext(ctx), "key", key, "age", time.Since(entry.fetchedAt))
+			return entry.price, nil
+		}
+
+		call, ok := c.inflight[key]
+		if !ok {
+			call = &priceCall{done: make(chan struct{}), generation: c.generation}
+			c.inflight[key] = call
+			c.mu.Unlock()
+
+			return c.lookup(ctx, key, call, fetch)
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-call.done:
+			if call.canceled && ctx.Err() == nil {
+				continue
+			}
+			return call.price, call.err
+		case <-ctx.Done():
+			return 0, fetcher.ClassifyLimiterError(ctx.Err())
+		}
+	}
+}
+
+// lookup runs fetch for the in-flight call on key, caches a successful result unless the
+// cache was invalidated since the call started, and hands the result to every caller
+// waiting on call
+func (c *priceCache) lookup(ctx context.Context, key string, call *priceCall, fetch func(ctx context.Context) (float64, error)) (float64, error) {
+	call.price, call.err = fetch(ctx)
+	call.canceled = call.err != nil && ctx.Err() != nil
+
+	c.mu.Lock()
+	// An invalidation may already have replaced this call with a fresh lookup
+	if c.inflight[key] == call {
+		delete(c.inflight, key)
+	}
+	if call.err == nil && c.ttl > 0 && call.generation == c.generation {
+		c.entries[key] = cachedPrice{
+			price:     call.price,
+			fetchedAt: time.Now(),
+		}
+	}
+	c.mu.Unlock()
+
+	close(call.done)
+	return call.price, call.err
+}
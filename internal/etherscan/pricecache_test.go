@@ -0,0 +1,340 @@
+package etherscan
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newCountingServer returns a server that answers ethprice and balance calls,
+// counting how many times the ETH price is requested
+func newCountingServer(t *testing.T, priceRequests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		if r.URL.Query().Get("action") == "ethprice" {
+			priceRequests.Add(1)
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "2000.00"}}`))
+			return
+		}
+		w.Write([]byte(`{"status": "1", "message": "OK", "result": "1000000000000000000"}`))
+	}))
+}
+
+func TestWalletFetcher_SharesCachedEthPrice(t *testing.T) {
+	var priceRequests atomic.Int32
+	server := newCountingServer(t, &priceRequests)
+	defer server.Close()
+
+	ctx := context.Background()
+	for _, address := range []string{"0xabc", "0xdef", "0x123"} {
+		if _, err := NewWalletFetcher("test_key", address, server.URL).Fetch(ctx); err != nil {
+			t.Fatalf("Fetch() returned unexpected error: %v", err)
+		}
+	}
+
+	if got := priceRequests.Load(); got != 1 {
+		t.Errorf("ethprice requested %d times, want 1", got)
+	}
+}
+
+func TestWalletFetcher_PriceCacheDisabled(t *testing.T) {
+	SetPriceCacheTTL(0)
+	defer SetPriceCacheTTL(defaultPriceCacheTTL)
+
+	var priceRequests atomic.Int32
+	server := newCountingServer(t, &priceRequests)
+	defer server.Close()
+
+	ctx := context.Background()
+	for _, address := range []string{"0xabc", "0xdef"} {
+		if _, err := NewWalletFetcher("test_key", address, server.URL).Fetch(ctx); err != nil {
+			t.Fatalf("Fetch() returned unexpected error: %v", err)
+		}
+	}
+
+	if got := priceRequests.Load(); got != 2 {
+		t.Errorf("ethprice requested %d times, want 2", got)
+	}
+}
+
+func TestPriceCache_Expiry(t *testing.T) {
+	cache := newPriceCache(20 * time.Millisecond)
+	ctx := context.Background()
+
+	calls := 0
+	fetch := func(ctx context.Context) (float64, error) {
+		calls++
+		return float64(calls), nil
+	}
+
+	cache.get(ctx, "key", fetch)
+	cache.get(ctx, "key", fetch)
+	if calls != 1 {
+		t.Errorf("fetch called %d times before expiry, want 1", calls)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	price, _ := cache.get(ctx, "key", fetch)
+	if calls != 2 {
+		t.Errorf("fetch called %d times after expiry, want 2", calls)
+	}
+	if price != 2 {
+		t.Errorf("get() = %v, want refreshed price 2", price)
+	}
+}
+
+func TestPriceCache_DoesNotCacheErrors(t *testing.T) {
+	cache := newPriceCache(time.Minute)
+	ctx := context.Background()
+
+	calls := 0
+	fetch := func(ctx context.Context) (float64, error) {
+		calls++
+		return 0, errors.New("price unavailable")
+	}
+
+	cache.get(ctx, "key", fetch)
+	if _, err := cache.get(ctx, "key", fetch); err == nil {
+		t.Error("get() expected error, got nil")
+	}
+	if calls != 2 {
+		t.Errorf("fetch called %d times, want 2", calls)
+	}
+}
+
+func TestPriceFetcher_SharesCacheWithWallets(t *testing.T) {
+	var priceRequests atomic.Int32
+	server := newCountingServer(t, &priceRequests)
+	defer server.Close()
+
+	ctx := context.Background()
+	price, err := NewPriceFetcher("test_key", server.URL).Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+	if price != 2000.0 {
+		t.Errorf("Fetch() = %v, want 2000", price)
+	}
+
+	if _, err := NewWalletFetcher("test_key", "0xabc", server.URL).Fetch(ctx); err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	if got := priceRequests.Load(); got != 1 {
+		t.Errorf("ethprice requested %d times, want 1", got)
+	}
+}
+
+func TestPriceFetcher_PriceUSD(t *testing.T) {
+	var priceRequests atomic.Int32
+	server := newCountingServer(t, &priceRequests)
+	defer server.Close()
+
+	source := NewPriceFetcher("test_key", server.URL)
+
+	price, err := source.PriceUSD(context.Background(), "eth")
+	if err != nil {
+		t.Fatalf("PriceUSD(eth) returned unexpected error: %v", err)
+	}
+	if price != 2000.0 {
+		t.Errorf("PriceUSD(eth) = %v, want 2000", price)
+	}
+
+	if _, err := source.PriceUSD(context.Background(), "LINK"); err == nil {
+		t.Error("PriceUSD(LINK) expected an error, Etherscan only prices ETH")
+	}
+	if got := priceRequests.Load(); got != 1 {
+		t.Errorf("ethprice requested %d times, want 1", got)
+	}
+}
+
+func TestInvalidatePrice(t *testing.T) {
+	var priceRequests atomic.Int32
+	server := newCountingServer(t, &priceRequests)
+	defer server.Close()
+
+	ctx := context.Background()
+	fetchPrice := func() {
+		t.Helper()
+		if _, err := NewPriceFetcher("test_key", server.URL).Fetch(ctx); err != nil {
+			t.Fatalf("Fetch() returned unexpected error: %v", err)
+		}
+	}
+
+	fetchPrice()
+	fetchPrice()
+	if got := priceRequests.Load(); got != 1 {
+		t.Fatalf("ethprice requested %d times before invalidation, want 1", got)
+	}
+
+	InvalidatePrice(server.URL + "/")
+	fetchPrice()
+	if got := priceRequests.Load(); got != 2 {
+		t.Errorf("ethprice requested %d times after InvalidatePrice, want 2", got)
+	}
+
+	InvalidateAllPrices()
+	fetchPrice()
+	if got := priceRequests.Load(); got != 3 {
+		t.Errorf("ethprice requested %d times after InvalidateAllPrices, want 3", got)
+	}
+}
+
+func TestPriceCache_CollapsesConcurrentMisses(t *testing.T) {
+	cache := newPriceCache(time.Minute)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (float64, error) {
+		calls.Add(1)
+		<-release
+		return 2000, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if price, err := cache.get(ctx, "key", fetch); err != nil || price != 2000 {
+				t.Errorf("get() = %v, %v, want 2000", price, err)
+			}
+		}()
+	}
+
+	// Give every caller time to join the lookup before it completes
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("fetch called %d times for concurrent misses, want 1", got)
+	}
+}
+
+func TestPriceCache_SlowKeyDoesNotBlockOthers(t *testing.T) {
+	cache := newPriceCache(time.Minute)
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	defer close(release)
+	go cache.get(ctx, "slow", func(ctx context.Context) (float64, error) {
+		<-release
+		return 1, nil
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.get(ctx, "fast", func(ctx context.Context) (float64, error) { return 2, nil })
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("get() for one key waited on a slow lookup of another")
+	}
+}
+
+func TestPriceCache_CanceledLookupDoesNotFailWaiters(t *testing.T) {
+	cache := newPriceCache(time.Minute)
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := cache.get(leaderCtx, "key", func(ctx context.Context) (float64, error) {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		leaderDone <- err
+	}()
+	<-started
+
+	waiterDone := make(chan float64, 1)
+	go func() {
+		price, err := cache.get(context.Background(), "key", func(ctx context.Context) (float64, error) {
+			return 2000, nil
+		})
+		if err != nil {
+			t.Errorf("waiter get() returned unexpected error: %v", err)
+		}
+		waiterDone <- price
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	if err := <-leaderDone; err == nil {
+		t.Error("canceled get() returned nil error")
+	}
+
+	select {
+	case price := <-waiterDone:
+		if price != 2000 {
+			t.Errorf("waiter get() = %v, want 2000 from its own lookup", price)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter never finished after the lookup it joined was canceled")
+	}
+}
+
+func TestPriceCache_InvalidateDuringLookup(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		invalidate func(c *priceCache)
+	}{
+		{"invalidate", func(c *priceCache) { c.invalidate("key") }},
+		{"invalidateAll", func(c *priceCache) { c.invalidateAll() }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newPriceCache(time.Minute)
+			ctx := context.Background()
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			done := make(chan float64, 1)
+			go func() {
+				price, _ := cache.get(ctx, "key", func(ctx context.Context) (float64, error) {
+					close(started)
+					<-release
+					return 2000, nil
+				})
+				done <- price
+			}()
+			<-started
+
+			// The price is invalidated while the lookup fetching it is still in flight
+			tt.invalidate(cache)
+
+			// A caller arriving now must not join the stale lookup
+			price, err := cache.get(ctx, "key", func(ctx context.Context) (float64, error) { return 2100, nil })
+			if err != nil || price != 2100 {
+				t.Errorf("get() after invalidation = %v, %v, want 2100 from a fresh lookup", price, err)
+			}
+
+			close(release)
+			if price := <-done; price != 2000 {
+				t.Errorf("in-flight get() = %v, want 2000", price)
+			}
+
+			// The stale lookup finished last but must not replace the fresh price
+			price, err = cache.get(ctx, "key", func(ctx context.Context) (float64, error) { return 2200, nil })
+			if err != nil || price != 2100 {
+				t.Errorf("get() = %v, %v, want the fresh cached 2100", price, err)
+			}
+		})
+	}
+}
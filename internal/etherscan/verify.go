@@ -0,0 +1,86 @@
+package etherscan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+)
+
+// statusResponse is the envelope shared by Etherscan responses. On failure Status is "0"
+// and Result holds an error string such as "Invalid API Key".
+type statusResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// Verify checks the API key with a single ethprice call, bypassing the shared price cache
+func (f *WalletFetcher) Verify(ctx context.Context) error {
+	if err := ratelimit.GetLimiter().Wait(ctx, ratelimit.APIEtherscan); err != nil {
+		return fetcher.ClassifyLimiterError(err)
+	}
+
+	var result statusResponse
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"chainid": mainnetChainID,
+			"module":  "stats",
+			"action":  "ethprice",
+			"apikey":  f.apiKey,
+		}).
+		SetResult(&result).
+		Get("")
+
+	if err != nil {
+		return fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to verify Etherscan API key")
+	}
+
+	if !resp.IsSuccess() {
+		return fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to verify Etherscan API key")
+	}
+
+	if result.Status == "1" {
+		return nil
+	}
+
+	// Etherscan reports errors with HTTP 200, status "0" and the reason in result
+	var reason string
+	if err := json.Unmarshal(result.Result, &reason); err != nil || reason == "" {
+		reason = result.Message
+	}
+
+	lower := strings.ToLower(reason)
+	switch {
+	case strings.Contains(lower, "api key"), strings.Contains(lower, "apikey"):
+		return fetcher.NewClientError(0, fmt.Sprintf("invalid Etherscan API key: %s", reason))
+	case isRateLimited(reason):
+		return statusError(result)
+	default:
+		return fetcher.NewValidationError(fmt.Sprintf("unexpected Etherscan response: %s", reason))
+	}
+}
+
+// statusError classifies a failure Etherscan reported with HTTP 200 and status "0". A rate
+// limit reason such as "Max rate limit reached" is a retryable rate limit error; any other
+// reason is a validation error.
+func statusError(result statusResponse) *fetcher.FetchError {
+	reason := resultReason(result)
+	if isRateLimited(reason) {
+		rateErr := fetcher.NewRateLimitError(0)
+		rateErr.Message = reason
+		return rateErr
+	}
+	return fetcher.NewValidationError(fmt.Sprintf("unexpected Etherscan response: %s", reason))
+}
+
+// isRateLimited reports whether an Etherscan error says the key's rate limit was hit,
+// e.g. "Max rate limit reached" or "Max calls per sec rate limit reached (5/sec)"
+func isRateLimited(reason string) bool {
+	return strings.Contains(strings.ToLower(reason), "rate limit")
+}
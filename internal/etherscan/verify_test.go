@@ -0,0 +1,61 @@
+package etherscan
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestWalletFetcher_Verify(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantType fetcherpkg.ErrorType
+	}{
+		{"valid key", `{"status": "1", "message": "OK", "result": {"ethusd": "2500.00"}}`, ""},
+		{"invalid key", `{"status": "0", "message": "NOTOK", "result": "Invalid API Key"}`, fetcherpkg.ErrorTypeClient},
+		{"missing key", `{"status": "0", "message": "NOTOK", "result": "Missing/Invalid API Key"}`, fetcherpkg.ErrorTypeClient},
+		{"rate limited", `{"status": "0", "message": "NOTOK", "result": "Max rate limit reached"}`, fetcherpkg.ErrorTypeRateLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var balanceCalls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("action") != "ethprice" {
+					balanceCalls++
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			fetcher := NewWalletFetcher("test_key", "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb", server.URL)
+			err := fetcher.Verify(context.Background())
+
+			if balanceCalls != 0 {
+				t.Errorf("Verify() made %d balance calls, want 0", balanceCalls)
+			}
+
+			if tt.wantType == "" {
+				if err != nil {
+					t.Errorf("Verify() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var fetchErr *fetcherpkg.FetchError
+			if !errors.As(err, &fetchErr) {
+				t.Fatalf("Verify() error = %v, want *FetchError", err)
+			}
+			if fetchErr.Type != tt.wantType {
+				t.Errorf("error type = %q, want %q", fetchErr.Type, tt.wantType)
+			}
+		})
+	}
+}
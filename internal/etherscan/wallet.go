@@ -2,6 +2,7 @@ package etherscan
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"math/big"
@@ -9,6 +10,7 @@ import (
 
 	"financefetcher/internal/fetcher"
 	"financefetcher/internal/ratelimit"
+	"financefetcher/internal/wei"
 
 	"resty.dev/v3"
 )
@@ -17,23 +19,29 @@ const (
 	weiPerEth = 1e18
 )
 
-// EthPriceResponse represents the Etherscan API response for ETH price
+// EthPriceResponse represents the Etherscan API response for ETH price. On success Result
+// holds an EthPriceResult; on failure Status is "0" and Result holds an error string such
+// as "Max rate limit reached".
 type EthPriceResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Result  struct {
-		EthBTC          string `json:"ethbtc"`
-		EthBTCTimestamp string `json:"ethbtc_timestamp"`
-		EthUSD          string `json:"ethusd"`
-		EthUSDTimestamp string `json:"ethusd_timestamp"`
-	} `json:"result"`
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
 }
 
-// BalanceResponse represents the Etherscan API response for account balance
+// EthPriceResult is the price data in a successful EthPriceResponse
+type EthPriceResult struct {
+	EthBTC          string `json:"ethbtc"`
+	EthBTCTimestamp string `json:"ethbtc_timestamp"`
+	EthUSD          string `json:"ethusd"`
+	EthUSDTimestamp string `json:"ethusd_timestamp"`
+}
+
+// BalanceResponse represents the Etherscan API response for account balance. Result is
+// the balance in wei as a string on success, and the error reason when Status is "0".
 type BalanceResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Result  string `json:"result"` // Balance in wei as a string
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
 }
 
 // WalletFetcher fetches an Ethereum wallet balance in USD
@@ -41,56 +49,129 @@ type WalletFetcher struct {
 	apiKey  string
 	address string
 	client  *resty.Client
+
+	// gasReserve is wei set aside for future gas and excluded from the reported value
+	gasReserve *big.Int
+
+	// priceSource prices ETH instead of Etherscan's ethprice endpoint; nil uses Etherscan
+	priceSource fetcher.PriceSource
+}
+
+// Option configures optional behavior of a WalletFetcher
+type Option func(*WalletFetcher)
+
+// WithClientOptions applies HTTP client options, e.g. fetcher.WithoutRetries()
+func WithClientOptions(opts ...fetcher.ClientOption) Option {
+	return func(f *WalletFetcher) {
+		for _, opt := range opts {
+			opt(f.client)
+		}
+	}
+}
+
+// WithGasReserve reports a "spendable" balance by setting aside eth for gas before the
+// USD conversion. The result never goes below zero. The reserve is a fixed, user-chosen
+// approximation, not an estimate of actual pending transactions or current gas prices.
+func WithGasReserve(eth float64) Option {
+	return func(f *WalletFetcher) {
+		if eth <= 0 {
+			f.gasReserve = nil
+			return
+		}
+		reserve, _ := new(big.Float).Mul(big.NewFloat(eth), big.NewFloat(weiPerEth)).Int(nil)
+		f.gasReserve = reserve
+	}
+}
+
+// WithPriceSource takes the ETH/USD price from source (e.g. alphavantage.NewPriceSource)
+// instead of Etherscan's ethprice endpoint, so price lookups don't spend the Etherscan
+// rate limit or fail along with it. Etherscan is then only asked for balances. The source
+// is responsible for any caching; eth_price_cache_ttl applies only to Etherscan prices.
+func WithPriceSource(source fetcher.PriceSource) Option {
+	return func(f *WalletFetcher) {
+		f.priceSource = source
+	}
 }
 
 // NewWalletFetcher creates a new wallet balance fetcher
-func NewWalletFetcher(apiKey, address, baseURL string) *WalletFetcher {
-	client := fetcher.NewHTTPClient(baseURL)
+func NewWalletFetcher(apiKey, address, baseURL string, opts ...Option) *WalletFetcher {
+	client := fetcher.NewHTTPClient(baseURL, fetcher.WithRateLimitedRetries(ratelimit.APIEtherscan))
 
-	return &WalletFetcher{
+	f := &WalletFetcher{
 		apiKey:  apiKey,
 		address: address,
 		client:  client,
 	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
 }
 
-// fetchEthPrice gets the current ETH/USD price
+// fetchEthPrice gets the current ETH/USD price from the configured price source, or from
+// Etherscan, sharing recent lookups across wallet fetchers
 func (f *WalletFetcher) fetchEthPrice(ctx context.Context) (float64, error) {
+	if f.priceSource != nil {
+		return f.priceSource.PriceUSD(ctx, "ETH")
+	}
+	return cachedEthPrice(ctx, f.client, f.apiKey)
+}
+
+// cachedEthPrice returns the ETH/USD price from the shared cache, fetching it on a miss.
+// Entries are keyed by endpoint and chain id so different Etherscan endpoints never share prices.
+func cachedEthPrice(ctx context.Context, client *resty.Client, apiKey string) (float64, error) {
+	key := client.BaseURL() + "|" + mainnetChainID
+	return ethPriceCache.get(ctx, key, func(ctx context.Context) (float64, error) {
+		return fetchEthPrice(ctx, client, apiKey)
+	})
+}
+
+// fetchEthPrice gets the current ETH/USD price using the given client and API key
+func fetchEthPrice(ctx context.Context, client *resty.Client, apiKey string) (float64, error) {
 	// Apply rate limiting
 	limiter := ratelimit.GetLimiter()
-	if err := limiter.Wait(ctx, ratelimit.APIEtherscan); err != nil {
-		return 0, fetcher.NewTimeoutError(err)
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIEtherscan)
+	if err != nil {
+		return 0, fetcher.ClassifyLimiterError(err)
 	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIEtherscan, "action", "ethprice", "wait_duration", waited)
 
-	slog.Debug("fetching ETH price from Etherscan")
+	slog.Debug("fetching ETH price from Etherscan", "run_id", fetcher.RunIDFromContext(ctx))
 
 	var result EthPriceResponse
 
-	resp, err := f.client.R().
+	resp, err := client.R().
 		SetContext(ctx).
 		SetQueryParams(map[string]string{
-			"chainid": "1",
+			"chainid": mainnetChainID,
 			"module":  "stats",
 			"action":  "ethprice",
-			"apikey":  f.apiKey,
+			"apikey":  apiKey,
 		}).
 		SetResult(&result).
 		Get("")
 
 	if err != nil {
-		return 0, fetcher.NewNetworkError(err)
+		return 0, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch ETH price")
 	}
 
 	if !resp.IsSuccess() {
-		fetchErr := fetcher.ClassifyHTTPError(resp.StatusCode())
-		return 0, fmt.Errorf("failed to fetch ETH price: %w", fetchErr)
+		return 0, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch ETH price")
+	}
+
+	// Etherscan reports errors with HTTP 200, status "0" and the reason in result
+	var prices EthPriceResult
+	if result.Status != "1" || json.Unmarshal(result.Result, &prices) != nil {
+		return 0, statusError(statusResponse(result)).WithContext("failed to fetch ETH price")
 	}
 
-	if result.Result.EthUSD == "" {
+	if prices.EthUSD == "" {
 		return 0, fetcher.NewValidationError("ETH price not found in response")
 	}
 
-	price, err := strconv.ParseFloat(result.Result.EthUSD, 64)
+	price, err := strconv.ParseFloat(prices.EthUSD, 64)
 	if err != nil {
 		return 0, fetcher.NewValidationError(fmt.Sprintf("failed to parse ETH price: %v", err))
 	}
@@ -108,11 +189,13 @@ func (f *WalletFetcher) Fetch(ctx context.Context) (float64, error) {
 
 	// Apply rate limiting for the balance request
 	limiter := ratelimit.GetLimiter()
-	if err := limiter.Wait(ctx, ratelimit.APIEtherscan); err != nil {
-		return 0, fetcher.NewTimeoutError(err)
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIEtherscan)
+	if err != nil {
+		return 0, fetcher.ClassifyLimiterError(err)
 	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIEtherscan, "address", f.address, "wait_duration", waited)
 
-	slog.Debug("fetching wallet balance from Etherscan", "address", f.address)
+	slog.Debug("fetching wallet balance from Etherscan", "run_id", fetcher.RunIDFromContext(ctx), "address", f.address)
 
 	// Then get the wallet balance in wei
 	var balanceResult BalanceResponse
@@ -120,7 +203,7 @@ func (f *WalletFetcher) Fetch(ctx context.Context) (float64, error) {
 	resp, err := f.client.R().
 		SetContext(ctx).
 		SetQueryParams(map[string]string{
-			"chainid": "1",
+			"chainid": mainnetChainID,
 			"module":  "account",
 			"action":  "balance",
 			"address": f.address,
@@ -131,39 +214,50 @@ func (f *WalletFetcher) Fetch(ctx context.Context) (float64, error) {
 		Get("")
 
 	if err != nil {
-		return 0, fetcher.NewNetworkError(err)
+		return 0, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch wallet balance for " + f.address)
 	}
 
 	if !resp.IsSuccess() {
-		fetchErr := fetcher.ClassifyHTTPError(resp.StatusCode())
-		return 0, fmt.Errorf("failed to fetch wallet balance: %w", fetchErr)
+		return 0, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch wallet balance for " + f.address)
 	}
 
-	if balanceResult.Result == "" {
-		return 0, fetcher.NewValidationError("balance not found in response")
+	// Etherscan reports errors with HTTP 200, status "0" and the reason in result
+	var wei string
+	if balanceResult.Status != "1" || json.Unmarshal(balanceResult.Result, &wei) != nil {
+		return 0, statusError(statusResponse(balanceResult)).WithContext("failed to fetch wallet balance for " + f.address)
 	}
 
-	// Convert wei (string) to big.Int, then to ETH (float64)
-	weiBalance := new(big.Int)
-	weiBalance, ok := weiBalance.SetString(balanceResult.Result, 10)
-	if !ok {
-		return 0, fetcher.NewValidationError(fmt.Sprintf("failed to parse balance: %s", balanceResult.Result))
+	if wei == "" {
+		return 0, fetcher.NewValidationError("balance not found in response")
 	}
 
-	// Convert wei to ETH: divide by 10^18
-	ethBalance := new(big.Float).SetInt(weiBalance)
-	ethBalance.Quo(ethBalance, big.NewFloat(weiPerEth))
+	return spendableWeiToUSD(wei, f.gasReserve, ethUSD)
+}
 
-	// Convert to float64
-	ethFloat, _ := ethBalance.Float64()
+// weiToUSD converts a wei balance (decimal string) to its USD value at the given ETH/USD price
+func weiToUSD(wei string, ethUSD float64) (float64, error) {
+	return spendableWeiToUSD(wei, nil, ethUSD)
+}
 
-	// Calculate USD value
-	usdValue := ethFloat * ethUSD
+// spendableWeiToUSD converts a wei balance (decimal string) to USD after subtracting a
+// reserve in wei, never going below zero. A nil reserve subtracts nothing.
+func spendableWeiToUSD(balance string, reserve *big.Int, ethUSD float64) (float64, error) {
+	weiBalance, ok := new(big.Int).SetString(balance, 10)
+	if !ok {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("failed to parse balance: %s", balance))
+	}
+
+	if reserve != nil {
+		weiBalance.Sub(weiBalance, reserve)
+		if weiBalance.Sign() < 0 {
+			weiBalance.SetInt64(0)
+		}
+	}
 
-	return usdValue, nil
+	return wei.ToUSD(weiBalance, ethUSD), nil
 }
 
 // Key returns the Redis key for this fetcher
 func (f *WalletFetcher) Key() string {
 	return fmt.Sprintf("fetcher:etherscan:%s", f.address)
-}
\ No newline at end of file
+}
@@ -2,11 +2,20 @@ package etherscan
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
 )
 
+// Run this package's tests without real API rate limits
+func init() {
+	ratelimit.SetTestMode(true)
+}
+
 func TestNewWalletFetcher(t *testing.T) {
 	apiKey := "test_api_key"
 	address := "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb"
@@ -247,6 +256,40 @@ func TestWalletFetcher_Fetch_InvalidBalance(t *testing.T) {
 	}
 }
 
+func TestWalletFetcher_Fetch_RateLimited(t *testing.T) {
+	price := `{"status": "1", "message": "OK", "result": {"ethusd": "2000.00"}}`
+	balance := `{"status": "1", "message": "OK", "result": "1000000000000000000"}`
+
+	tests := []struct {
+		name      string
+		responses map[string]string
+	}{
+		{name: "balance", responses: map[string]string{"ethprice": price, "balance": rateLimitedResult}},
+		{name: "ETH price", responses: map[string]string{"ethprice": rateLimitedResult, "balance": balance}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Etherscan throttles with HTTP 200 and the reason as a string result
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.responses[r.URL.Query().Get("action")]))
+			}))
+			defer server.Close()
+
+			fetcher := NewWalletFetcher("test_key", "0x123", server.URL, WithClientOptions(fetcherpkg.WithoutRetries()))
+
+			_, err := fetcher.Fetch(context.Background())
+			if fetcherpkg.ErrorTypeOf(err) != fetcherpkg.ErrorTypeRateLimit {
+				t.Fatalf("Fetch() error = %v, want rate limit error", err)
+			}
+			if !fetcherpkg.IsRetryable(err) {
+				t.Errorf("Fetch() error = %v, want retryable", err)
+			}
+		})
+	}
+}
+
 func TestWalletFetcher_Fetch_ZeroBalance(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		action := r.URL.Query().Get("action")
@@ -309,4 +352,166 @@ func TestWalletFetcher_Fetch_ContextCancellation(t *testing.T) {
 	if err == nil {
 		t.Error("Fetch() expected error for cancelled context, got nil")
 	}
-}
\ No newline at end of file
+}
+
+func TestWalletFetcher_Fetch_ErrorClassification(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		wantType      fetcherpkg.ErrorType
+		wantRetryable bool
+	}{
+		{
+			name: "client error on balance",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("action") == "ethprice" {
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "2000.00"}}`))
+					return
+				}
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			wantType:      fetcherpkg.ErrorTypeClient,
+			wantRetryable: false,
+		},
+		{
+			name: "validation error on balance",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Query().Get("action") == "ethprice" {
+					w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "2000.00"}}`))
+					return
+				}
+				w.Write([]byte(`{"status": "1", "message": "OK", "result": "not_a_number"}`))
+			},
+			wantType:      fetcherpkg.ErrorTypeValidation,
+			wantRetryable: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			fetcher := NewWalletFetcher("test_key", "0x123", server.URL)
+
+			_, err := fetcher.Fetch(context.Background())
+			if err == nil {
+				t.Fatal("Fetch() expected error, got nil")
+			}
+
+			if got := fetcherpkg.ErrorTypeOf(err); got != tt.wantType {
+				t.Errorf("ErrorTypeOf() = %q, want %q", got, tt.wantType)
+			}
+			if got := fetcherpkg.IsRetryable(err); got != tt.wantRetryable {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.wantRetryable)
+			}
+		})
+	}
+}
+
+func TestWalletFetcher_Fetch_GasReserve(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("action") == "ethprice" {
+			w.Write([]byte(`{"status": "1", "message": "OK", "result": {"ethusd": "2000"}}`))
+			return
+		}
+		// 1 ETH
+		w.Write([]byte(`{"status": "1", "message": "OK", "result": "1000000000000000000"}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		reserve float64
+		want    float64
+	}{
+		{"no reserve", 0, 2000},
+		{"partial reserve", 0.25, 1500},
+		{"reserve exceeds balance", 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := NewWalletFetcher("test_key", "0x123", server.URL, WithGasReserve(tt.reserve))
+
+			value, err := fetcher.Fetch(context.Background())
+			if err != nil {
+				t.Fatalf("Fetch() returned unexpected error: %v", err)
+			}
+			if value != tt.want {
+				t.Errorf("Fetch() = %.2f, want %.2f", value, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalletFetcher_Fetch_PriceSource(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if action := r.URL.Query().Get("action"); action != "balance" {
+			t.Errorf("unexpected %q request to Etherscan with a price source set", action)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// 2 ETH
+		w.Write([]byte(`{"status": "1", "message": "OK", "result": "2000000000000000000"}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	var symbols []string
+	source := fetcherpkg.PriceSourceFunc(func(ctx context.Context, symbol string) (float64, error) {
+		symbols = append(symbols, symbol)
+		return 3000, nil
+	})
+	fetcher := NewWalletFetcher("test_key", "0x123", server.URL, WithPriceSource(source))
+
+	value, err := fetcher.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+	if expected := 6000.0; value != expected {
+		t.Errorf("Fetch() = %.2f, want %.2f", value, expected)
+	}
+	if len(symbols) != 1 || symbols[0] != "ETH" {
+		t.Errorf("price source asked for %v, want [ETH]", symbols)
+	}
+}
+
+func TestWalletFetcher_Fetch_PriceSourceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to Etherscan after the price source failed")
+	}))
+	defer server.Close()
+
+	source := fetcherpkg.PriceSourceFunc(func(ctx context.Context, symbol string) (float64, error) {
+		return 0, fetcherpkg.NewServerError(http.StatusServiceUnavailable)
+	})
+	fetcher := NewWalletFetcher("test_key", "0x123", server.URL, WithPriceSource(source))
+
+	if _, err := fetcher.Fetch(context.Background()); err == nil {
+		t.Error("Fetch() expected error when the price source fails, got nil")
+	}
+}
+
+func TestWeiToUSD_ExactCents(t *testing.T) {
+	// 0.0078125 ETH at $1234.56 is exactly $9.645. The float path computes
+	// 9.644999..., which prints as $9.64; big.Int cents round half up to $9.65.
+	wei, price := "7812500000000000", 1234.56
+
+	if naive := fmt.Sprintf("%.2f", 7812500000000000/1e18*price); naive != "9.64" {
+		t.Fatalf("float path = %s, expected it to round down to 9.64", naive)
+	}
+
+	value, err := weiToUSD(wei, price)
+	if err != nil {
+		t.Fatalf("weiToUSD() returned unexpected error: %v", err)
+	}
+	if value != 9.65 {
+		t.Errorf("weiToUSD() = %v, want 9.65", value)
+	}
+}
@@ -0,0 +1,250 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+
+	"resty.dev/v3"
+)
+
+// Multicall3Address is the Multicall3 contract, deployed at the same address on mainnet
+// and most EVM chains
+const Multicall3Address = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+const (
+	// aggregate3Selector is the selector of Multicall3's aggregate3((address,bool,bytes)[])
+	aggregate3Selector = "82ad56cb"
+
+	// balanceOfSelector is the selector of ERC-20 balanceOf(address)
+	balanceOfSelector = "70a08231"
+
+	// abiWord is the size of one ABI-encoded word in bytes
+	abiWord = 32
+)
+
+// CallResponse represents the JSON-RPC response to eth_call
+type CallResponse struct {
+	JSONRPC string    `json:"jsonrpc"`
+	ID      int       `json:"id"`
+	Result  string    `json:"result"` // Return data as a 0x-prefixed hex string
+	Error   *RPCError `json:"error,omitempty"`
+}
+
+// TokenBalanceReader reads a wallet's balances of many ERC-20 tokens in a single eth_call
+// through the Multicall3 contract, instead of one request per token
+type TokenBalanceReader struct {
+	client *resty.Client
+}
+
+// NewTokenBalanceReader creates a reader for the JSON-RPC endpoint rpcURL, including any
+// API key in the path
+func NewTokenBalanceReader(rpcURL string) *TokenBalanceReader {
+	client := fetcher.NewHTTPClient(rpcURL, fetcher.WithRateLimitedRetries(ratelimit.APIEthRPC))
+
+	// DEBUG_HTTP request logging can't redact a key embedded in the URL path, so keep it off
+	client.SetDebug(false)
+
+	return &TokenBalanceReader{client: client}
+}
+
+// TokenBalances returns owner's raw balance of each token contract, in order. A token
+// whose balanceOf call reverts (e.g. not an ERC-20 contract) gets a nil balance rather
+// than failing the whole batch.
+func (r *TokenBalanceReader) TokenBalances(ctx context.Context, owner string, contracts []string) ([]*big.Int, error) {
+	if len(contracts) == 0 {
+		return nil, nil
+	}
+
+	calldata, err := encodeBalanceOfCalls(owner, contracts)
+	if err != nil {
+		return nil, fetcher.NewValidationError(err.Error())
+	}
+
+	// Apply rate limiting; the whole batch costs one request
+	limiter := ratelimit.GetLimiter()
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIEthRPC)
+	if err != nil {
+		return nil, fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIEthRPC, "address", owner, "wait_duration", waited)
+
+	slog.Debug("fetching token balances with multicall", "run_id", fetcher.RunIDFromContext(ctx), "address", owner, "tokens", len(contracts))
+
+	var result CallResponse
+
+	resp, err := r.client.R().
+		SetContext(ctx).
+		SetBody(rpcRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "eth_call",
+			Params:  []any{map[string]string{"to": Multicall3Address, "data": calldata}, "latest"},
+		}).
+		SetResult(&result).
+		Post("")
+
+	if err != nil {
+		return nil, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch token balances for " + owner)
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch token balances for " + owner)
+	}
+
+	if result.Error != nil {
+		return nil, fetcher.NewValidationError(fmt.Sprintf("multicall failed for %s: %s (code %d)",
+			owner, result.Error.Message, result.Error.Code))
+	}
+
+	balances, err := decodeBalanceOfResults(result.Result, len(contracts))
+	if err != nil {
+		return nil, fetcher.NewValidationError(fmt.Sprintf("failed to decode multicall result for %s: %v", owner, err))
+	}
+
+	return balances, nil
+}
+
+// encodeBalanceOfCalls ABI-encodes aggregate3 calldata with one balanceOf(owner) call per
+// contract, each allowed to fail on its own
+func encodeBalanceOfCalls(owner string, contracts []string) (string, error) {
+	ownerWord, err := addressWord(owner)
+	if err != nil {
+		return "", err
+	}
+
+	// balanceOf calldata is the selector plus one word, padded to two words
+	inner := make([]byte, 2*abiWord)
+	selector, _ := hex.DecodeString(balanceOfSelector)
+	copy(inner, selector)
+	copy(inner[4:], ownerWord)
+	const innerLen = 4 + abiWord
+
+	// Each Call3 tuple: target, allowFailure, offset of callData, callData length, callData
+	const tupleSize = 4*abiWord + 2*abiWord
+
+	var buf []byte
+	buf = append(buf, uintWord(abiWord)...) // offset of the calls array
+	buf = append(buf, uintWord(uint64(len(contracts)))...)
+	for i := range contracts {
+		buf = append(buf, uintWord(uint64(len(contracts)*abiWord+i*tupleSize))...)
+	}
+	for _, contract := range contracts {
+		target, err := addressWord(contract)
+		if err != nil {
+			return "", err
+		}
+		buf = append(buf, target...)
+		buf = append(buf, uintWord(1)...)         // allowFailure
+		buf = append(buf, uintWord(3*abiWord)...) // callData follows the three head words
+		buf = append(buf, uintWord(innerLen)...)  // callData length
+		buf = append(buf, inner...)
+	}
+
+	return "0x" + aggregate3Selector + hex.EncodeToString(buf), nil
+}
+
+// decodeBalanceOfResults decodes aggregate3's (bool success, bytes returnData)[] into
+// balances, leaving nil for each failed call
+func decodeBalanceOfResults(hexData string, want int) ([]*big.Int, error) {
+	digits, ok := strings.CutPrefix(hexData, "0x")
+	if !ok {
+		return nil, fmt.Errorf("result is not 0x-prefixed: %q", hexData)
+	}
+	data, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, err
+	}
+
+	arrayStart, err := readOffset(data, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	count, err := readOffset(data, arrayStart, 0)
+	if err != nil {
+		return nil, err
+	}
+	if count != want {
+		return nil, fmt.Errorf("got %d results for %d calls", count, want)
+	}
+
+	base := arrayStart + abiWord
+	balances := make([]*big.Int, count)
+	for i := range count {
+		tuple, err := readOffset(data, base+i*abiWord, base)
+		if err != nil {
+			return nil, err
+		}
+		success, err := readWord(data, tuple)
+		if err != nil {
+			return nil, err
+		}
+		if success.Sign() == 0 {
+			continue
+		}
+
+		returnData, err := readOffset(data, tuple+abiWord, tuple)
+		if err != nil {
+			return nil, err
+		}
+		length, err := readOffset(data, returnData, 0)
+		if err != nil {
+			return nil, err
+		}
+		// A non-ERC-20 contract can succeed without returning a balance
+		if length < abiWord {
+			continue
+		}
+		balance, err := readWord(data, returnData+abiWord)
+		if err != nil {
+			return nil, err
+		}
+		balances[i] = balance
+	}
+
+	return balances, nil
+}
+
+// readWord returns the 32-byte word at pos as an unsigned integer
+func readWord(data []byte, pos int) (*big.Int, error) {
+	if pos < 0 || pos+abiWord > len(data) {
+		return nil, fmt.Errorf("word at %d is out of range (%d bytes)", pos, len(data))
+	}
+	return new(big.Int).SetBytes(data[pos : pos+abiWord]), nil
+}
+
+// readOffset reads the word at pos as an offset or length, adding base to it
+func readOffset(data []byte, pos, base int) (int, error) {
+	word, err := readWord(data, pos)
+	if err != nil {
+		return 0, err
+	}
+	if !word.IsInt64() || word.Int64() > int64(len(data)) {
+		return 0, fmt.Errorf("offset %s at %d is out of range (%d bytes)", word, pos, len(data))
+	}
+	return base + int(word.Int64()), nil
+}
+
+// uintWord ABI-encodes n as a 32-byte word
+func uintWord(n uint64) []byte {
+	return new(big.Int).SetUint64(n).FillBytes(make([]byte, abiWord))
+}
+
+// addressWord ABI-encodes a 0x-prefixed 20-byte address as a left-padded 32-byte word
+func addressWord(address string) ([]byte, error) {
+	digits, ok := strings.CutPrefix(strings.ToLower(address), "0x")
+	if !ok || len(digits) != 40 {
+		return nil, fmt.Errorf("invalid address: %q", address)
+	}
+	raw, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %q", address)
+	}
+	return append(make([]byte, abiWord-len(raw)), raw...), nil
+}
@@ -0,0 +1,128 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testOwner = "0x1111111111111111111111111111111111111111"
+	testUSDC  = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testLINK  = "0x514910771af9ca656af840dff83e8264ecf986ca"
+)
+
+// word returns n as a 64-character hex ABI word
+func word(n int64) string {
+	return hex.EncodeToString(big.NewInt(n).FillBytes(make([]byte, abiWord)))
+}
+
+// encodeResults ABI-encodes aggregate3 results, one (success, balance) pair per call;
+// a nil balance encodes a failed call with empty return data
+func encodeResults(balances []*big.Int) string {
+	var heads, tails strings.Builder
+	offset := int64(len(balances) * abiWord)
+	for _, balance := range balances {
+		heads.WriteString(word(offset))
+		if balance == nil {
+			tails.WriteString(word(0) + word(0x40) + word(0))
+			offset += 3 * abiWord
+			continue
+		}
+		tails.WriteString(word(1) + word(0x40) + word(abiWord))
+		tails.WriteString(hex.EncodeToString(balance.FillBytes(make([]byte, abiWord))))
+		offset += 4 * abiWord
+	}
+	return "0x" + word(0x20) + word(int64(len(balances))) + heads.String() + tails.String()
+}
+
+func TestEncodeBalanceOfCalls(t *testing.T) {
+	calldata, err := encodeBalanceOfCalls(testOwner, []string{testUSDC})
+	if err != nil {
+		t.Fatalf("encodeBalanceOfCalls() returned unexpected error: %v", err)
+	}
+
+	ownerWord := strings.Repeat("0", 24) + strings.TrimPrefix(testOwner, "0x")
+	want := "0x82ad56cb" +
+		word(0x20) + // offset of the calls array
+		word(1) + // one call
+		word(0x20) + // offset of the first tuple
+		strings.Repeat("0", 24) + strings.TrimPrefix(testUSDC, "0x") + // target
+		word(1) + // allowFailure
+		word(0x60) + // offset of callData
+		word(0x24) + // callData length
+		"70a08231" + ownerWord + strings.Repeat("0", 56) // balanceOf(owner), padded
+
+	if calldata != want {
+		t.Errorf("encodeBalanceOfCalls() =\n%s\nwant\n%s", calldata, want)
+	}
+
+	if _, err := encodeBalanceOfCalls(testOwner, []string{"not-an-address"}); err == nil {
+		t.Error("encodeBalanceOfCalls() expected error for an invalid contract address, got nil")
+	}
+}
+
+func TestTokenBalanceReader_TokenBalances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		var call map[string]string
+		if len(req.Params) != 2 || json.Unmarshal(req.Params[0], &call) != nil {
+			t.Fatalf("params = %s, want [call, block]", req.Params)
+		}
+		if req.Method != "eth_call" || call["to"] != Multicall3Address || !strings.HasPrefix(call["data"], "0x82ad56cb") {
+			t.Errorf("got %s to %s with data %.10s, want eth_call to Multicall3 aggregate3", req.Method, call["to"], call["data"])
+		}
+
+		// 2.5 USDC; the LINK call fails
+		result := encodeResults([]*big.Int{big.NewInt(2500000), nil})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": "` + result + `"}`))
+	}))
+	defer server.Close()
+
+	balances, err := NewTokenBalanceReader(server.URL).TokenBalances(context.Background(), testOwner, []string{testUSDC, testLINK})
+	if err != nil {
+		t.Fatalf("TokenBalances() returned unexpected error: %v", err)
+	}
+
+	if len(balances) != 2 {
+		t.Fatalf("TokenBalances() returned %d balances, want 2", len(balances))
+	}
+	if balances[0] == nil || balances[0].Int64() != 2500000 {
+		t.Errorf("balances[0] = %v, want 2500000", balances[0])
+	}
+	if balances[1] != nil {
+		t.Errorf("balances[1] = %v, want nil for the failed call", balances[1])
+	}
+}
+
+func TestDecodeBalanceOfResults_Malformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not hex", "0xzz"},
+		{"truncated", "0x" + word(0x20)},
+		{"wrong count", encodeResults([]*big.Int{big.NewInt(1)})},
+		{"offset out of range", "0x" + word(0x20) + word(2) + word(0x1000) + word(0x1000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeBalanceOfResults(tt.data, 2); err == nil {
+				t.Error("decodeBalanceOfResults() expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package ethrpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+	"financefetcher/internal/wei"
+
+	"resty.dev/v3"
+)
+
+// rpcRequest is a JSON-RPC 2.0 request body
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+// RPCError is the error object of a failed JSON-RPC call
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// BalanceResponse represents the JSON-RPC response to eth_getBalance
+type BalanceResponse struct {
+	JSONRPC string    `json:"jsonrpc"`
+	ID      int       `json:"id"`
+	Result  string    `json:"result"` // Balance in wei as a 0x-prefixed hex string
+	Error   *RPCError `json:"error,omitempty"`
+}
+
+// WalletFetcher fetches an Ethereum wallet balance in USD from a JSON-RPC endpoint
+// (e.g. Alchemy or Infura), pricing it with a separate ETH/USD price source
+type WalletFetcher struct {
+	address string
+	price   fetcher.Fetcher
+	client  *resty.Client
+}
+
+// Option configures optional behavior of a WalletFetcher
+type Option func(*WalletFetcher)
+
+// WithClientOptions applies HTTP client options, e.g. fetcher.WithoutRetries()
+func WithClientOptions(opts ...fetcher.ClientOption) Option {
+	return func(f *WalletFetcher) {
+		for _, opt := range opts {
+			opt(f.client)
+		}
+	}
+}
+
+// NewWalletFetcher creates a new wallet balance fetcher. rpcURL is the full endpoint,
+// including any API key in the path; price must return the ETH/USD price.
+func NewWalletFetcher(rpcURL, address string, price fetcher.Fetcher, opts ...Option) *WalletFetcher {
+	client := fetcher.NewHTTPClient(rpcURL, fetcher.WithRateLimitedRetries(ratelimit.APIEthRPC))
+
+	// DEBUG_HTTP request logging can't redact a key embedded in the URL path, so keep it off
+	client.SetDebug(false)
+
+	f := &WalletFetcher{
+		address: address,
+		price:   price,
+		client:  client,
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+// Fetch retrieves the wallet balance in USD
+func (f *WalletFetcher) Fetch(ctx context.Context) (float64, error) {
+	// First, get the current ETH/USD price
+	ethUSD, err := f.price.Fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Apply rate limiting for the balance request
+	limiter := ratelimit.GetLimiter()
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIEthRPC)
+	if err != nil {
+		return 0, fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIEthRPC, "address", f.address, "wait_duration", waited)
+
+	slog.Debug("fetching wallet balance over JSON-RPC", "run_id", fetcher.RunIDFromContext(ctx), "address", f.address)
+
+	// Then get the wallet balance in wei
+	var balanceResult BalanceResponse
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetBody(rpcRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "eth_getBalance",
+			Params:  []any{f.address, "latest"},
+		}).
+		SetResult(&balanceResult).
+		Post("")
+
+	if err != nil {
+		return 0, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch wallet balance for " + f.address)
+	}
+
+	if !resp.IsSuccess() {
+		return 0, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch wallet balance for " + f.address)
+	}
+
+	// JSON-RPC reports call failures (e.g. an invalid address) in the body with HTTP 200
+	if balanceResult.Error != nil {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("eth_getBalance failed for %s: %s (code %d)",
+			f.address, balanceResult.Error.Message, balanceResult.Error.Code))
+	}
+
+	if balanceResult.Result == "" {
+		return 0, fetcher.NewValidationError("balance not found in response")
+	}
+
+	return hexWeiToUSD(balanceResult.Result, ethUSD)
+}
+
+// hexWeiToUSD converts a wei balance (0x-prefixed hex string) to its USD value at the given ETH/USD price
+func hexWeiToUSD(balance string, ethUSD float64) (float64, error) {
+	digits, ok := strings.CutPrefix(balance, "0x")
+	if !ok {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("failed to parse balance: %s", balance))
+	}
+
+	weiBalance, ok := new(big.Int).SetString(digits, 16)
+	if !ok {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("failed to parse balance: %s", balance))
+	}
+
+	return wei.ToUSD(weiBalance, ethUSD), nil
+}
+
+// Key returns the Redis key for this fetcher
+func (f *WalletFetcher) Key() string {
+	return fmt.Sprintf("fetcher:ethrpc:%s", f.address)
+}
@@ -0,0 +1,119 @@
+package ethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+	"financefetcher/internal/testutil"
+)
+
+// Run this package's tests without real API rate limits
+func init() {
+	ratelimit.SetTestMode(true)
+}
+
+// newRPCServer returns a server that answers eth_getBalance with the given JSON body
+func newRPCServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if r.Method != http.MethodPost || req.Method != "eth_getBalance" {
+			t.Errorf("got %s %s, want POST eth_getBalance", r.Method, req.Method)
+		}
+		if len(req.Params) != 2 || req.Params[0] != "0x123" || req.Params[1] != "latest" {
+			t.Errorf("params = %v, want [0x123 latest]", req.Params)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestWalletFetcher_Key(t *testing.T) {
+	address := "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb"
+	fetcher := NewWalletFetcher("http://localhost", address, testutil.NewMockFetcher("test:price", 2000, nil))
+
+	expectedKey := "fetcher:ethrpc:" + address
+	if got := fetcher.Key(); got != expectedKey {
+		t.Errorf("Key() = %q, want %q", got, expectedKey)
+	}
+}
+
+func TestWalletFetcher_Fetch_Success(t *testing.T) {
+	// 1.5 ETH = 0x14d1120d7b160000 wei
+	server := newRPCServer(t, `{"jsonrpc": "2.0", "id": 1, "result": "0x14d1120d7b160000"}`)
+	defer server.Close()
+
+	fetcher := NewWalletFetcher(server.URL, "0x123", testutil.NewMockFetcher("test:price", 2000.0, nil))
+
+	value, err := fetcher.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	// Expected: 1.5 ETH * $2000 = $3000
+	if value != 3000.0 {
+		t.Errorf("Fetch() = %.2f, want 3000.00", value)
+	}
+}
+
+func TestWalletFetcher_Fetch_RPCError(t *testing.T) {
+	server := newRPCServer(t, `{"jsonrpc": "2.0", "id": 1, "error": {"code": -32602, "message": "invalid address"}}`)
+	defer server.Close()
+
+	fetcher := NewWalletFetcher(server.URL, "0x123", testutil.NewMockFetcher("test:price", 2000.0, nil))
+
+	_, err := fetcher.Fetch(context.Background())
+	if err == nil {
+		t.Fatal("Fetch() expected error, got nil")
+	}
+	if got := fetcherpkg.ErrorTypeOf(err); got != fetcherpkg.ErrorTypeValidation {
+		t.Errorf("ErrorTypeOf() = %v, want %v", got, fetcherpkg.ErrorTypeValidation)
+	}
+}
+
+func TestWalletFetcher_Fetch_PriceError(t *testing.T) {
+	priceErr := errors.New("price unavailable")
+	fetcher := NewWalletFetcher("http://localhost", "0x123", testutil.NewMockFetcher("test:price", 0, priceErr))
+
+	if _, err := fetcher.Fetch(context.Background()); !errors.Is(err, priceErr) {
+		t.Errorf("Fetch() error = %v, want %v", err, priceErr)
+	}
+}
+
+func TestHexWeiToUSD(t *testing.T) {
+	tests := []struct {
+		name    string
+		wei     string
+		want    float64
+		wantErr bool
+	}{
+		{name: "zero", wei: "0x0", want: 0},
+		{name: "one ether", wei: "0xde0b6b3a7640000", want: 2000},
+		{name: "missing prefix", wei: "de0b6b3a7640000", wantErr: true},
+		{name: "not hex", wei: "0xzz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hexWeiToUSD(tt.wei, 2000)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("hexWeiToUSD(%q) error = %v, wantErr %v", tt.wei, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("hexWeiToUSD(%q) = %v, want %v", tt.wei, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package fetcher
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheMaxEntries bounds a ResultCache unless WithMaxEntries says otherwise
+	DefaultCacheMaxEntries = 1000
+
+	// DefaultCacheEvictionInterval is how often a ResultCache sweeps out expired entries
+	// unless WithEvictionInterval says otherwise
+	DefaultCacheEvictionInterval = time.Minute
+)
+
+// ResultCache reuses a fetched value for ttl, keyed by fetcher Key, so long-running
+// processes don't call an API again for a value they just fetched. Memory stays bounded
+// two ways: once the cache holds its maximum number of entries, the least recently used
+// one is evicted for each new key, and expired entries are swept out periodically so
+// keys that are never fetched again don't linger.
+type ResultCache struct {
+	ttl              time.Duration
+	maxEntries       int
+	evictionInterval time.Duration
+
+	// now is the time source for expiry (for tests)
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*list.Element
+	order     *list.List // of *cacheEntry, most recently used first
+	lastSweep time.Time
+}
+
+// cacheEntry is a cached value along with the time it was fetched
+type cacheEntry struct {
+	key       string
+	value     float64
+	fetchedAt time.Time
+}
+
+// CacheOption configures a ResultCache
+type CacheOption func(*ResultCache)
+
+// WithMaxEntries bounds the cache to n entries, evicting the least recently used entry
+// when a new key would exceed it. Zero or less leaves the cache unbounded.
+func WithMaxEntries(n int) CacheOption {
+	return func(c *ResultCache) {
+		c.maxEntries = n
+	}
+}
+
+// WithEvictionInterval sets how often expired entries are swept out. Sweeps piggyback on
+// cache lookups, so an idle cache does no work. Zero or less disables sweeping, leaving
+// expired entries in place until their key is looked up again or they are evicted.
+func WithEvictionInterval(interval time.Duration) CacheOption {
+	return func(c *ResultCache) {
+		c.evictionInterval = interval
+	}
+}
+
+// NewResultCache creates a cache that reuses fetched values for ttl
+func NewResultCache(ttl time.Duration, opts ...CacheOption) *ResultCache {
+	c := &ResultCache{
+		ttl:              ttl,
+		maxEntries:       DefaultCacheMaxEntries,
+		evictionInterval: DefaultCacheEvictionInterval,
+		now:              time.Now,
+		entries:          make(map[string]*list.Element),
+		order:            list.New(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.lastSweep = c.now()
+	return c
+}
+
+// Wrap returns f cached by c. Fetchers wrapped by the same cache share its entries,
+// so two fetchers with the same Key share a cached value.
+func (c *ResultCache) Wrap(f Fetcher) *CachedFetcher {
+	return &CachedFetcher{Fetcher: f, cache: c}
+}
+
+// Len returns the number of entries currently held, including expired entries that
+// haven't been swept yet
+func (c *ResultCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Invalidate drops the cached value for key, if any, so the next fetch of key calls the
+// API again
+func (c *ResultCache) Invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeLocked(elem)
+	}
+}
+
+// InvalidateAll drops every cached value
+func (c *ResultCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// get returns the cached value for key if it is fresh, marking it most recently used
+func (c *ResultCache) get(key string) (value float64, age time.Duration, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.sweepLocked(now)
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return 0, 0, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	age = now.Sub(entry.fetchedAt)
+	if age >= c.ttl {
+		c.removeLocked(elem)
+		return 0, 0, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, age, true
+}
+
+// put caches value for key, evicting least recently used entries past the size bound
+func (c *ResultCache) put(key string, value float64) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, value: value, fetchedAt: c.now()}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.removeLocked(c.order.Back())
+	}
+}
+
+// sweepLocked drops every expired entry if at least evictionInterval has passed since
+// the last sweep. c.mu must be held.
+func (c *ResultCache) sweepLocked(now time.Time) {
+	if c.evictionInterval <= 0 || now.Sub(c.lastSweep) < c.evictionInterval {
+		return
+	}
+	c.lastSweep = now
+
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		if now.Sub(elem.Value.(*cacheEntry).fetchedAt) >= c.ttl {
+			c.removeLocked(elem)
+		}
+		elem = next
+	}
+}
+
+// removeLocked drops elem from the cache. c.mu must be held.
+func (c *ResultCache) removeLocked(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+// CachedFetcher decorates a Fetcher so a successful fetch is reused until its cache's
+// TTL passes. Failed fetches are not cached.
+type CachedFetcher struct {
+	Fetcher
+	cache *ResultCache
+}
+
+// Fetch returns the cached value for the key if it is fresh, otherwise fetches from the
+// wrapped fetcher and caches the result. Unlike the ETH price cache, the lock isn't held
+// while fetching, so a slow API doesn't hold up cache hits for other keys.
+func (f *CachedFetcher) Fetch(ctx context.Context) (float64, error) {
+	key := f.Key()
+
+	if value, age, ok := f.cache.get(key); ok {
+		slog.Debug("using cached result", "run_id", RunIDFromContext(ctx), "key", key, "age", age)
+		return value, nil
+	}
+
+	value, err := f.Fetcher.Fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	f.cache.put(key, value)
+	return value, nil
+}
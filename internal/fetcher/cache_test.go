@@ -0,0 +1,211 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingFetcher returns value and counts how often it was called
+type countingFetcher struct {
+	key   string
+	value float64
+	err   error
+	calls int
+}
+
+func (f *countingFetcher) Fetch(ctx context.Context) (float64, error) {
+	f.calls++
+	return f.value, f.err
+}
+
+func (f *countingFetcher) Key() string { return f.key }
+
+// fakeClock is a manually advanced time source
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// newTestCache creates a cache on a fake clock
+func newTestCache(ttl time.Duration, opts ...CacheOption) (*ResultCache, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	opts = append([]CacheOption{func(c *ResultCache) { c.now = clock.Now }}, opts...)
+	return NewResultCache(ttl, opts...), clock
+}
+
+func TestResultCache_ReusesFreshValues(t *testing.T) {
+	cache, clock := newTestCache(time.Minute)
+	inner := &countingFetcher{key: "fetcher:alphavantage:AAPL", value: 100}
+	f := cache.Wrap(inner)
+	ctx := context.Background()
+
+	for range 3 {
+		value, err := f.Fetch(ctx)
+		if err != nil {
+			t.Fatalf("Fetch() returned unexpected error: %v", err)
+		}
+		if value != 100 {
+			t.Errorf("Fetch() = %v, want 100", value)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("wrapped fetcher called %d times within the TTL, want 1", inner.calls)
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := f.Fetch(ctx); err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("wrapped fetcher called %d times after the TTL, want 2", inner.calls)
+	}
+}
+
+func TestResultCache_DoesNotCacheErrors(t *testing.T) {
+	cache, _ := newTestCache(time.Minute)
+	inner := &countingFetcher{key: "fetcher:alphavantage:AAPL", err: errors.New("boom")}
+	f := cache.Wrap(inner)
+
+	for range 2 {
+		if _, err := f.Fetch(context.Background()); err == nil {
+			t.Fatal("Fetch() returned nil error, want the wrapped fetcher's error")
+		}
+	}
+	if inner.calls != 2 {
+		t.Errorf("wrapped fetcher called %d times, want 2", inner.calls)
+	}
+	if got := cache.Len(); got != 0 {
+		t.Errorf("Len() = %d, want 0", got)
+	}
+}
+
+func TestResultCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache, _ := newTestCache(time.Hour, WithMaxEntries(2))
+	aapl := &countingFetcher{key: "fetcher:alphavantage:AAPL", value: 1}
+	msft := &countingFetcher{key: "fetcher:alphavantage:MSFT", value: 2}
+	goog := &countingFetcher{key: "fetcher:alphavantage:GOOG", value: 3}
+	ctx := context.Background()
+
+	// Touch AAPL after MSFT so MSFT is least recently used when GOOG arrives
+	for _, f := range []*countingFetcher{aapl, msft, aapl, goog} {
+		if _, err := cache.Wrap(f).Fetch(ctx); err != nil {
+			t.Fatalf("Fetch() returned unexpected error: %v", err)
+		}
+	}
+
+	if got := cache.Len(); got != 2 {
+		t.Fatalf("Len() = %d, want 2", got)
+	}
+
+	for _, f := range []*countingFetcher{aapl, goog, msft} {
+		if _, err := cache.Wrap(f).Fetch(ctx); err != nil {
+			t.Fatalf("Fetch() returned unexpected error: %v", err)
+		}
+	}
+	if aapl.calls != 1 {
+		t.Errorf("AAPL fetched %d times, want 1 (recently used, should stay cached)", aapl.calls)
+	}
+	if goog.calls != 1 {
+		t.Errorf("GOOG fetched %d times, want 1", goog.calls)
+	}
+	if msft.calls != 2 {
+		t.Errorf("MSFT fetched %d times, want 2 (least recently used, should be evicted)", msft.calls)
+	}
+}
+
+func TestResultCache_SweepsExpiredEntries(t *testing.T) {
+	cache, clock := newTestCache(time.Minute, WithMaxEntries(0), WithEvictionInterval(5*time.Minute))
+	ctx := context.Background()
+
+	// Churned keys that are never fetched again
+	for _, key := range []string{"fetcher:etherscan:0x1", "fetcher:etherscan:0x2", "fetcher:etherscan:0x3"} {
+		if _, err := cache.Wrap(&countingFetcher{key: key, value: 1}).Fetch(ctx); err != nil {
+			t.Fatalf("Fetch() returned unexpected error: %v", err)
+		}
+	}
+
+	// Expired, but no sweep is due yet
+	clock.Advance(2 * time.Minute)
+	live := cache.Wrap(&countingFetcher{key: "fetcher:etherscan:0x4", value: 1})
+	if _, err := live.Fetch(ctx); err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+	if got := cache.Len(); got != 4 {
+		t.Fatalf("Len() before the sweep = %d, want 4", got)
+	}
+
+	// The next lookup after the interval sweeps the churned keys; 0x4 has expired too
+	clock.Advance(3 * time.Minute)
+	if _, err := live.Fetch(ctx); err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+	if got := cache.Len(); got != 1 {
+		t.Errorf("Len() after the sweep = %d, want 1 (only the refetched key)", got)
+	}
+}
+
+func TestResultCache_Defaults(t *testing.T) {
+	cache := NewResultCache(time.Minute)
+
+	if cache.maxEntries != DefaultCacheMaxEntries {
+		t.Errorf("maxEntries = %d, want %d", cache.maxEntries, DefaultCacheMaxEntries)
+	}
+	if cache.evictionInterval != DefaultCacheEvictionInterval {
+		t.Errorf("evictionInterval = %v, want %v", cache.evictionInterval, DefaultCacheEvictionInterval)
+	}
+}
+
+func TestResultCache_Invalidate(t *testing.T) {
+	cache, _ := newTestCache(time.Minute)
+	aapl := &countingFetcher{key: "fetcher:alphavantage:AAPL", value: 100}
+	msft := &countingFetcher{key: "fetcher:alphavantage:MSFT", value: 200}
+	ctx := context.Background()
+
+	for _, f := range []*countingFetcher{aapl, msft} {
+		cache.Wrap(f).Fetch(ctx)
+	}
+
+	cache.Invalidate(aapl.key)
+	cache.Invalidate("fetcher:alphavantage:UNKNOWN")
+
+	if cache.Len() != 1 {
+		t.Errorf("Len() = %d after invalidating one key, want 1", cache.Len())
+	}
+	for _, f := range []*countingFetcher{aapl, msft} {
+		cache.Wrap(f).Fetch(ctx)
+	}
+	if aapl.calls != 2 {
+		t.Errorf("invalidated fetcher called %d times, want 2", aapl.calls)
+	}
+	if msft.calls != 1 {
+		t.Errorf("other fetcher called %d times, want 1 (still cached)", msft.calls)
+	}
+}
+
+func TestResultCache_InvalidateAll(t *testing.T) {
+	cache, _ := newTestCache(time.Minute)
+	aapl := &countingFetcher{key: "fetcher:alphavantage:AAPL", value: 100}
+	msft := &countingFetcher{key: "fetcher:alphavantage:MSFT", value: 200}
+	ctx := context.Background()
+
+	for _, f := range []*countingFetcher{aapl, msft} {
+		cache.Wrap(f).Fetch(ctx)
+	}
+
+	cache.InvalidateAll()
+
+	if cache.Len() != 0 {
+		t.Errorf("Len() = %d after InvalidateAll, want 0", cache.Len())
+	}
+	for _, f := range []*countingFetcher{aapl, msft} {
+		cache.Wrap(f).Fetch(ctx)
+		if f.calls != 2 {
+			t.Errorf("%s fetcher called %d times after InvalidateAll, want 2", f.key, f.calls)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package fetcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"financefetcher/internal/registry"
+)
+
+// ValueStore reads and records the latest value per key. *registry.Registry satisfies it;
+// a persistent store (e.g. Redis) can implement it to track changes across restarts.
+type ValueStore interface {
+	Get(key string) (registry.Entry, bool)
+	Set(key string, value float64, ts time.Time)
+}
+
+// Change describes how far a value moved since it was last recorded
+type Change struct {
+	PrevValue float64
+	Delta     float64
+
+	// DeltaPct is Delta as a percentage of PrevValue, or 0 when PrevValue is 0
+	DeltaPct float64
+}
+
+// ChangeReporter is implemented by fetchers that can report how their last value moved
+type ChangeReporter interface {
+	LastChange() (Change, bool)
+}
+
+// ChangeFetcher decorates a Fetcher to track how its value moves between runs. Each
+// successful Fetch compares the new value with the one stored under the same key,
+// remembers the change, and stores the new value.
+type ChangeFetcher struct {
+	Fetcher
+	store ValueStore
+
+	mu     sync.Mutex
+	change Change
+	ok     bool
+}
+
+// NewChangeFetcher wraps f so each fetch reports its change against store
+func NewChangeFetcher(f Fetcher, store ValueStore) *ChangeFetcher {
+	return &ChangeFetcher{Fetcher: f, store: store}
+}
+
+// Fetch retrieves the value from the wrapped fetcher and records its change
+func (f *ChangeFetcher) Fetch(ctx context.Context) (float64, error) {
+	value, err := f.Fetcher.Fetch(ctx)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.change, f.ok = Change{}, false
+	if err != nil {
+		return value, err
+	}
+
+	key := f.Key()
+	if prev, found := f.store.Get(key); found {
+		f.change = computeChange(prev.Value, value)
+		f.ok = true
+	}
+	f.store.Set(key, value, time.Now())
+
+	return value, nil
+}
+
+// LastChange returns the change computed by the most recent Fetch. It reports false if
+// that fetch failed or no previous value was stored.
+func (f *ChangeFetcher) LastChange() (Change, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.change, f.ok
+}
+
+// ChangeOf returns the change reported by the fetcher's most recent Fetch, or false if
+// it doesn't track changes. It looks through a LabeledFetcher to the fetcher it wraps.
+func ChangeOf(f Fetcher) (Change, bool) {
+	switch ft := f.(type) {
+	case ChangeReporter:
+		return ft.LastChange()
+	case LabeledFetcher:
+		return ChangeOf(ft.Fetcher)
+	}
+	return Change{}, false
+}
+
+// computeChange returns the change from prev to value
+func computeChange(prev, value float64) Change {
+	change := Change{PrevValue: prev, Delta: value - prev}
+	if prev != 0 {
+		change.DeltaPct = change.Delta / prev * 100
+	}
+	return change
+}
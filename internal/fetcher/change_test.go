@@ -0,0 +1,71 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"financefetcher/internal/registry"
+)
+
+// valueFetcher returns its value or error from Fetch
+type valueFetcher struct {
+	key   string
+	value float64
+	err   error
+}
+
+func (f *valueFetcher) Fetch(ctx context.Context) (float64, error) { return f.value, f.err }
+func (f *valueFetcher) Key() string                                { return f.key }
+
+func TestChangeFetcher(t *testing.T) {
+	store := registry.New()
+	inner := &valueFetcher{key: "fetcher:alphavantage:AAPL", value: 100}
+	f := NewChangeFetcher(inner, store)
+	ctx := context.Background()
+
+	// The first fetch has nothing to compare against
+	if _, err := f.Fetch(ctx); err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+	if _, ok := f.LastChange(); ok {
+		t.Error("LastChange() reported a change without a previous value")
+	}
+
+	inner.value = 101.2
+	if _, err := f.Fetch(ctx); err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	change, ok := f.LastChange()
+	if !ok {
+		t.Fatal("LastChange() reported no change after a second fetch")
+	}
+	if change.PrevValue != 100 || math.Abs(change.Delta-1.2) > 1e-9 || math.Abs(change.DeltaPct-1.2) > 1e-9 {
+		t.Errorf("LastChange() = %+v, want prev 100, delta 1.2, 1.2%%", change)
+	}
+
+	if entry, _ := store.Get(inner.key); entry.Value != 101.2 {
+		t.Errorf("stored value = %v, want 101.2", entry.Value)
+	}
+
+	// A failed fetch reports no change and leaves the stored value alone
+	inner.err = errors.New("boom")
+	if _, err := f.Fetch(ctx); err == nil {
+		t.Fatal("Fetch() expected error, got nil")
+	}
+	if _, ok := f.LastChange(); ok {
+		t.Error("LastChange() reported a change after a failed fetch")
+	}
+	if entry, _ := store.Get(inner.key); entry.Value != 101.2 {
+		t.Errorf("stored value = %v after failure, want 101.2", entry.Value)
+	}
+}
+
+func TestComputeChange_ZeroPrevious(t *testing.T) {
+	change := computeChange(0, 50)
+	if change.Delta != 50 || change.DeltaPct != 0 {
+		t.Errorf("computeChange(0, 50) = %+v, want delta 50 and no percentage", change)
+	}
+}
@@ -1,7 +1,14 @@
 package fetcher
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+
+	"financefetcher/internal/ratelimit"
+
+	"resty.dev/v3"
 )
 
 // ErrorType represents the category of error that occurred during a fetch operation
@@ -20,6 +27,8 @@ const (
 	ErrorTypeValidation ErrorType = "validation"
 	// ErrorTypeTimeout indicates the request timed out
 	ErrorTypeTimeout ErrorType = "timeout"
+	// ErrorTypeCanceled indicates the caller canceled the fetch before it completed
+	ErrorTypeCanceled ErrorType = "canceled"
 	// ErrorTypeUnknown indicates an error of unknown type
 	ErrorTypeUnknown ErrorType = "unknown"
 )
@@ -31,14 +40,26 @@ type FetchError struct {
 	StatusCode int
 	Message    string
 	Cause      error
+
+	// Attempts is how many times the request was sent, including retries (0 if unknown)
+	Attempts int
+
+	// Exhausted is true when every configured retry was used before giving up, telling
+	// "tried hard and failed" apart from a failure that wasn't (or couldn't be) retried
+	Exhausted bool
 }
 
 // Error implements the error interface
 func (e *FetchError) Error() string {
+	msg := fmt.Sprintf("%s error: %s", e.Type, e.Message)
 	if e.StatusCode > 0 {
-		return fmt.Sprintf("%s error (status %d): %s", e.Type, e.StatusCode, e.Message)
+		msg = fmt.Sprintf("%s error (status %d): %s", e.Type, e.StatusCode, e.Message)
 	}
-	return fmt.Sprintf("%s error: %s", e.Type, e.Message)
+	// An exhausted error's message already says how many retries were made
+	if e.Attempts > 1 && !e.Exhausted {
+		msg += fmt.Sprintf(" (failed after %d attempts)", e.Attempts)
+	}
+	return msg
 }
 
 // Unwrap implements error unwrapping for errors.Is and errors.As
@@ -46,6 +67,13 @@ func (e *FetchError) Unwrap() error {
 	return e.Cause
 }
 
+// WithContext prefixes the message with context such as the ticker or address being
+// fetched, so callers can return the FetchError directly instead of wrapping it.
+func (e *FetchError) WithContext(prefix string) *FetchError {
+	e.Message = prefix + ": " + e.Message
+	return e
+}
+
 // NewNetworkError creates a network error
 func NewNetworkError(cause error) *FetchError {
 	return &FetchError{
@@ -105,13 +133,46 @@ func NewTimeoutError(cause error) *FetchError {
 	}
 }
 
+// WithAttempts records how many times the request behind resp was sent, so the final
+// error shows that retries happened. When the request used every retry it was allowed,
+// the error is marked Exhausted and its message ends with "after N retries". A nil resp
+// leaves the error unchanged.
+func (e *FetchError) WithAttempts(resp *resty.Response) *FetchError {
+	if resp == nil || resp.Request == nil {
+		return e
+	}
+
+	e.Attempts = resp.Request.Attempt
+	if retries := resp.Request.RetryCount; retries > 0 && e.Attempts > retries && !e.Exhausted {
+		e.Exhausted = true
+		if retries == 1 {
+			e.Message += " after 1 retry"
+		} else {
+			e.Message += fmt.Sprintf(" after %d retries", retries)
+		}
+	}
+	return e
+}
+
+// NewCanceledError creates a canceled error. Cancellation is deliberate, so it is not retryable.
+func NewCanceledError(cause error) *FetchError {
+	return &FetchError{
+		Type:      ErrorTypeCanceled,
+		Retryable: false,
+		Message:   "request canceled",
+		Cause:     cause,
+	}
+}
+
 // ClassifyHTTPError classifies an HTTP status code into an appropriate FetchError
 func ClassifyHTTPError(statusCode int) *FetchError {
 	switch {
 	case statusCode == 429:
 		return NewRateLimitError(statusCode)
 	case statusCode >= 500:
-		return NewServerError(statusCode)
+		fetchErr := NewServerError(statusCode)
+		fetchErr.Retryable = !isPermanentServerError(statusCode)
+		return fetchErr
 	case statusCode >= 400:
 		return NewClientError(statusCode, fmt.Sprintf("client error: HTTP %d", statusCode))
 	default:
@@ -122,4 +183,96 @@ func ClassifyHTTPError(statusCode int) *FetchError {
 			Message:    fmt.Sprintf("unexpected status code: %d", statusCode),
 		}
 	}
-}
\ No newline at end of file
+}
+
+// isPermanentServerError reports whether a 5xx status means the server will never handle
+// the request (501 Not Implemented, 505 HTTP Version Not Supported), so retrying is pointless
+func isPermanentServerError(statusCode int) bool {
+	return statusCode == http.StatusNotImplemented || statusCode == http.StatusHTTPVersionNotSupported
+}
+
+// ClassifyLimiterError classifies an error returned by the rate limiter's Wait.
+// In non-blocking mode a request that would have to wait is a rate limit error,
+// so callers can decide whether to wait. A wait that would outlast the context's
+// deadline is a timeout reported before blocking. If the context ended while waiting,
+// a cancellation is a canceled error and anything else (a passed deadline) is a timeout.
+// A limiter whose burst can never permit a request is a rate limit error that retrying
+// won't fix.
+func ClassifyLimiterError(err error) *FetchError {
+	if errors.Is(err, ratelimit.ErrExceedsBurst) {
+		return &FetchError{
+			Type:      ErrorTypeRateLimit,
+			Retryable: false,
+			Message:   "rate limiter burst is too small to permit a request",
+			Cause:     err,
+		}
+	}
+	if errors.Is(err, ratelimit.ErrExceedsDeadline) {
+		return &FetchError{
+			Type:      ErrorTypeTimeout,
+			Retryable: true,
+			Message:   "rate limiter wait would exceed the deadline",
+			Cause:     err,
+		}
+	}
+	if errors.Is(err, ratelimit.ErrWouldWait) {
+		return &FetchError{
+			Type:      ErrorTypeRateLimit,
+			Retryable: true,
+			Message:   "rate limiter would block",
+			Cause:     err,
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		return NewCanceledError(err)
+	}
+	return NewTimeoutError(err)
+}
+
+// ClassifyRequestError classifies an error returned while executing a request.
+// Responses that exceed the client's size limit are validation errors, empty successful
+// responses (ErrEmptyResponse) are retryable server errors, and everything else
+// (connection refused, DNS, TLS, etc.) is a network error.
+func ClassifyRequestError(err error) *FetchError {
+	// Request middleware such as WithRateLimitedRetries fails with an already classified error
+	var fetchErr *FetchError
+	if errors.As(err, &fetchErr) {
+		return fetchErr
+	}
+	if errors.Is(err, ErrEmptyResponse) {
+		return &FetchError{
+			Type:      ErrorTypeServer,
+			Retryable: true,
+			Message:   "server returned an empty response body",
+			Cause:     err,
+		}
+	}
+	if errors.Is(err, resty.ErrReadExceedsThresholdLimit) {
+		return &FetchError{
+			Type:      ErrorTypeValidation,
+			Retryable: false,
+			Message:   "response body exceeds size limit",
+			Cause:     err,
+		}
+	}
+	return NewNetworkError(err)
+}
+
+// IsRetryable reports whether err (or any error it wraps) is a FetchError marked retryable
+func IsRetryable(err error) bool {
+	var fetchErr *FetchError
+	if errors.As(err, &fetchErr) {
+		return fetchErr.Retryable
+	}
+	return false
+}
+
+// ErrorTypeOf returns the ErrorType of err if it is (or wraps) a FetchError,
+// or ErrorTypeUnknown otherwise
+func ErrorTypeOf(err error) ErrorType {
+	var fetchErr *FetchError
+	if errors.As(err, &fetchErr) {
+		return fetchErr.Type
+	}
+	return ErrorTypeUnknown
+}
@@ -0,0 +1,142 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"financefetcher/internal/ratelimit"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"network error", NewNetworkError(errors.New("connection refused")), true},
+		{"server error", NewServerError(503), true},
+		{"client error", NewClientError(404, "not found"), false},
+		{"validation error", NewValidationError("bad data"), false},
+		{"canceled error", NewCanceledError(context.Canceled), false},
+		{"wrapped retryable", fmt.Errorf("failed to fetch: %w", NewRateLimitError(429)), true},
+		{"plain error", errors.New("boom"), false},
+		{"nil error", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorTypeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorType
+	}{
+		{"validation error", NewValidationError("bad data"), ErrorTypeValidation},
+		{"wrapped client error", fmt.Errorf("failed: %w", NewClientError(401, "unauthorized")), ErrorTypeClient},
+		{"plain error", errors.New("boom"), ErrorTypeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorTypeOf(tt.err); got != tt.want {
+				t.Errorf("ErrorTypeOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchError_WithContext(t *testing.T) {
+	err := NewServerError(503).WithContext("failed to fetch stock price for AAPL")
+
+	want := "server error (status 503): failed to fetch stock price for AAPL: server returned an error"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if err.Type != ErrorTypeServer || !err.Retryable {
+		t.Errorf("WithContext() changed classification: type=%q retryable=%v", err.Type, err.Retryable)
+	}
+}
+
+func TestClassifyLimiterError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantType ErrorType
+	}{
+		{"would wait", ratelimit.ErrWouldWait, ErrorTypeRateLimit},
+		{"exceeds deadline", ratelimit.ErrExceedsDeadline, ErrorTypeTimeout},
+		{"exceeds burst", ratelimit.ErrExceedsBurst, ErrorTypeRateLimit},
+		{"context canceled", context.Canceled, ErrorTypeCanceled},
+		{"deadline exceeded", context.DeadlineExceeded, ErrorTypeTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyLimiterError(tt.err)
+			if got.Type != tt.wantType {
+				t.Errorf("ClassifyLimiterError() type = %q, want %q", got.Type, tt.wantType)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Error("ClassifyLimiterError() should wrap the original error")
+			}
+		})
+	}
+}
+
+func TestClassifyHTTPError_ServerErrorRetryable(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		retryable  bool
+	}{
+		{500, true},
+		{501, false},
+		{502, true},
+		{503, true},
+		{504, true},
+		{505, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d", tt.statusCode), func(t *testing.T) {
+			err := ClassifyHTTPError(tt.statusCode)
+			if err.Type != ErrorTypeServer {
+				t.Errorf("Type = %v, want %v", err.Type, ErrorTypeServer)
+			}
+			if err.Retryable != tt.retryable {
+				t.Errorf("Retryable = %v, want %v", err.Retryable, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestFetchError_Attempts(t *testing.T) {
+	err := NewServerError(503)
+	if got, want := err.Error(), "server error (status 503): server returned an error"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	// A nil response leaves the attempt count unknown
+	if err.WithAttempts(nil).Attempts != 0 {
+		t.Errorf("Attempts = %d after WithAttempts(nil), want 0", err.Attempts)
+	}
+
+	err.Attempts = 4
+	if got, want := err.Error(), "server error (status 503): server returned an error (failed after 4 attempts)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	// An exhausted error says so in its message instead of repeating the attempt count
+	exhausted := &FetchError{Type: ErrorTypeServer, StatusCode: 503, Message: "server returned an error after 3 retries", Attempts: 4, Exhausted: true}
+	if got, want := exhausted.Error(), "server error (status 503): server returned an error after 3 retries"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
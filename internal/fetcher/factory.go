@@ -0,0 +1,107 @@
+package fetcher
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Params holds string-valued constructor parameters, e.g. from a config file or web form
+type Params map[string]string
+
+// Factory builds a Fetcher from Params
+type Factory func(params Params) (Fetcher, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a Factory available under source (e.g. "etherscan"). Provider packages
+// call it from init, so importing a provider is enough to construct its fetchers by name.
+// It panics if source is empty or already registered.
+func Register(source string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if source == "" || factory == nil {
+		panic("fetcher: Register requires a source and a factory")
+	}
+	if _, exists := factories[source]; exists {
+		panic("fetcher: Register called twice for source " + source)
+	}
+	factories[source] = factory
+}
+
+// New builds a Fetcher using the Factory registered for source
+func New(source string, params Params) (Fetcher, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[source]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown fetcher source %q", source)
+	}
+
+	f, err := factory(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s fetcher: %w", source, err)
+	}
+	return f, nil
+}
+
+// Sources returns the registered source names in sorted order
+func Sources() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	sources := make([]string, 0, len(factories))
+	for source := range factories {
+		sources = append(sources, source)
+	}
+	slices.Sort(sources)
+	return sources
+}
+
+// Require returns an error naming every key that is missing or empty
+func (p Params) Require(keys ...string) error {
+	var missing []string
+	for _, key := range keys {
+		if p[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required parameters: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Int parses key as an integer, returning 0 if it is missing or empty
+func (p Params) Int(key string) (int, error) {
+	if p[key] == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(p[key])
+	if err != nil {
+		return 0, fmt.Errorf("parameter %s must be an integer, got %q", key, p[key])
+	}
+	return n, nil
+}
+
+// Float parses key as a number, returning 0 if it is missing or empty
+func (p Params) Float(key string) (float64, error) {
+	if p[key] == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseFloat(p[key], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %s must be a number, got %q", key, p[key])
+	}
+	return n, nil
+}
@@ -0,0 +1,68 @@
+package fetcher
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRegisterAndNew(t *testing.T) {
+	Register("test-factory", func(params Params) (Fetcher, error) {
+		if err := params.Require("id"); err != nil {
+			return nil, err
+		}
+		return keyFetcher("fetcher:test-factory:" + params["id"]), nil
+	})
+
+	if !slices.Contains(Sources(), "test-factory") {
+		t.Errorf("Sources() = %v, want it to include test-factory", Sources())
+	}
+
+	f, err := New("test-factory", Params{"id": "abc"})
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	if got := f.Key(); got != "fetcher:test-factory:abc" {
+		t.Errorf("Key() = %q, want %q", got, "fetcher:test-factory:abc")
+	}
+
+	if _, err := New("test-factory", Params{}); err == nil || !strings.Contains(err.Error(), "id") {
+		t.Errorf("New() error = %v, want error naming the missing id", err)
+	}
+
+	if _, err := New("no-such-source", nil); err == nil {
+		t.Error("New() expected error for unknown source, got nil")
+	}
+}
+
+func TestRegister_Duplicate(t *testing.T) {
+	factory := func(params Params) (Fetcher, error) { return keyFetcher("k"), nil }
+	Register("test-duplicate", factory)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() did not panic for a duplicate source")
+		}
+	}()
+	Register("test-duplicate", factory)
+}
+
+func TestParams_Numbers(t *testing.T) {
+	params := Params{"beds": "3", "baths": "2.5", "bad": "many"}
+
+	if n, err := params.Int("beds"); err != nil || n != 3 {
+		t.Errorf("Int(beds) = %d, %v, want 3", n, err)
+	}
+	if n, err := params.Float("baths"); err != nil || n != 2.5 {
+		t.Errorf("Float(baths) = %v, %v, want 2.5", n, err)
+	}
+	if n, err := params.Int("missing"); err != nil || n != 0 {
+		t.Errorf("Int(missing) = %d, %v, want 0", n, err)
+	}
+	if _, err := params.Int("bad"); err == nil {
+		t.Error("Int(bad) expected error, got nil")
+	}
+	if _, err := params.Float("bad"); err == nil {
+		t.Error("Float(bad) expected error, got nil")
+	}
+}
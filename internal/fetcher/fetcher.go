@@ -1,6 +1,9 @@
 package fetcher
 
-import "context"
+import (
+	"context"
+	"strings"
+)
 
 // Fetcher is the core interface that all data fetchers must implement.
 // Each fetcher knows how to retrieve a specific piece of financial data
@@ -18,4 +21,22 @@ type Fetcher interface {
 	//   - fetcher:alphavantage:AAPL
 	//   - fetcher:rentcast:123_main_st_anytown
 	Key() string
-}
\ No newline at end of file
+}
+
+// SourceFromKey extracts the source segment from a key in the format fetcher:{source}:{identifier}.
+// Returns an empty string if the key does not follow that format.
+func SourceFromKey(key string) string {
+	parts := strings.SplitN(key, ":", 3)
+	if len(parts) < 3 || parts[0] != "fetcher" {
+		return ""
+	}
+	return parts[1]
+}
+
+// IsRateKey reports whether key belongs to an exchange rate fetcher, keyed
+// fetcher:{source}:fx:{from}-{to}. Its value is a rate such as 0.92 rather than an amount
+// of money, so it is reported like any other result but left out of totals.
+func IsRateKey(key string) bool {
+	parts := strings.SplitN(key, ":", 4)
+	return len(parts) == 4 && parts[0] == "fetcher" && parts[2] == "fx"
+}
@@ -1,9 +1,18 @@
 package fetcher
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"financefetcher/internal/ratelimit"
+
 	"resty.dev/v3"
 )
 
@@ -12,32 +21,271 @@ const (
 	defaultRetryCount       = 3
 	defaultRetryWaitTime    = 1 * time.Second
 	defaultRetryMaxWaitTime = 10 * time.Second
+
+	// defaultRetryJitter spreads each retry wait randomly by ±25%
+	defaultRetryJitter = 0.25
+
+	// maxRetryJitter caps the jitter fraction below 1, so a jittered wait never drops to zero
+	maxRetryJitter = 0.9
+
+	// defaultMaxResponseBytes bounds response bodies so a misbehaving endpoint can't exhaust memory
+	defaultMaxResponseBytes = 10 << 20 // 10MB
 )
 
-// NewHTTPClient creates a new HTTP client with retry logic and exponential backoff
-func NewHTTPClient(baseURL string) *resty.Client {
+// ErrEmptyResponse is returned when a successful response has an empty or whitespace-only
+// body, which some APIs (notably AlphaVantage) send under load instead of an error status
+var ErrEmptyResponse = errors.New("empty response body")
+
+// ClientOption configures an HTTP client created by NewHTTPClient
+type ClientOption func(*resty.Client)
+
+// retryJitterKey is the request context key carrying a client's jitter fraction
+type retryJitterKey struct{}
+
+// WithRetryJitter sets the fraction by which each retry wait is randomly spread,
+// e.g. 0.25 spreads a 2s wait across 1.5s-2.5s. A fraction of 0 disables jitter.
+// The fraction must be in [0, 1): a negative (or NaN) fraction is treated as 0, and one
+// of 1 or more is clamped to 0.9, since a full spread could retry with no wait at all.
+// The fraction travels on each request's context, where both the backoff strategy and
+// the deadline check (see deadlineTooClose) read it.
+func WithRetryJitter(fraction float64) ClientOption {
+	fraction = clampJitter(fraction)
+	return func(c *resty.Client) {
+		c.AddRequestMiddleware(func(c *resty.Client, r *resty.Request) error {
+			r.SetContext(context.WithValue(r.Context(), retryJitterKey{}, fraction))
+			return nil
+		})
+	}
+}
+
+// clampJitter limits fraction to [0, maxRetryJitter]
+func clampJitter(fraction float64) float64 {
+	if !(fraction > 0) {
+		return 0
+	}
+	return min(fraction, maxRetryJitter)
+}
+
+// retryJitter returns the jitter fraction set for r by WithRetryJitter, or the default
+func retryJitter(r *resty.Request) float64 {
+	if fraction, ok := r.Context().Value(retryJitterKey{}).(float64); ok {
+		return fraction
+	}
+	return defaultRetryJitter
+}
+
+// WithMaxResponseSize sets the maximum response body size in bytes.
+// Larger responses fail with a validation error (see ClassifyRequestError) and are not retried.
+func WithMaxResponseSize(bytes int64) ClientOption {
+	return func(c *resty.Client) {
+		c.SetResponseBodyLimit(bytes)
+	}
+}
+
+// WithRetryCount sets how many times a failed request is retried after the first attempt
+func WithRetryCount(count int) ClientOption {
+	return func(c *resty.Client) {
+		c.SetRetryCount(count)
+	}
+}
+
+// WithoutRetries disables retries so the first failure is returned as-is,
+// which is useful when debugging flaky behavior
+func WithoutRetries() ClientOption {
+	return WithRetryCount(0)
+}
+
+// WithRateLimitedRetries makes every retry wait on the rate limiter for api before it is
+// sent. Fetchers already wait before their first attempt, but resty's retries would
+// otherwise go straight out and spend quota the limiter has handed to other requests.
+// If the limiter refuses (e.g. the wait would pass the deadline), the request ends with
+// that error instead of retrying.
+func WithRateLimitedRetries(api ratelimit.API) ClientOption {
+	return func(c *resty.Client) {
+		c.AddRequestMiddleware(func(c *resty.Client, r *resty.Request) error {
+			if r.Attempt <= 1 {
+				return nil
+			}
+
+			waited, err := ratelimit.GetLimiter().WaitTimed(r.Context(), api)
+			if err != nil {
+				return ClassifyLimiterError(err)
+			}
+			slog.Debug("rate limiter wait complete before retry", "run_id", RunIDFromContext(r.Context()), "source", api, "attempt", r.Attempt, "wait_duration", waited)
+			return nil
+		})
+	}
+}
+
+// WithDebugLogging logs every request's method, URL, headers, and response status through
+// slog at debug level using resty's debug log hook. API keys in query parameters and any
+// header named like a credential (token, key, secret, auth) are redacted first, and bodies
+// are never logged.
+func WithDebugLogging() ClientOption {
+	return func(c *resty.Client) {
+		c.SetDebug(true).
+			SetDebugLogFormatter(nil).
+			OnDebugLog(logDebugRequest)
+	}
+}
+
+// debugHTTP makes NewHTTPClient enable WithDebugLogging on every client; see SetDebugHTTP
+var debugHTTP atomic.Bool
+
+// SetDebugHTTP enables or disables request logging (WithDebugLogging) for clients created
+// by NewHTTPClient from now on, e.g. from the DEBUG_HTTP setting
+func SetDebugHTTP(enabled bool) {
+	debugHTTP.Store(enabled)
+}
+
+// NewHTTPClient creates a new HTTP client with retry logic and exponential backoff.
+// Retry waits are jittered so concurrent fetchers hitting the same throttled API
+// don't retry in lockstep.
+func NewHTTPClient(baseURL string, opts ...ClientOption) *resty.Client {
 	client := resty.New().
 		SetBaseURL(baseURL).
 		SetHeader("Accept", "application/json").
 		SetRetryCount(defaultRetryCount).
 		SetRetryWaitTime(defaultRetryWaitTime).
 		SetRetryMaxWaitTime(defaultRetryMaxWaitTime).
+		SetRetryStrategy(jitteredBackoff).
+		SetResponseBodyLimit(defaultMaxResponseBytes).
+		// Keep the body readable after JSON decoding so emptyBodyMiddleware can inspect it
+		SetResponseBodyUnlimitedReads(true).
+		AddResponseMiddleware(emptyBodyMiddleware).
+		// retryCondition covers every case resty's defaults do, and the defaults
+		// would otherwise bypass its deadline check
+		SetRetryDefaultConditions(false).
 		AddRetryConditions(retryCondition).
 		AddRetryHooks(retryHook)
 
+	if debugHTTP.Load() {
+		WithDebugLogging()(client)
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
 	return client
 }
 
-// retryCondition determines whether a request should be retried based on the response and error
+// emptyBodyMiddleware fails successful responses with an empty or whitespace-only body
+// with ErrEmptyResponse. Decoding would otherwise leave the result zero-valued and the
+// fetcher would report a non-retryable "not found" error; as an error, the response is
+// retried like a network failure and classified as a retryable server error.
+func emptyBodyMiddleware(c *resty.Client, r *resty.Response) error {
+	if r.Err != nil || !r.IsSuccess() || r.StatusCode() == http.StatusNoContent {
+		return nil
+	}
+	if len(bytes.TrimSpace(r.Bytes())) == 0 {
+		return ErrEmptyResponse
+	}
+	return nil
+}
+
+// jitteredBackoff is a retry strategy using exponential backoff (the request's retry wait
+// time * 2^attempt, capped at its max wait time) with each wait randomly spread by its
+// jitter fraction (see WithRetryJitter)
+func jitteredBackoff(r *resty.Response, err error) (time.Duration, error) {
+	return applyJitter(nextRetryBackoff(r), retryJitter(r.Request), rand.Float64()), nil
+}
+
+// nextRetryBackoff returns the un-jittered backoff before retrying r, using the wait
+// times configured on its client or request
+func nextRetryBackoff(r *resty.Response) time.Duration {
+	return backoffWait(r.Request.RetryWaitTime, r.Request.RetryMaxWaitTime, retryAttempt(r))
+}
+
+// backoffWait returns the un-jittered exponential backoff for an attempt
+func backoffWait(minWait, maxWait time.Duration, attempt int) time.Duration {
+	wait := min(maxWait, minWait<<attempt)
+	if wait <= 0 {
+		// Shift overflowed for a very large attempt count
+		wait = maxWait
+	}
+	return wait
+}
+
+// retryAttempt returns the number of the attempt that just completed (at least 1)
+func retryAttempt(r *resty.Response) int {
+	if r != nil && r.Request != nil && r.Request.Attempt > 0 {
+		return r.Request.Attempt
+	}
+	return 1
+}
+
+// minNextRetryWait returns the shortest wait the client could choose before retrying r:
+// the Retry-After header resty honors for 429/503, or else the request's backoff at its
+// lowest jitter draw, never below its minimum retry wait
+func minNextRetryWait(r *resty.Response) time.Duration {
+	if r.StatusCode() == 429 || r.StatusCode() == 503 {
+		if seconds, err := strconv.Atoi(r.Header().Get("Retry-After")); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return max(r.Request.RetryWaitTime, applyJitter(nextRetryBackoff(r), retryJitter(r.Request), 0))
+}
+
+// deadlineTooClose reports whether the request's context would expire before the next retry could start
+func deadlineTooClose(r *resty.Response) bool {
+	if r.Request == nil {
+		return false
+	}
+	deadline, ok := r.Request.Context().Deadline()
+	return ok && time.Until(deadline) < minNextRetryWait(r)
+}
+
+// applyJitter spreads wait by ±fraction using r, a random value in [0, 1)
+func applyJitter(wait time.Duration, fraction, r float64) time.Duration {
+	return time.Duration(float64(wait) * (1 + fraction*(2*r-1)))
+}
+
+// retryCondition determines whether a request should be retried, drawing each retry from
+// the run's retry budget (see WithRetryBudget) when one is set
 func retryCondition(r *resty.Response, err error) bool {
+	if !shouldRetry(r, err) {
+		return false
+	}
+
+	// Waiting out a backoff that outlasts the deadline only turns the real failure into a
+	// timeout. Checked only once the failure is known to be retryable, so the log line
+	// never appears for successful or permanently failed requests.
+	if deadlineTooClose(r) {
+		slog.Debug("not retrying request, context deadline is closer than the next backoff",
+			"run_id", RunIDFromContext(r.Request.Context()),
+			"url", requestURL(r),
+			"attempt", r.Request.Attempt)
+		return false
+	}
+
+	if !takeRetryBudget(r) {
+		slog.Debug("not retrying request, retry budget for this source is exhausted",
+			"run_id", RunIDFromContext(r.Request.Context()),
+			"url", requestURL(r),
+			"attempt", r.Request.Attempt)
+		return false
+	}
+
+	return true
+}
+
+// shouldRetry determines whether a request should be retried based on the response and error
+func shouldRetry(r *resty.Response, err error) bool {
+	// An oversized body will be just as large on the next attempt
+	if errors.Is(err, resty.ErrReadExceedsThresholdLimit) {
+		return false
+	}
+
 	// Retry on network errors
 	if err != nil {
 		return true
 	}
 
-	// Retry on server errors (5xx)
+	// Retry on server errors (5xx), except those that will fail the same way every time
 	if r.StatusCode() >= 500 {
-		return true
+		return !isPermanentServerError(r.StatusCode())
 	}
 
 	// Retry on rate limit (429)
@@ -58,18 +306,35 @@ func retryCondition(r *resty.Response, err error) bool {
 	return false
 }
 
-// retryHook logs retry attempts for observability
+// logDebugRequest logs one request/response pair from resty's debug log, redacted.
+// Resty's own formatter is disabled since it would print raw credential headers and bodies.
+func logDebugRequest(dl *resty.DebugLog) {
+	req, res := dl.Request, dl.Response
+	slog.Debug("http request",
+		"method", req.Method,
+		"url", RedactURL(req.Host+req.URI),
+		"attempt", req.Attempt,
+		"headers", redactHeaders(req.Header),
+		"status_code", res.StatusCode,
+		"duration", res.Duration,
+		"response_bytes", res.Size)
+}
+
+// retryHook logs retry attempts for observability.
+// URLs and error messages are redacted so API keys never reach the logs.
 func retryHook(r *resty.Response, err error) {
 	if err != nil {
 		slog.Debug("retrying request due to error",
-			"url", r.Request.URL,
+			"run_id", RunIDFromContext(r.Request.Context()),
+			"url", requestURL(r),
 			"attempt", r.Request.Attempt,
-			"error", err.Error())
+			"error", redactLogURL(err.Error()))
 		return
 	}
 
 	slog.Debug("retrying request due to status code",
-		"url", r.Request.URL,
+		"run_id", RunIDFromContext(r.Request.Context()),
+		"url", requestURL(r),
 		"attempt", r.Request.Attempt,
 		"status_code", r.StatusCode())
 }
@@ -0,0 +1,501 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"financefetcher/internal/ratelimit"
+
+	"resty.dev/v3"
+)
+
+func TestApplyJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		wait     time.Duration
+		fraction float64
+		r        float64
+		want     time.Duration
+	}{
+		{"lowest draw", 2 * time.Second, 0.25, 0, 1500 * time.Millisecond},
+		{"middle draw", 2 * time.Second, 0.25, 0.5, 2 * time.Second},
+		{"high draw", 2 * time.Second, 0.25, 0.75, 2250 * time.Millisecond},
+		{"jitter disabled", 2 * time.Second, 0, 0.9, 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyJitter(tt.wait, tt.fraction, tt.r); got != tt.want {
+				t.Errorf("applyJitter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClampJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		fraction float64
+		want     float64
+	}{
+		{"in range", 0.25, 0.25},
+		{"zero", 0, 0},
+		{"negative", -0.5, 0},
+		{"NaN", math.NaN(), 0},
+		{"one", 1, maxRetryJitter},
+		{"above one", 3, maxRetryJitter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clampJitter(tt.fraction); got != tt.want {
+				t.Errorf("clampJitter(%v) = %v, want %v", tt.fraction, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJitteredBackoff(t *testing.T) {
+
+	tests := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 8 * time.Second},
+		{4, 10 * time.Second},
+		{100, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		resp := &resty.Response{Request: &resty.Request{Attempt: tt.attempt, RetryWaitTime: time.Second, RetryMaxWaitTime: 10 * time.Second}}
+
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 20; i++ {
+			wait, err := jitteredBackoff(resp, nil)
+			if err != nil {
+				t.Fatalf("strategy returned unexpected error: %v", err)
+			}
+
+			low := time.Duration(float64(tt.base) * 0.75)
+			high := time.Duration(float64(tt.base) * 1.25)
+			if wait < low || wait > high {
+				t.Errorf("attempt %d: wait = %v, want within [%v, %v]", tt.attempt, wait, low, high)
+			}
+			seen[wait] = true
+		}
+
+		if len(seen) < 2 {
+			t.Errorf("attempt %d: waits were not randomized", tt.attempt)
+		}
+	}
+}
+
+func TestNewHTTPClient_MaxResponseSize(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"padding": "` + strings.Repeat("x", 4096) + `"}`))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, WithMaxResponseSize(1024))
+
+	var result map[string]string
+	_, err := client.R().
+		SetContext(context.Background()).
+		SetResult(&result).
+		Get("")
+	if err == nil {
+		t.Fatal("expected error for oversized response, got nil")
+	}
+
+	fetchErr := ClassifyRequestError(err)
+	if fetchErr.Type != ErrorTypeValidation {
+		t.Errorf("expected ErrorTypeValidation, got %v", fetchErr.Type)
+	}
+	if fetchErr.Retryable {
+		t.Error("expected oversized response to be non-retryable")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 request (no retries), got %d", got)
+	}
+}
+
+func TestNewHTTPClient_ResponseWithinLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, WithMaxResponseSize(1024))
+
+	var result map[string]string
+	_, err := client.R().
+		SetContext(context.Background()).
+		SetResult(&result).
+		Get("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result["status"] != "ok" {
+		t.Errorf("expected status ok, got %q", result["status"])
+	}
+}
+
+func TestNewHTTPClient_WithoutRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, WithoutRetries())
+
+	resp, err := client.R().SetContext(context.Background()).Get("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode() != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", resp.StatusCode(), http.StatusServiceUnavailable)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected exactly 1 request with retries disabled, got %d", got)
+	}
+}
+
+func TestNewHTTPClient_NoRetryPastDeadline(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL)
+
+	// The shortest first backoff is 1.5s, so a 500ms deadline leaves no room to retry
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := client.R().SetContext(ctx).Get("")
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("expected the last response without an error, got %v", err)
+	}
+	if resp.StatusCode() != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", resp.StatusCode(), http.StatusInternalServerError)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+	if elapsed > 250*time.Millisecond {
+		t.Errorf("request took %v, expected it to return without waiting for the deadline", elapsed)
+	}
+}
+
+func TestMinNextRetryWait(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempt    int
+		status     int
+		retryAfter string
+		want       time.Duration
+	}{
+		{"first attempt", 1, 500, "", 1500 * time.Millisecond},
+		{"second attempt", 2, 500, "", 3 * time.Second},
+		{"capped", 10, 500, "", 7500 * time.Millisecond},
+		{"retry-after", 1, 429, "30", 30 * time.Second},
+		{"retry-after ignored for 500", 1, 500, "30", 1500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.retryAfter != "" {
+				header.Set("Retry-After", tt.retryAfter)
+			}
+			r := &resty.Response{
+				Request:     &resty.Request{Attempt: tt.attempt, RetryWaitTime: time.Second, RetryMaxWaitTime: 10 * time.Second},
+				RawResponse: &http.Response{StatusCode: tt.status, Header: header},
+			}
+			if got := minNextRetryWait(r); got != tt.want {
+				t.Errorf("minNextRetryWait() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_NoRetryOnNotImplemented(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL)
+
+	if _, err := client.R().SetContext(context.Background()).Get(""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected exactly 1 request for 501, got %d", got)
+	}
+}
+
+func TestNewHTTPClient_DebugLoggingRedactsKeys(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	SetDebugHTTP(true)
+	defer SetDebugHTTP(false)
+
+	client := NewHTTPClient(server.URL)
+	_, err := client.R().
+		SetHeader("X-Api-Key", "header-secret").
+		SetQueryParam("apikey", "query-secret").
+		SetQueryParam("symbol", "AAPL").
+		Get("/quote")
+	if err != nil {
+		t.Fatalf("request returned unexpected error: %v", err)
+	}
+
+	out := logs.String()
+	if !strings.Contains(out, "http request") || !strings.Contains(out, "status_code=200") {
+		t.Errorf("debug log missing request line: %s", out)
+	}
+	if !strings.Contains(out, "symbol=AAPL") {
+		t.Errorf("debug log missing query parameters: %s", out)
+	}
+	if strings.Contains(out, "header-secret") || strings.Contains(out, "query-secret") {
+		t.Errorf("debug log leaked an API key: %s", out)
+	}
+}
+
+func TestNewHTTPClient_EmptyBodyIsRetryable(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// The first response is an empty 200, as AlphaVantage sends under load
+		if requests.Add(1) == 1 {
+			w.Write([]byte(" \n"))
+			return
+		}
+		w.Write([]byte(`{"price": 1.5}`))
+	}))
+	defer server.Close()
+
+	var result struct {
+		Price float64 `json:"price"`
+	}
+	resp, err := NewHTTPClient(server.URL, WithRetryCount(1)).R().SetResult(&result).Get("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected the empty response to be retried once, got %d requests", got)
+	}
+	if result.Price != 1.5 || !resp.IsSuccess() {
+		t.Errorf("result = %+v, want price 1.5 from the retried request", result)
+	}
+}
+
+func TestNewHTTPClient_EmptyBodyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := NewHTTPClient(server.URL, WithoutRetries()).R().Get("")
+	if !errors.Is(err, ErrEmptyResponse) {
+		t.Fatalf("error = %v, want ErrEmptyResponse", err)
+	}
+
+	fetchErr := ClassifyRequestError(err).WithAttempts(resp)
+	if fetchErr.Type != ErrorTypeServer || !fetchErr.Retryable {
+		t.Errorf("ClassifyRequestError() = %s (retryable %v), want retryable server error", fetchErr.Type, fetchErr.Retryable)
+	}
+}
+
+func TestNewHTTPClient_RateLimitedRetries(t *testing.T) {
+	// One token: the first retry takes it and the second would have to wait
+	limiter := ratelimit.GetLimiter()
+	limiter.Configure(ratelimit.APIFinnhub, 1, 1)
+	t.Cleanup(limiter.Reset)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	noBackoff := func(c *resty.Client) {
+		c.SetRetryWaitTime(time.Millisecond).
+			SetRetryStrategy(func(*resty.Response, error) (time.Duration, error) { return time.Millisecond, nil })
+	}
+	client := NewHTTPClient(server.URL, WithRetryCount(3), noBackoff, WithRateLimitedRetries(ratelimit.APIFinnhub))
+	ctx := ratelimit.WithNonBlocking(context.Background())
+
+	_, err := client.R().SetContext(ctx).Get("")
+	if err == nil {
+		t.Fatal("expected the rate limiter to end the retries with an error, got nil")
+	}
+	if got := ClassifyRequestError(err).Type; got != ErrorTypeRateLimit {
+		t.Errorf("error type = %v, want %v (error: %v)", got, ErrorTypeRateLimit, err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected 2 requests (the first attempt and one rate-limited retry), got %d", got)
+	}
+}
+
+func TestNewHTTPClient_RetriesExhausted(t *testing.T) {
+	var failFirst atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The /flaky path succeeds on its second attempt; everything else always fails
+		if r.URL.Path == "/flaky" && failFirst.Add(1) > 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	noBackoff := func(c *resty.Client) {
+		c.SetRetryWaitTime(time.Millisecond).
+			SetRetryStrategy(func(*resty.Response, error) (time.Duration, error) { return time.Millisecond, nil })
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		opts          []ClientOption
+		wantAttempts  int
+		wantExhausted bool
+	}{
+		{name: "every retry used", path: "/down", opts: []ClientOption{WithRetryCount(2)}, wantAttempts: 3, wantExhausted: true},
+		{name: "retries disabled", path: "/down", opts: []ClientOption{WithoutRetries()}, wantAttempts: 1},
+		{name: "stopped early on a non-retryable error", path: "/flaky", opts: []ClientOption{WithRetryCount(2)}, wantAttempts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewHTTPClient(server.URL, append(tt.opts, noBackoff)...)
+
+			resp, err := client.R().Get(tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			fetchErr := ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp)
+			if fetchErr.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", fetchErr.Attempts, tt.wantAttempts)
+			}
+			if fetchErr.Exhausted != tt.wantExhausted {
+				t.Errorf("Exhausted = %v, want %v", fetchErr.Exhausted, tt.wantExhausted)
+			}
+			if got := strings.Contains(fetchErr.Message, "after 2 retries"); got != tt.wantExhausted {
+				t.Errorf("Message = %q, mentions retries = %v, want %v", fetchErr.Message, got, tt.wantExhausted)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_RetryLogMasksURLPath(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// An RPC endpoint that carries its key in the path, as Alchemy and Infura do
+	client := NewHTTPClient(server.URL+"/v2/path-secret", WithRetryCount(1))
+	client.SetRetryWaitTime(time.Millisecond).SetRetryMaxWaitTime(time.Millisecond)
+
+	if _, err := client.R().Get(""); err != nil {
+		t.Fatalf("request returned unexpected error: %v", err)
+	}
+
+	out := logs.String()
+	if !strings.Contains(out, "retrying request due to status code") {
+		t.Fatalf("no retry log line: %s", out)
+	}
+	if strings.Contains(out, "path-secret") {
+		t.Errorf("retry log leaked a key in the URL path: %s", out)
+	}
+}
+
+func TestMinNextRetryWait_UsesClientSettings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var got time.Duration
+	client := NewHTTPClient(server.URL, WithRetryCount(1), WithRetryJitter(0))
+	client.SetRetryWaitTime(10 * time.Millisecond).SetRetryMaxWaitTime(time.Second)
+	client.AddRetryHooks(func(r *resty.Response, err error) {
+		if r.Request.Attempt == 1 {
+			got = minNextRetryWait(r)
+		}
+	})
+
+	if _, err := client.R().Get(""); err != nil {
+		t.Fatalf("request returned unexpected error: %v", err)
+	}
+
+	// Without jitter the first backoff is exactly twice the custom wait time
+	if want := 20 * time.Millisecond; got != want {
+		t.Errorf("minNextRetryWait() = %v, want %v", got, want)
+	}
+}
+
+func TestNewHTTPClient_DeadlineLogOnlyForRetryableFailures(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	for _, status := range []int{http.StatusOK, http.StatusNotFound} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(`{}`))
+		}))
+
+		// Far too short for any backoff, but these responses aren't retried anyway
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		if _, err := NewHTTPClient(server.URL).R().SetContext(ctx).Get(""); err != nil {
+			t.Fatalf("status %d: request returned unexpected error: %v", status, err)
+		}
+		cancel()
+		server.Close()
+	}
+
+	if strings.Contains(logs.String(), "context deadline is closer") {
+		t.Errorf("deadline log line written for a response that isn't retried: %s", logs.String())
+	}
+}
@@ -0,0 +1,26 @@
+package fetcher
+
+// Labeler is implemented by fetchers that carry a human-readable name for output
+type Labeler interface {
+	Label() string
+}
+
+// LabeledFetcher attaches a display name (e.g. "Apple stock") to a Fetcher.
+// The wrapped fetcher's Key is still used for storage.
+type LabeledFetcher struct {
+	Fetcher
+	Name string
+}
+
+// Label implements the Labeler interface
+func (f LabeledFetcher) Label() string {
+	return f.Name
+}
+
+// LabelOf returns the fetcher's label, or an empty string if it has none
+func LabelOf(f Fetcher) string {
+	if l, ok := f.(Labeler); ok {
+		return l.Label()
+	}
+	return ""
+}
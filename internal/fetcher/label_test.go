@@ -0,0 +1,45 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+)
+
+// keyFetcher is a minimal Fetcher for exercising labels
+type keyFetcher string
+
+func (k keyFetcher) Fetch(ctx context.Context) (float64, error) { return 0, nil }
+func (k keyFetcher) Key() string                                { return string(k) }
+
+func TestLabelOf(t *testing.T) {
+	base := keyFetcher("fetcher:alphavantage:AAPL")
+
+	tests := []struct {
+		name    string
+		fetcher Fetcher
+		want    string
+	}{
+		{"unlabeled", base, ""},
+		{"labeled", LabeledFetcher{Fetcher: base, Name: "Apple stock"}, "Apple stock"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LabelOf(tt.fetcher); got != tt.want {
+				t.Errorf("LabelOf() = %q, want %q", got, tt.want)
+			}
+			if got := tt.fetcher.Key(); got != "fetcher:alphavantage:AAPL" {
+				t.Errorf("Key() = %q, want the wrapped key", got)
+			}
+		})
+	}
+}
+
+func TestResult_DisplayName(t *testing.T) {
+	if got := (Result{Key: "fetcher:alphavantage:AAPL"}).DisplayName(); got != "fetcher:alphavantage:AAPL" {
+		t.Errorf("DisplayName() = %q, want key fallback", got)
+	}
+	if got := (Result{Key: "fetcher:alphavantage:AAPL", Label: "Apple stock"}).DisplayName(); got != "Apple stock" {
+		t.Errorf("DisplayName() = %q, want %q", got, "Apple stock")
+	}
+}
@@ -0,0 +1,19 @@
+package fetcher
+
+import "context"
+
+// PriceSource supplies USD prices by asset symbol (e.g. "ETH", "LINK"). Fetchers that
+// value holdings accept one so the price provider can be swapped in one place, separately
+// from the API that reports balances.
+type PriceSource interface {
+	// PriceUSD returns the USD price of one unit of symbol
+	PriceUSD(ctx context.Context, symbol string) (float64, error)
+}
+
+// PriceSourceFunc adapts an ordinary function to a PriceSource
+type PriceSourceFunc func(ctx context.Context, symbol string) (float64, error)
+
+// PriceUSD calls fn(ctx, symbol)
+func (fn PriceSourceFunc) PriceUSD(ctx context.Context, symbol string) (float64, error) {
+	return fn(ctx, symbol)
+}
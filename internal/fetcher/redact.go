@@ -0,0 +1,68 @@
+package fetcher
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"resty.dev/v3"
+)
+
+// secretParamPattern matches API key query parameters (and header-style key=value pairs)
+// so their values can be masked before they reach logs or error messages
+var secretParamPattern = regexp.MustCompile(`(?i)\b(apikey|api_key|x-api-key)=[^&\s"']*`)
+
+// RedactURL masks API key values in u, e.g. "...?apikey=abc123&symbol=AAPL" becomes
+// "...?apikey=***&symbol=AAPL". It operates on plain text, so it is also safe to apply
+// to error messages that embed a request URL.
+func RedactURL(u string) string {
+	return secretParamPattern.ReplaceAllString(u, "${1}=***")
+}
+
+// urlPathPattern matches the path of a URL, up to its query or fragment
+var urlPathPattern = regexp.MustCompile(`(?i)\b(https?://[^/\s"'?#]+)/[^?#\s"']+`)
+
+// redactLogURL masks API keys in u like RedactURL and also replaces every URL path with
+// "/***", for logs that may see endpoints carrying the key in the path (e.g. an Alchemy or
+// Infura RPC URL). Like RedactURL, it is safe to apply to error messages.
+func redactLogURL(u string) string {
+	return urlPathPattern.ReplaceAllString(RedactURL(u), "${1}/***")
+}
+
+// secretHeaderWords mark header names whose values are credentials, e.g. X-Api-Key,
+// X-Finnhub-Token, CB-ACCESS-KEY, or Authorization
+var secretHeaderWords = []string{"token", "key", "secret", "auth"}
+
+// isSecretHeader reports whether the header called name carries a credential
+func isSecretHeader(name string) bool {
+	lower := strings.ToLower(name)
+	for _, word := range secretHeaderWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeaders returns a copy of h with credential header values masked
+func redactHeaders(h http.Header) http.Header {
+	redacted := h.Clone()
+	for name := range redacted {
+		if isSecretHeader(name) {
+			redacted.Set(name, "***")
+		}
+	}
+	return redacted
+}
+
+// requestURL returns the redacted URL of the request behind r for logging, with its path
+// masked (see redactLogURL)
+func requestURL(r *resty.Response) string {
+	if r == nil || r.Request == nil {
+		return ""
+	}
+	if r.Request.RawRequest != nil && r.Request.RawRequest.URL != nil {
+		return redactLogURL(r.Request.RawRequest.URL.String())
+	}
+	return redactLogURL(r.Request.URL)
+}
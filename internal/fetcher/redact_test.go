@@ -0,0 +1,106 @@
+package fetcher
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "etherscan query",
+			in:   "https://api.etherscan.io/v2/api?action=balance&apikey=SECRET123&module=account",
+			want: "https://api.etherscan.io/v2/api?action=balance&apikey=***&module=account",
+		},
+		{
+			name: "key as last param",
+			in:   "https://www.alphavantage.co/query?function=GLOBAL_QUOTE&symbol=AAPL&apikey=SECRET123",
+			want: "https://www.alphavantage.co/query?function=GLOBAL_QUOTE&symbol=AAPL&apikey=***",
+		},
+		{
+			name: "case insensitive header style",
+			in:   "X-Api-Key=SECRET123",
+			want: "X-Api-Key=***",
+		},
+		{
+			name: "embedded in error message",
+			in:   `Get "http://127.0.0.1:1/?apikey=SECRET123&chainid=1": dial tcp: connection refused`,
+			want: `Get "http://127.0.0.1:1/?apikey=***&chainid=1": dial tcp: connection refused`,
+		},
+		{
+			name: "no secrets",
+			in:   "https://api.rentcast.io/v1/avm/value?address=123+Main+St",
+			want: "https://api.rentcast.io/v1/avm/value?address=123+Main+St",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactURL(tt.in); got != tt.want {
+				t.Errorf("RedactURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactLogURL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "key in path",
+			in:   "https://eth-mainnet.g.alchemy.com/v2/SECRET123",
+			want: "https://eth-mainnet.g.alchemy.com/***",
+		},
+		{
+			name: "path and query",
+			in:   "https://api.etherscan.io/v2/api?apikey=SECRET123&module=account",
+			want: "https://api.etherscan.io/***?apikey=***&module=account",
+		},
+		{
+			name: "embedded in error message",
+			in:   `Post "https://mainnet.infura.io/v3/SECRET123": dial tcp: connection refused`,
+			want: `Post "https://mainnet.infura.io/***": dial tcp: connection refused`,
+		},
+		{
+			name: "no path",
+			in:   "http://127.0.0.1:8545",
+			want: "http://127.0.0.1:8545",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactLogURL(tt.in); got != tt.want {
+				t.Errorf("redactLogURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	h := http.Header{}
+	for _, name := range []string{"X-Api-Key", "X-Finnhub-Token", "Cb-Access-Key", "Cb-Access-Secret", "Authorization", "Accept"} {
+		h.Set(name, "value-"+name)
+	}
+
+	redacted := redactHeaders(h)
+
+	for _, name := range []string{"X-Api-Key", "X-Finnhub-Token", "Cb-Access-Key", "Cb-Access-Secret", "Authorization"} {
+		if got := redacted.Get(name); got != "***" {
+			t.Errorf("%s = %q, want ***", name, got)
+		}
+	}
+	if got := redacted.Get("Accept"); got != "value-Accept" {
+		t.Errorf("Accept = %q, want it unchanged", got)
+	}
+	if h.Get("X-Api-Key") != "value-X-Api-Key" {
+		t.Error("redactHeaders() modified its input")
+	}
+}
@@ -1,5 +1,7 @@
 package fetcher
 
+import "time"
+
 // Result represents the outcome of a fetch operation.
 // It's designed to be sent through channels from worker goroutines
 // to a coordinator that processes and stores the results.
@@ -7,10 +9,38 @@ type Result struct {
 	// Key is the Redis-compatible hierarchical key for this data point
 	Key string
 
+	// Label is a human-readable name for output. Empty when the fetcher has no label.
+	Label string
+
 	// Value is the fetched financial data (price, balance, valuation, etc.)
 	Value float64
 
 	// Error contains any error that occurred during the fetch operation.
 	// If Error is not nil, Value should be considered invalid.
 	Error error
-}
\ No newline at end of file
+
+	// Stale is true when the fetch failed with a retryable error and Value is the
+	// last known value from a fallback store instead of fresh data
+	Stale bool
+
+	// Age is how old a stale Value is. It is zero for live results.
+	Age time.Duration
+
+	// PrevValue is the previously stored value, or nil when the fetcher doesn't track
+	// changes or had nothing to compare against
+	PrevValue *float64
+
+	// Delta is Value minus PrevValue
+	Delta float64
+
+	// DeltaPct is Delta as a percentage of PrevValue, or 0 when PrevValue is 0
+	DeltaPct float64
+}
+
+// DisplayName returns the label if set, otherwise the key
+func (r Result) DisplayName() string {
+	if r.Label != "" {
+		return r.Label
+	}
+	return r.Key
+}
@@ -0,0 +1,76 @@
+package fetcher
+
+import (
+	"context"
+	"sync"
+
+	"resty.dev/v3"
+)
+
+type retryBudgetKey struct{}
+
+// RetryBudget caps how many retries requests sharing a context may make per source (API
+// host) in total. During an outage, dozens of fetchers each retrying three times against
+// the same throttled API would amplify the load; once a source's budget is spent, further
+// failures are returned immediately instead of retried.
+type RetryBudget struct {
+	perSource int
+
+	mu   sync.Mutex
+	used map[string]int
+}
+
+// NewRetryBudget creates a budget allowing perSource retries for each API host
+func NewRetryBudget(perSource int) *RetryBudget {
+	return &RetryBudget{
+		perSource: perSource,
+		used:      make(map[string]int),
+	}
+}
+
+// WithRetryBudget returns a context under which every request made by a NewHTTPClient
+// client draws its retries from budget
+func WithRetryBudget(ctx context.Context, budget *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, budget)
+}
+
+// Used returns how many retries source has drawn from the budget
+func (b *RetryBudget) Used(source string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.used[source]
+}
+
+// take draws one retry for source, reporting false if its budget is exhausted
+func (b *RetryBudget) take(source string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.used[source] >= b.perSource {
+		return false
+	}
+	b.used[source]++
+	return true
+}
+
+// takeRetryBudget draws one retry from the budget in r's context, if any, keyed by the
+// request's host. It reports false when the budget for that host is exhausted.
+func takeRetryBudget(r *resty.Response) bool {
+	if r == nil || r.Request == nil {
+		return true
+	}
+	budget, ok := r.Request.Context().Value(retryBudgetKey{}).(*RetryBudget)
+	if !ok {
+		return true
+	}
+	return budget.take(requestHost(r))
+}
+
+// requestHost returns the host r was sent to
+func requestHost(r *resty.Response) string {
+	if r.Request.RawRequest != nil && r.Request.RawRequest.URL != nil {
+		return r.Request.RawRequest.URL.Host
+	}
+	return r.Request.URL
+}
@@ -0,0 +1,58 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRetryBudget_SharedAcrossClients(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	budget := NewRetryBudget(1)
+	ctx := WithRetryBudget(context.Background(), budget)
+
+	// Two fetchers against the same host share one retry between them
+	for range 2 {
+		client := NewHTTPClient(server.URL, WithRetryCount(3), WithRetryJitter(0))
+		resp, err := client.R().SetContext(ctx).Get("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.StatusCode() != http.StatusServiceUnavailable {
+			t.Errorf("status = %d, want 503", resp.StatusCode())
+		}
+	}
+
+	if got := requests.Load(); got != 3 {
+		t.Errorf("expected 3 requests (2 attempts plus 1 budgeted retry), got %d", got)
+	}
+	host := strings.TrimPrefix(server.URL, "http://")
+	if got := budget.Used(host); got != 1 {
+		t.Errorf("Used(%q) = %d, want 1", host, got)
+	}
+}
+
+func TestRetryBudget_PerSource(t *testing.T) {
+	budget := NewRetryBudget(2)
+
+	for i := range 2 {
+		if !budget.take("api.example.com") {
+			t.Fatalf("take %d: budget exhausted early", i+1)
+		}
+	}
+	if budget.take("api.example.com") {
+		t.Error("expected the budget for api.example.com to be exhausted")
+	}
+	if !budget.take("other.example.com") {
+		t.Error("expected other.example.com to have its own budget")
+	}
+}
@@ -0,0 +1,30 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+type runIDKey struct{}
+
+// NewRunID returns a random (version 4) UUID identifying one fetch cycle
+func NewRunID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// WithRunID returns a copy of ctx carrying the given run ID
+func WithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, runID)
+}
+
+// RunIDFromContext returns the run ID attached by WithRunID, or an empty string.
+// Fetchers include it in their log lines so logs from concurrent cycles can be separated.
+func RunIDFromContext(ctx context.Context) string {
+	runID, _ := ctx.Value(runIDKey{}).(string)
+	return runID
+}
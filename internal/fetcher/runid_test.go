@@ -0,0 +1,30 @@
+package fetcher
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+func TestNewRunID(t *testing.T) {
+	uuidV4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first, second := NewRunID(), NewRunID()
+	if !uuidV4.MatchString(first) {
+		t.Errorf("NewRunID() = %q, want a version 4 UUID", first)
+	}
+	if first == second {
+		t.Errorf("NewRunID() returned %q twice", first)
+	}
+}
+
+func TestRunIDFromContext(t *testing.T) {
+	if got := RunIDFromContext(context.Background()); got != "" {
+		t.Errorf("RunIDFromContext() = %q, want empty for a bare context", got)
+	}
+
+	ctx := WithRunID(context.Background(), "run-123")
+	if got := RunIDFromContext(ctx); got != "run-123" {
+		t.Errorf("RunIDFromContext() = %q, want %q", got, "run-123")
+	}
+}
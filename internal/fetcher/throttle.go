@@ -0,0 +1,82 @@
+package fetcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"financefetcher/internal/ratelimit"
+)
+
+// KeyThrottle enforces a minimum interval between fetches of the same key. Unlike the
+// per-API token buckets in ratelimit, it spaces out calls per resource, for APIs that
+// throttle per symbol or address rather than per account.
+type KeyThrottle struct {
+	minInterval time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// NewKeyThrottle creates a throttle allowing one fetch per key every minInterval
+func NewKeyThrottle(minInterval time.Duration) *KeyThrottle {
+	return &KeyThrottle{
+		minInterval: minInterval,
+		next:        make(map[string]time.Time),
+	}
+}
+
+// Wrap returns f throttled by t. Fetchers wrapped by the same throttle share its
+// per-key schedule, so two fetchers with the same Key are spaced out together.
+func (t *KeyThrottle) Wrap(f Fetcher) *ThrottledFetcher {
+	return &ThrottledFetcher{Fetcher: f, throttle: t}
+}
+
+// wait blocks until key may be fetched again. Each caller reserves the next free slot
+// up front, so concurrent callers for one key are spaced minInterval apart.
+func (t *KeyThrottle) wait(ctx context.Context, key string) *FetchError {
+	t.mu.Lock()
+	now := time.Now()
+	slot := now
+	if next, ok := t.next[key]; ok && next.After(now) {
+		slot = next
+	}
+	delay := slot.Sub(now)
+
+	// Like the rate limiter, give up early rather than wait past the deadline
+	if deadline, ok := ctx.Deadline(); ok && delay > 0 && deadline.Before(slot) {
+		t.mu.Unlock()
+		return ClassifyLimiterError(ratelimit.ErrExceedsDeadline)
+	}
+	t.next[key] = slot.Add(t.minInterval)
+	t.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ClassifyLimiterError(ctx.Err())
+	}
+}
+
+// ThrottledFetcher decorates a Fetcher so successive fetches of its key are at least
+// its throttle's minimum interval apart
+type ThrottledFetcher struct {
+	Fetcher
+	throttle *KeyThrottle
+}
+
+// Fetch waits for the key's next slot, then fetches from the wrapped fetcher
+func (f *ThrottledFetcher) Fetch(ctx context.Context) (float64, error) {
+	if err := f.throttle.wait(ctx, f.Key()); err != nil {
+		return 0, err.WithContext("throttled fetch of " + f.Key())
+	}
+	return f.Fetcher.Fetch(ctx)
+}
@@ -0,0 +1,65 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyThrottle_SpacesCallsPerKey(t *testing.T) {
+	throttle := NewKeyThrottle(50 * time.Millisecond)
+	aapl := throttle.Wrap(&valueFetcher{key: "fetcher:alphavantage:AAPL", value: 1})
+	msft := throttle.Wrap(&valueFetcher{key: "fetcher:alphavantage:MSFT", value: 2})
+	ctx := context.Background()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, f := range []*ThrottledFetcher{aapl, aapl, aapl, msft} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.Fetch(ctx); err != nil {
+				t.Errorf("Fetch() returned unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Three AAPL fetches need two full intervals; MSFT doesn't wait on AAPL
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("three fetches of one key took %v, want at least 100ms", elapsed)
+	}
+
+	start = time.Now()
+	if _, err := throttle.Wrap(&valueFetcher{key: "fetcher:alphavantage:GOOG"}).Fetch(ctx); err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("first fetch of a new key took %v, want no wait", elapsed)
+	}
+}
+
+func TestKeyThrottle_DeadlineTooClose(t *testing.T) {
+	throttle := NewKeyThrottle(time.Minute)
+	f := throttle.Wrap(&valueFetcher{key: "fetcher:alphavantage:AAPL", value: 1})
+
+	if _, err := f.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := f.Fetch(ctx)
+
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) || fetchErr.Type != ErrorTypeTimeout {
+		t.Fatalf("Fetch() error = %v, want timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Fetch() took %v, want it to fail without waiting", elapsed)
+	}
+}
@@ -0,0 +1,11 @@
+package fetcher
+
+import "context"
+
+// Verifier is implemented by fetchers that can check their credentials without a full fetch.
+// Verify returns nil if the API accepted the key, or a FetchError of type ErrorTypeClient
+// if the API reported an authentication problem. Other failures (network, rate limit)
+// are returned as-is since they say nothing about the key.
+type Verifier interface {
+	Verify(ctx context.Context) error
+}
@@ -0,0 +1,15 @@
+package finnhub
+
+import "financefetcher/internal/fetcher"
+
+func init() {
+	fetcher.Register("finnhub", newFromParams)
+}
+
+// newFromParams builds a StockFetcher from the "api_key", "symbol", and "base_url" parameters
+func newFromParams(params fetcher.Params) (fetcher.Fetcher, error) {
+	if err := params.Require("api_key", "symbol", "base_url"); err != nil {
+		return nil, err
+	}
+	return NewStockFetcher(params["api_key"], params["symbol"], params["base_url"]), nil
+}
@@ -0,0 +1,21 @@
+package finnhub
+
+import (
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestFactory(t *testing.T) {
+	f, err := fetcherpkg.New("finnhub", fetcherpkg.Params{
+		"api_key":  "test_key",
+		"symbol":   "AAPL",
+		"base_url": "http://localhost",
+	})
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	if got := f.Key(); got != "fetcher:finnhub:AAPL" {
+		t.Errorf("Key() = %q, want %q", got, "fetcher:finnhub:AAPL")
+	}
+}
@@ -0,0 +1,108 @@
+package finnhub
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+
+	"resty.dev/v3"
+)
+
+// QuoteResponse represents the Finnhub /quote response
+type QuoteResponse struct {
+	Current       float64 `json:"c"`
+	Change        float64 `json:"d"`
+	PercentChange float64 `json:"dp"`
+	High          float64 `json:"h"`
+	Low           float64 `json:"l"`
+	Open          float64 `json:"o"`
+	PreviousClose float64 `json:"pc"`
+	Timestamp     int64   `json:"t"`
+}
+
+// StockFetcher fetches stock prices from Finnhub
+type StockFetcher struct {
+	apiKey string
+	symbol string
+	client *resty.Client
+}
+
+// NewStockFetcher creates a new stock price fetcher
+func NewStockFetcher(apiKey, symbol, baseURL string) *StockFetcher {
+	client := fetcher.NewHTTPClient(baseURL, fetcher.WithRateLimitedRetries(ratelimit.APIFinnhub))
+	// Send the token as a header so it never appears in request URLs
+	client.SetHeader("X-Finnhub-Token", apiKey)
+
+	return &StockFetcher{
+		apiKey: apiKey,
+		symbol: symbol,
+		client: client,
+	}
+}
+
+// Fetch retrieves the current stock price
+func (f *StockFetcher) Fetch(ctx context.Context) (float64, error) {
+	// Apply rate limiting
+	limiter := ratelimit.GetLimiter()
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIFinnhub)
+	if err != nil {
+		return 0, fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIFinnhub, "symbol", f.symbol, "wait_duration", waited)
+
+	slog.Debug("fetching stock price from Finnhub", "run_id", fetcher.RunIDFromContext(ctx), "symbol", f.symbol)
+
+	var result QuoteResponse
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParam("symbol", f.symbol).
+		SetResult(&result).
+		Get("/quote")
+
+	if err != nil {
+		return 0, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch stock price for " + f.symbol)
+	}
+
+	if !resp.IsSuccess() {
+		return 0, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch stock price for " + f.symbol)
+	}
+
+	// Finnhub answers unknown symbols with an all-zero quote rather than an error
+	if result.Current == 0 {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("price not found in response for %s", f.symbol))
+	}
+
+	return result.Current, nil
+}
+
+// Verify checks the API key with a single quote request.
+// Finnhub rejects bad tokens with HTTP 401, which is classified as a client error.
+func (f *StockFetcher) Verify(ctx context.Context) error {
+	if err := ratelimit.GetLimiter().Wait(ctx, ratelimit.APIFinnhub); err != nil {
+		return fetcher.ClassifyLimiterError(err)
+	}
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParam("symbol", f.symbol).
+		Get("/quote")
+
+	if err != nil {
+		return fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to verify Finnhub API key")
+	}
+
+	if !resp.IsSuccess() {
+		return fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to verify Finnhub API key")
+	}
+
+	return nil
+}
+
+// Key returns the Redis key for this fetcher
+func (f *StockFetcher) Key() string {
+	return fmt.Sprintf("fetcher:finnhub:%s", f.symbol)
+}
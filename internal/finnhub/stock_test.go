@@ -0,0 +1,166 @@
+package finnhub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+)
+
+// Run this package's tests without real API rate limits
+func init() {
+	ratelimit.SetTestMode(true)
+}
+
+func TestStockFetcher_Key(t *testing.T) {
+	fetcher := NewStockFetcher("test_key", "AAPL", "http://localhost")
+
+	expectedKey := "fetcher:finnhub:AAPL"
+	if got := fetcher.Key(); got != expectedKey {
+		t.Errorf("Key() = %q, want %q", got, expectedKey)
+	}
+}
+
+func TestStockFetcher_Fetch_Success(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/quote" {
+			t.Errorf("path = %q, want /quote", r.URL.Path)
+		}
+		if r.URL.Query().Get("symbol") != "AAPL" {
+			t.Errorf("symbol = %q, want AAPL", r.URL.Query().Get("symbol"))
+		}
+		if r.Header.Get("X-Finnhub-Token") != "test_key" {
+			t.Errorf("X-Finnhub-Token = %q, want test_key", r.Header.Get("X-Finnhub-Token"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"c": 178.23, "d": 1.2, "dp": 0.68, "h": 179.1, "l": 176.5, "o": 177.0, "pc": 177.03, "t": 1714579200}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewStockFetcher("test_key", "AAPL", server.URL)
+	ctx := context.Background()
+
+	price, err := fetcher.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	expectedPrice := 178.23
+	if price != expectedPrice {
+		t.Errorf("Fetch() = %v, want %v", price, expectedPrice)
+	}
+}
+
+func TestStockFetcher_Fetch_UnknownSymbol(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"c": 0, "d": null, "dp": null, "h": 0, "l": 0, "o": 0, "pc": 0, "t": 0}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewStockFetcher("test_key", "NOTREAL", server.URL)
+	ctx := context.Background()
+
+	_, err := fetcher.Fetch(ctx)
+	if err == nil {
+		t.Fatal("Fetch() expected error for unknown symbol, got nil")
+	}
+
+	expectedErrMsg := "validation error: price not found in response for NOTREAL"
+	if err.Error() != expectedErrMsg {
+		t.Errorf("Fetch() error = %q, want %q", err.Error(), expectedErrMsg)
+	}
+}
+
+func TestStockFetcher_Fetch_Unauthorized(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewStockFetcher("bad_key", "AAPL", server.URL)
+	ctx := context.Background()
+
+	_, err := fetcher.Fetch(ctx)
+	if err == nil {
+		t.Fatal("Fetch() expected error, got nil")
+	}
+
+	var fetchErr *fetcherpkg.FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("Fetch() error = %v, want *FetchError", err)
+	}
+	if fetchErr.Type != fetcherpkg.ErrorTypeClient || fetchErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("error = %v, want client error with status 401", err)
+	}
+}
+
+func TestStockFetcher_Verify(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Finnhub-Token") != "good_key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"c": 178.23}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx := context.Background()
+
+	if err := NewStockFetcher("good_key", "AAPL", server.URL).Verify(ctx); err != nil {
+		t.Errorf("Verify() error = %v, want nil", err)
+	}
+
+	err := NewStockFetcher("bad_key", "AAPL", server.URL).Verify(ctx)
+	if got := fetcherpkg.ErrorTypeOf(err); got != fetcherpkg.ErrorTypeClient {
+		t.Errorf("Verify() error type = %q, want %q", got, fetcherpkg.ErrorTypeClient)
+	}
+}
+
+func TestStockFetcher_DebugLoggingRedactsToken(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"c": 178.23}`))
+	}))
+	defer server.Close()
+
+	fetcherpkg.SetDebugHTTP(true)
+	defer fetcherpkg.SetDebugHTTP(false)
+
+	fetcher := NewStockFetcher("finnhub-secret", "AAPL", server.URL)
+	if _, err := fetcher.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	out := logs.String()
+	if !strings.Contains(out, "http request") {
+		t.Fatalf("debug log missing request line: %s", out)
+	}
+	if strings.Contains(out, "finnhub-secret") {
+		t.Errorf("debug log leaked the Finnhub token: %s", out)
+	}
+}
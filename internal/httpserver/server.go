@@ -0,0 +1,106 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"financefetcher/internal/registry"
+)
+
+const (
+	readHeaderTimeout = 5 * time.Second
+)
+
+// ResultResponse is the JSON representation of a single registry entry
+type ResultResponse struct {
+	Value     float64   `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Server exposes the latest fetched values over HTTP
+type Server struct {
+	registry *registry.Registry
+	server   *http.Server
+}
+
+// New creates a server that serves the given registry on addr (e.g. ":8080")
+func New(addr string, reg *registry.Registry) *Server {
+	s := &Server{
+		registry: reg,
+	}
+
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	return s
+}
+
+// Handler returns the HTTP handler with all routes registered:
+//   - GET /results: latest value per key as JSON
+//   - GET /healthz: liveness probe
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /results", s.handleResults)
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	return mux
+}
+
+// Start listens on the server's address and begins serving in the background. A failure
+// to listen, such as the port already being in use, is returned; errors while serving
+// other than a graceful shutdown are logged.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("starting HTTP server", "addr", listener.Addr().String())
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("HTTP server failed", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown gracefully stops the server, waiting for in-flight requests until ctx expires
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// handleResults writes the latest registry snapshot as JSON
+func (s *Server) handleResults(w http.ResponseWriter, r *http.Request) {
+	snapshot := s.registry.Snapshot()
+
+	results := make(map[string]ResultResponse, len(snapshot))
+	for key, entry := range snapshot {
+		results[key] = ResultResponse{
+			Value:     entry.Value,
+			UpdatedAt: entry.UpdatedAt,
+		}
+	}
+
+	writeJSON(w, results)
+}
+
+// handleHealthz reports that the process is alive
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{"status": "ok"})
+}
+
+// writeJSON encodes v as a JSON response body
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to encode JSON response", "error", err)
+	}
+}
@@ -0,0 +1,122 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"financefetcher/internal/registry"
+)
+
+func TestServer_Results(t *testing.T) {
+	reg := registry.New()
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	reg.Set("fetcher:alphavantage:AAPL", 178.23, ts)
+	reg.Set("fetcher:rentcast:123_main_st", 250000.00, ts)
+
+	server := httptest.NewServer(New(":0", reg).Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/results")
+	if err != nil {
+		t.Fatalf("GET /results failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+
+	var results map[string]ResultResponse
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	aapl := results["fetcher:alphavantage:AAPL"]
+	if aapl.Value != 178.23 {
+		t.Errorf("AAPL value = %.2f, want 178.23", aapl.Value)
+	}
+	if !aapl.UpdatedAt.Equal(ts) {
+		t.Errorf("AAPL updated_at = %v, want %v", aapl.UpdatedAt, ts)
+	}
+}
+
+func TestServer_Healthz(t *testing.T) {
+	server := httptest.NewServer(New(":0", registry.New()).Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestServer_MethodNotAllowed(t *testing.T) {
+	server := httptest.NewServer(New(":0", registry.New()).Handler())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/results", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST /results failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestServer_Start_AddressInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer listener.Close()
+
+	if err := New(listener.Addr().String(), registry.New()).Start(); err == nil {
+		t.Error("Start() expected error for an address in use, got nil")
+	}
+}
+
+func TestServer_Start_Serves(t *testing.T) {
+	// Reserve a free port, then release it for the server
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	server := New(addr, registry.New())
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start() returned unexpected error: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	// The listener is open once Start returns, so no retry is needed
+	resp, err := http.Get("http://" + addr + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
@@ -0,0 +1,53 @@
+package portfolio
+
+import (
+	"financefetcher/internal/fetcher"
+)
+
+// Allocation describes how a portfolio's value is split across sources (asset classes)
+type Allocation struct {
+	// Total is the sum of all successful results
+	Total float64
+
+	// Totals is the summed value per source, e.g. "etherscan" → 12500.00
+	Totals map[string]float64
+
+	// Percent is each source's share of Total, in percent (0-100)
+	Percent map[string]float64
+}
+
+// ComputeAllocation groups successful results by the source segment of their key
+// (fetcher:{source}:{identifier}), sums each group, and reports each group's share
+// of the total. Failed results and exchange rates are ignored. Keys without a source are
+// grouped under "unknown".
+func ComputeAllocation(results []fetcher.Result) Allocation {
+	allocation := Allocation{
+		Totals:  make(map[string]float64),
+		Percent: make(map[string]float64),
+	}
+
+	for _, result := range results {
+		if result.Error != nil || fetcher.IsRateKey(result.Key) {
+			continue
+		}
+
+		source := fetcher.SourceFromKey(result.Key)
+		if source == "" {
+			source = "unknown"
+		}
+
+		allocation.Totals[source] += result.Value
+		allocation.Total += result.Value
+	}
+
+	// Percentages are undefined for an empty or zero-valued portfolio
+	if allocation.Total == 0 {
+		return allocation
+	}
+
+	for source, total := range allocation.Totals {
+		allocation.Percent[source] = total / allocation.Total * 100
+	}
+
+	return allocation
+}
@@ -0,0 +1,85 @@
+package portfolio
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"financefetcher/internal/fetcher"
+)
+
+func TestComputeAllocation(t *testing.T) {
+	results := []fetcher.Result{
+		{Key: "fetcher:etherscan:0xabc", Value: 20000},
+		{Key: "fetcher:alphavantage:AAPL", Value: 15000},
+		{Key: "fetcher:alphavantage:GOOGL", Value: 15000},
+		{Key: "fetcher:rentcast:123_main_st", Value: 150000},
+		{Key: "fetcher:alphavantage:MSFT", Error: errors.New("fetch failed")},
+	}
+
+	allocation := ComputeAllocation(results)
+
+	if allocation.Total != 200000 {
+		t.Errorf("Total = %.2f, want 200000.00", allocation.Total)
+	}
+
+	wantTotals := map[string]float64{
+		"etherscan":    20000,
+		"alphavantage": 30000,
+		"rentcast":     150000,
+	}
+	wantPercent := map[string]float64{
+		"etherscan":    10,
+		"alphavantage": 15,
+		"rentcast":     75,
+	}
+
+	if len(allocation.Totals) != len(wantTotals) {
+		t.Errorf("len(Totals) = %d, want %d", len(allocation.Totals), len(wantTotals))
+	}
+
+	for source, want := range wantTotals {
+		if got := allocation.Totals[source]; got != want {
+			t.Errorf("Totals[%q] = %.2f, want %.2f", source, got, want)
+		}
+	}
+
+	for source, want := range wantPercent {
+		if got := allocation.Percent[source]; math.Abs(got-want) > 1e-9 {
+			t.Errorf("Percent[%q] = %.4f, want %.4f", source, got, want)
+		}
+	}
+}
+
+func TestComputeAllocation_Empty(t *testing.T) {
+	allocation := ComputeAllocation(nil)
+
+	if allocation.Total != 0 {
+		t.Errorf("Total = %.2f, want 0", allocation.Total)
+	}
+	if len(allocation.Percent) != 0 {
+		t.Errorf("len(Percent) = %d, want 0", len(allocation.Percent))
+	}
+}
+
+func TestComputeAllocation_UnknownSource(t *testing.T) {
+	allocation := ComputeAllocation([]fetcher.Result{{Key: "custom-key", Value: 10}})
+
+	if got := allocation.Percent["unknown"]; got != 100 {
+		t.Errorf("Percent[%q] = %.2f, want 100", "unknown", got)
+	}
+}
+
+func TestComputeAllocation_IgnoresRates(t *testing.T) {
+	allocation := ComputeAllocation([]fetcher.Result{
+		{Key: "fetcher:alphavantage:AAPL", Value: 100},
+		{Key: "fetcher:alphavantage:fx:USD-EUR", Value: 0.92},
+	})
+
+	if allocation.Total != 100 {
+		t.Errorf("Total = %.2f, want 100 without the FX rate", allocation.Total)
+	}
+	if got := allocation.Percent["alphavantage"]; got != 100 {
+		t.Errorf("Percent[%q] = %.2f, want 100", "alphavantage", got)
+	}
+}
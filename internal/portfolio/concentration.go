@@ -0,0 +1,60 @@
+package portfolio
+
+import (
+	"log/slog"
+	"sort"
+
+	"financefetcher/internal/fetcher"
+)
+
+// Concentration is a single holding whose share of the portfolio exceeds a limit
+type Concentration struct {
+	Key     string
+	Value   float64
+	Percent float64 // share of the total, in percent (0-100)
+}
+
+// FindConcentrations returns every successful result whose value is more than maxPct percent
+// of the total of all successful results, largest share first. Failed results and exchange
+// rates are ignored, and nothing is reported for an empty or zero-valued portfolio.
+func FindConcentrations(results []fetcher.Result, maxPct float64) []Concentration {
+	total := 0.0
+	for _, result := range results {
+		if result.Error == nil && !fetcher.IsRateKey(result.Key) {
+			total += result.Value
+		}
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	var over []Concentration
+	for _, result := range results {
+		if result.Error != nil || fetcher.IsRateKey(result.Key) {
+			continue
+		}
+
+		percent := result.Value / total * 100
+		if percent > maxPct {
+			over = append(over, Concentration{Key: result.Key, Value: result.Value, Percent: percent})
+		}
+	}
+
+	sort.Slice(over, func(i, j int) bool { return over[i].Percent > over[j].Percent })
+	return over
+}
+
+// WarnConcentrations logs a warning for each holding that exceeds maxPct percent of the
+// portfolio, as a light rebalancing reminder, and returns the holdings it warned about
+func WarnConcentrations(results []fetcher.Result, maxPct float64) []Concentration {
+	over := FindConcentrations(results, maxPct)
+	for _, c := range over {
+		slog.Warn("holding exceeds maximum allocation",
+			"key", c.Key,
+			"value", c.Value,
+			"percent", c.Percent,
+			"max_percent", maxPct)
+	}
+	return over
+}
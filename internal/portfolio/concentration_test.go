@@ -0,0 +1,46 @@
+package portfolio
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"financefetcher/internal/fetcher"
+)
+
+func TestFindConcentrations(t *testing.T) {
+	results := []fetcher.Result{
+		{Key: "fetcher:etherscan:0xabc", Value: 20000},
+		{Key: "fetcher:alphavantage:AAPL", Value: 30000},
+		{Key: "fetcher:rentcast:123_main_st", Value: 150000},
+		{Key: "fetcher:alphavantage:MSFT", Error: errors.New("fetch failed")},
+	}
+
+	over := FindConcentrations(results, 10)
+
+	// 75% and 15% exceed the limit; 10% is exactly at it and is allowed
+	want := []Concentration{
+		{Key: "fetcher:rentcast:123_main_st", Value: 150000, Percent: 75},
+		{Key: "fetcher:alphavantage:AAPL", Value: 30000, Percent: 15},
+	}
+
+	if len(over) != len(want) {
+		t.Fatalf("FindConcentrations() returned %d holdings, want %d: %+v", len(over), len(want), over)
+	}
+	for i := range want {
+		if over[i].Key != want[i].Key || over[i].Value != want[i].Value || math.Abs(over[i].Percent-want[i].Percent) > 1e-9 {
+			t.Errorf("holding %d = %+v, want %+v", i, over[i], want[i])
+		}
+	}
+}
+
+func TestFindConcentrations_Empty(t *testing.T) {
+	if over := FindConcentrations(nil, 10); len(over) != 0 {
+		t.Errorf("FindConcentrations(nil) = %+v, want none", over)
+	}
+
+	zero := []fetcher.Result{{Key: "fetcher:etherscan:0xabc", Value: 0}}
+	if over := FindConcentrations(zero, 10); len(over) != 0 {
+		t.Errorf("FindConcentrations() for a zero portfolio = %+v, want none", over)
+	}
+}
@@ -0,0 +1,56 @@
+package portfolio
+
+import (
+	"log/slog"
+	"strings"
+
+	"financefetcher/internal/fetcher"
+)
+
+// fxKeyPrefix is the key prefix of AlphaVantage FX rate fetchers (fetcher:alphavantage:fx:{from}-{to})
+const fxKeyPrefix = "fetcher:alphavantage:fx:"
+
+// ConvertTotal expresses a USD total in each currency of rates, where each rate is how many
+// units of that currency one USD buys (e.g. "EUR" → 0.92). The result always includes "USD".
+// Non-positive rates are skipped with a warning rather than reporting a zero or negative total.
+func ConvertTotal(usdTotal float64, rates map[string]float64) map[string]float64 {
+	totals := map[string]float64{"USD": usdTotal}
+
+	for currency, rate := range rates {
+		if rate <= 0 {
+			slog.Warn("skipping currency with invalid exchange rate", "currency", currency, "rate", rate)
+			continue
+		}
+		totals[currency] = usdTotal * rate
+	}
+
+	return totals
+}
+
+// RatesFromResults collects USD-based exchange rates from the successful results of FX
+// fetchers run alongside the rest of the portfolio, which the coordinator reports but
+// leaves out of its total, keyed by target currency
+// (fetcher:alphavantage:fx:USD-EUR → "EUR"), for use with ConvertTotal. Pairs not quoted
+// from USD and all other results are ignored.
+func RatesFromResults(results []fetcher.Result) map[string]float64 {
+	rates := make(map[string]float64)
+
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+
+		pair, ok := strings.CutPrefix(result.Key, fxKeyPrefix)
+		if !ok {
+			continue
+		}
+
+		from, to, ok := strings.Cut(pair, "-")
+		if !ok || !strings.EqualFold(from, "USD") || to == "" {
+			continue
+		}
+		rates[strings.ToUpper(to)] = result.Value
+	}
+
+	return rates
+}
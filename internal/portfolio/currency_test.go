@@ -0,0 +1,41 @@
+package portfolio
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"testing"
+
+	"financefetcher/internal/fetcher"
+)
+
+func TestConvertTotal(t *testing.T) {
+	totals := ConvertTotal(1000, map[string]float64{"EUR": 0.92, "GBP": 0.79, "JPY": 0})
+
+	want := map[string]float64{"USD": 1000, "EUR": 920, "GBP": 790}
+	if len(totals) != len(want) {
+		t.Fatalf("ConvertTotal() = %v, want %v", totals, want)
+	}
+	for currency, value := range want {
+		if math.Abs(totals[currency]-value) > 1e-9 {
+			t.Errorf("totals[%s] = %v, want %v", currency, totals[currency], value)
+		}
+	}
+}
+
+func TestRatesFromResults(t *testing.T) {
+	results := []fetcher.Result{
+		{Key: "fetcher:alphavantage:fx:USD-EUR", Value: 0.92},
+		{Key: "fetcher:alphavantage:fx:USD-GBP", Value: 0.79},
+		{Key: "fetcher:alphavantage:fx:EUR-GBP", Value: 0.86},
+		{Key: "fetcher:alphavantage:fx:USD-JPY", Error: errors.New("fetch failed")},
+		{Key: "fetcher:alphavantage:AAPL", Value: 190},
+	}
+
+	rates := RatesFromResults(results)
+
+	want := map[string]float64{"EUR": 0.92, "GBP": 0.79}
+	if !maps.Equal(rates, want) {
+		t.Errorf("RatesFromResults() = %v, want %v", rates, want)
+	}
+}
@@ -2,8 +2,11 @@ package ratelimit
 
 import (
 	"context"
+	"errors"
 	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"golang.org/x/time/rate"
 )
@@ -18,8 +21,58 @@ const (
 	APIAlphaVantage API = "alphavantage"
 	// APIRentcast represents the Rentcast API
 	APIRentcast API = "rentcast"
+	// APIFinnhub represents the Finnhub API
+	APIFinnhub API = "finnhub"
+	// APIEthRPC represents an Ethereum JSON-RPC provider such as Alchemy or Infura
+	APIEthRPC API = "ethrpc"
+	// APICoinbase represents the Coinbase API
+	APICoinbase API = "coinbase"
 )
 
+// ErrWouldWait is returned by Wait in non-blocking mode when the limiter has no token available
+var ErrWouldWait = errors.New("rate limiter would block")
+
+// ErrExceedsDeadline is returned by Wait when the next token would only become available
+// after the context's deadline, so waiting would just end in cancellation
+var ErrExceedsDeadline = errors.New("rate limiter wait would exceed context deadline")
+
+// ErrExceedsBurst is returned by Wait when the limiter can never permit a request, because
+// its burst is zero with a finite rate, so waiting any amount of time wouldn't help
+var ErrExceedsBurst = errors.New("rate limiter burst is too small to permit a request")
+
+type nonBlockingKey struct{}
+
+// WithNonBlocking returns a context under which Wait never blocks: if a request can't
+// proceed immediately, Wait returns ErrWouldWait so the caller can skip or retry later
+func WithNonBlocking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nonBlockingKey{}, true)
+}
+
+// isNonBlocking reports whether ctx was created by WithNonBlocking
+func isNonBlocking(ctx context.Context) bool {
+	nonBlocking, _ := ctx.Value(nonBlockingKey{}).(bool)
+	return nonBlocking
+}
+
+type waitTrackerKey struct{}
+
+// WaitTracker accumulates how long Wait blocked under a context returned by TrackWaits,
+// which separates rate limiter delays from network latency in a fetch's total time
+type WaitTracker struct {
+	total atomic.Int64
+}
+
+// Total returns the time spent waiting so far
+func (t *WaitTracker) Total() time.Duration {
+	return time.Duration(t.total.Load())
+}
+
+// TrackWaits returns a context under which every Wait adds its blocked time to the returned tracker
+func TrackWaits(ctx context.Context) (context.Context, *WaitTracker) {
+	tracker := &WaitTracker{}
+	return context.WithValue(ctx, waitTrackerKey{}, tracker), tracker
+}
+
 // Limiter manages rate limits for different APIs
 type Limiter struct {
 	limiters map[API]*rate.Limiter
@@ -29,8 +82,20 @@ type Limiter struct {
 var (
 	instance *Limiter
 	once     sync.Once
+
+	// testMode makes limiters use unlimited rates; see SetTestMode
+	testMode atomic.Bool
 )
 
+// SetTestMode switches every API to unlimited rates (or back to production defaults) so
+// tests don't wait on real quotas. It resets the singleton limiter to the new defaults,
+// discarding any Configure/SetLimit/SetBurst changes. Test packages opt in explicitly,
+// typically from an init function in a _test.go file.
+func SetTestMode(enabled bool) {
+	testMode.Store(enabled)
+	GetLimiter().Reset()
+}
+
 // GetLimiter returns the singleton rate limiter instance
 func GetLimiter() *Limiter {
 	once.Do(func() {
@@ -42,15 +107,35 @@ func GetLimiter() *Limiter {
 	return instance
 }
 
+// ResetSingleton discards the singleton so the next GetLimiter call builds a fresh
+// one. Intended for tests; callers must ensure no other goroutine is using the limiter.
+func ResetSingleton() {
+	once = sync.Once{}
+	instance = nil
+}
+
+// Reset discards any Configure/SetLimit/SetBurst changes and refills every token
+// bucket by re-initializing the limiters with their defaults
+func (l *Limiter) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limiters = make(map[API]*rate.Limiter)
+	l.initLimiters()
+}
+
 // initLimiters initializes rate limiters for each API with conservative defaults
 func (l *Limiter) initLimiters() {
-	// In test mode, use unlimited rate limits to avoid slowing down tests
-	// Check for GO_TESTING environment variable or if we're running tests
-	if os.Getenv("GO_TESTING") == "1" || isTestMode() {
+	// In test mode, use unlimited rate limits to avoid slowing down tests.
+	// Tests opt in with SetTestMode; GO_TESTING=1 does the same for a whole process.
+	if os.Getenv("GO_TESTING") == "1" || testMode.Load() {
 		// Use rate.Inf for unlimited rate limiting in tests
 		l.limiters[APIEtherscan] = rate.NewLimiter(rate.Inf, 1)
 		l.limiters[APIAlphaVantage] = rate.NewLimiter(rate.Inf, 1)
 		l.limiters[APIRentcast] = rate.NewLimiter(rate.Inf, 1)
+		l.limiters[APIFinnhub] = rate.NewLimiter(rate.Inf, 1)
+		l.limiters[APIEthRPC] = rate.NewLimiter(rate.Inf, 1)
+		l.limiters[APICoinbase] = rate.NewLimiter(rate.Inf, 1)
 		return
 	}
 
@@ -64,22 +149,83 @@ func (l *Limiter) initLimiters() {
 
 	// Rentcast: 10 requests per second (conservative estimate)
 	l.limiters[APIRentcast] = rate.NewLimiter(rate.Limit(10), 1)
+
+	// Finnhub: 60 requests per minute on free tier = 1 request per second
+	l.limiters[APIFinnhub] = rate.NewLimiter(PerMinute(60), 1)
+
+	// Ethereum JSON-RPC: 10 requests per second (well within Alchemy and Infura free tiers)
+	l.limiters[APIEthRPC] = rate.NewLimiter(rate.Limit(10), 1)
+
+	// Coinbase: 10,000 requests per hour per key, so 2 per second stays well under it
+	l.limiters[APICoinbase] = rate.NewLimiter(rate.Limit(2), 1)
 }
 
-// isTestMode checks if we're running in test mode
-func isTestMode() bool {
-	// Check if the test binary is running by looking for test-related arguments
-	for _, arg := range os.Args {
-		if len(arg) > 6 && arg[:6] == "-test." {
-			return true
-		}
+// PerMinute converts a requests-per-minute quota into a rate.Limit (events per second)
+func PerMinute(requests float64) rate.Limit {
+	return rate.Limit(requests / 60.0)
+}
+
+// Configure replaces the limiter for the given API with one allowing requestsPerMinute
+// sustained requests. If burst is not positive, it defaults to one second's worth of
+// requests (minimum 1), so premium quotas aren't throttled to one request at a time.
+func (l *Limiter) Configure(api API, requestsPerMinute float64, burst int) {
+	if burst <= 0 {
+		burst = max(1, int(requestsPerMinute/60.0))
 	}
-	return false
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limiters[api] = rate.NewLimiter(PerMinute(requestsPerMinute), burst)
+}
+
+// SetLimit changes the sustained rate of the live limiter for the given API, keeping its
+// current tokens, e.g. when a process switches API tiers at runtime.
+// It reports false if no limiter exists for the API.
+func (l *Limiter) SetLimit(api API, limit rate.Limit) bool {
+	l.mu.RLock()
+	limiter, exists := l.limiters[api]
+	l.mu.RUnlock()
+
+	if !exists {
+		return false
+	}
+
+	limiter.SetLimit(limit)
+	return true
+}
+
+// SetBurst changes the burst size of the live limiter for the given API.
+// It reports false if no limiter exists for the API.
+func (l *Limiter) SetBurst(api API, burst int) bool {
+	l.mu.RLock()
+	limiter, exists := l.limiters[api]
+	l.mu.RUnlock()
+
+	if !exists {
+		return false
+	}
+
+	limiter.SetBurst(burst)
+	return true
 }
 
 // Wait blocks until the rate limiter permits an event for the given API
-// It returns an error if the context is canceled before the event can proceed
+// It returns ctx's error if the context is already done or is canceled before the event
+// can proceed. If ctx was created by WithNonBlocking, it returns ErrWouldWait instead of
+// blocking. If ctx has a deadline that falls before the next available token, it returns
+// ErrExceedsDeadline immediately rather than blocking until the deadline passes. If the
+// limiter's burst can never permit a request, it returns ErrExceedsBurst.
 func (l *Limiter) Wait(ctx context.Context, api API) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if tracker, ok := ctx.Value(waitTrackerKey{}).(*WaitTracker); ok {
+		start := time.Now()
+		defer func() { tracker.total.Add(int64(time.Since(start))) }()
+	}
+
 	l.mu.RLock()
 	limiter, exists := l.limiters[api]
 	l.mu.RUnlock()
@@ -89,7 +235,47 @@ func (l *Limiter) Wait(ctx context.Context, api API) error {
 		return nil
 	}
 
-	return limiter.Wait(ctx)
+	if isNonBlocking(ctx) {
+		if !limiter.Allow() {
+			return ErrWouldWait
+		}
+		return nil
+	}
+
+	// Reserve the token up front so the expected wait can be checked against the deadline
+	reservation := limiter.Reserve()
+	if !reservation.OK() {
+		return ErrExceedsBurst
+	}
+
+	delay := reservation.Delay()
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		reservation.Cancel()
+		return ErrExceedsDeadline
+	}
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the token back so the next caller isn't penalized for our cancellation
+		reservation.Cancel()
+		return ctx.Err()
+	}
+}
+
+// WaitTimed behaves like Wait but also reports how long the caller was blocked,
+// which helps separate rate-limit delays from network latency when diagnosing slow runs
+func (l *Limiter) WaitTimed(ctx context.Context, api API) (time.Duration, error) {
+	start := time.Now()
+	err := l.Wait(ctx, api)
+	return time.Since(start), err
 }
 
 // Allow reports whether an event for the given API may happen now
@@ -104,4 +290,4 @@ func (l *Limiter) Allow(api API) bool {
 	}
 
 	return limiter.Allow()
-}
\ No newline at end of file
+}
@@ -0,0 +1,248 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Run this package's tests without real API rate limits
+func init() {
+	SetTestMode(true)
+}
+
+func TestPerMinute(t *testing.T) {
+	tests := []struct {
+		perMinute float64
+		want      rate.Limit
+	}{
+		{5, rate.Limit(1.0 / 12.0)},
+		{60, rate.Limit(1)},
+		{75, rate.Limit(1.25)},
+		{1200, rate.Limit(20)},
+	}
+
+	for _, tt := range tests {
+		if got := PerMinute(tt.perMinute); got != tt.want {
+			t.Errorf("PerMinute(%v) = %v, want %v", tt.perMinute, got, tt.want)
+		}
+	}
+}
+
+func TestLimiter_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		perMinute float64
+		burst     int
+		wantBurst int
+	}{
+		{"free tier default burst", 5, 0, 1},
+		{"premium default burst", 1200, 0, 20},
+		{"explicit burst", 75, 10, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Limiter{limiters: make(map[API]*rate.Limiter)}
+			l.Configure(APIAlphaVantage, tt.perMinute, tt.burst)
+
+			limiter := l.limiters[APIAlphaVantage]
+			if got := limiter.Limit(); got != PerMinute(tt.perMinute) {
+				t.Errorf("Limit() = %v, want %v", got, PerMinute(tt.perMinute))
+			}
+			if got := limiter.Burst(); got != tt.wantBurst {
+				t.Errorf("Burst() = %d, want %d", got, tt.wantBurst)
+			}
+		})
+	}
+}
+
+func TestLimiter_WaitTimed(t *testing.T) {
+	l := &Limiter{limiters: make(map[API]*rate.Limiter)}
+	l.Configure(APIRentcast, 600, 1)
+	ctx := context.Background()
+
+	// The first call consumes the only burst token without waiting
+	if _, err := l.WaitTimed(ctx, APIRentcast); err != nil {
+		t.Fatalf("WaitTimed() returned unexpected error: %v", err)
+	}
+
+	// The second call must wait roughly 100ms for the next token at 10 req/s
+	waited, err := l.WaitTimed(ctx, APIRentcast)
+	if err != nil {
+		t.Fatalf("WaitTimed() returned unexpected error: %v", err)
+	}
+	if waited < 50*time.Millisecond {
+		t.Errorf("WaitTimed() waited %v, want at least 50ms", waited)
+	}
+}
+
+func TestLimiter_SetLimitAndBurst(t *testing.T) {
+	l := &Limiter{limiters: make(map[API]*rate.Limiter)}
+	l.Configure(APIAlphaVantage, 5, 1)
+
+	if !l.SetLimit(APIAlphaVantage, PerMinute(75)) {
+		t.Fatal("SetLimit() = false, want true for a configured API")
+	}
+	if !l.SetBurst(APIAlphaVantage, 5) {
+		t.Fatal("SetBurst() = false, want true for a configured API")
+	}
+
+	limiter := l.limiters[APIAlphaVantage]
+	if got := limiter.Limit(); got != PerMinute(75) {
+		t.Errorf("Limit() = %v, want %v", got, PerMinute(75))
+	}
+	if got := limiter.Burst(); got != 5 {
+		t.Errorf("Burst() = %d, want 5", got)
+	}
+
+	if l.SetLimit("unknown", rate.Inf) || l.SetBurst("unknown", 1) {
+		t.Error("SetLimit/SetBurst should report false for an unknown API")
+	}
+}
+
+func TestLimiter_Reset(t *testing.T) {
+	l := &Limiter{limiters: make(map[API]*rate.Limiter)}
+	l.initLimiters()
+	l.Configure(APIAlphaVantage, 600, 1)
+
+	l.Reset()
+
+	// Tests run with unlimited defaults, so Reset should restore rate.Inf
+	if got := l.limiters[APIAlphaVantage].Limit(); got != rate.Inf {
+		t.Errorf("Limit() after Reset = %v, want %v", got, rate.Inf)
+	}
+	if _, exists := l.limiters[APIEtherscan]; !exists {
+		t.Error("Reset() should re-create default limiters")
+	}
+}
+
+func TestResetSingleton(t *testing.T) {
+	first := GetLimiter()
+	ResetSingleton()
+	second := GetLimiter()
+
+	if first == second {
+		t.Error("GetLimiter() returned the same instance after ResetSingleton")
+	}
+}
+
+func TestLimiter_Wait_NonBlocking(t *testing.T) {
+	l := &Limiter{limiters: make(map[API]*rate.Limiter)}
+	l.Configure(APIAlphaVantage, 5, 1)
+	ctx := WithNonBlocking(context.Background())
+
+	// The first call consumes the only burst token
+	if err := l.Wait(ctx, APIAlphaVantage); err != nil {
+		t.Fatalf("Wait() returned unexpected error: %v", err)
+	}
+
+	// The next token is 12 seconds away, so a non-blocking Wait must fail fast
+	start := time.Now()
+	err := l.Wait(ctx, APIAlphaVantage)
+	if !errors.Is(err, ErrWouldWait) {
+		t.Fatalf("Wait() error = %v, want ErrWouldWait", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("non-blocking Wait() took %v, want immediate return", elapsed)
+	}
+}
+
+func TestLimiter_Wait_ExceedsDeadline(t *testing.T) {
+	l := &Limiter{limiters: make(map[API]*rate.Limiter)}
+	l.Configure(APIAlphaVantage, 5, 1)
+
+	// The first call consumes the only burst token
+	if err := l.Wait(context.Background(), APIAlphaVantage); err != nil {
+		t.Fatalf("Wait() returned unexpected error: %v", err)
+	}
+
+	// The next token is 12 seconds away, well past a 1 second deadline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := l.Wait(ctx, APIAlphaVantage)
+	if !errors.Is(err, ErrExceedsDeadline) {
+		t.Fatalf("Wait() error = %v, want ErrExceedsDeadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Wait() took %v, want immediate return", elapsed)
+	}
+}
+
+func TestLimiter_Wait_WithinDeadline(t *testing.T) {
+	l := &Limiter{limiters: make(map[API]*rate.Limiter)}
+	l.Configure(APIAlphaVantage, 1200, 1) // one token every 50ms
+
+	if err := l.Wait(context.Background(), APIAlphaVantage); err != nil {
+		t.Fatalf("Wait() returned unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := l.Wait(ctx, APIAlphaVantage); err != nil {
+		t.Errorf("Wait() returned unexpected error: %v", err)
+	}
+}
+
+func TestLimiter_Wait_ContextDone(t *testing.T) {
+	l := &Limiter{limiters: make(map[API]*rate.Limiter)}
+	l.Configure(APIAlphaVantage, 5, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A done context fails before taking a token, even one that is available now
+	if err := l.Wait(ctx, APIAlphaVantage); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() error = %v, want context.Canceled", err)
+	}
+	if !l.Allow(APIAlphaVantage) {
+		t.Error("Wait() with a canceled context consumed the burst token")
+	}
+}
+
+func TestLimiter_Wait_ExceedsBurst(t *testing.T) {
+	l := &Limiter{limiters: make(map[API]*rate.Limiter)}
+	l.Configure(APIAlphaVantage, 5, 1)
+	l.SetBurst(APIAlphaVantage, 0)
+
+	if err := l.Wait(context.Background(), APIAlphaVantage); !errors.Is(err, ErrExceedsBurst) {
+		t.Fatalf("Wait() error = %v, want ErrExceedsBurst", err)
+	}
+}
+
+func TestSetTestMode(t *testing.T) {
+	defer SetTestMode(true)
+
+	SetTestMode(false)
+	if got := GetLimiter().limiters[APIAlphaVantage].Limit(); got != rate.Limit(1.0/12.0) {
+		t.Errorf("Limit() outside test mode = %v, want %v", got, rate.Limit(1.0/12.0))
+	}
+
+	SetTestMode(true)
+	if got := GetLimiter().limiters[APIAlphaVantage].Limit(); got != rate.Inf {
+		t.Errorf("Limit() in test mode = %v, want %v", got, rate.Inf)
+	}
+}
+
+func TestTrackWaits(t *testing.T) {
+	l := &Limiter{limiters: make(map[API]*rate.Limiter)}
+	l.Configure(APIRentcast, 600, 1)
+	ctx, tracker := TrackWaits(context.Background())
+
+	// The first call is free; the second waits about 100ms for a token at 10 req/s
+	for range 2 {
+		if err := l.Wait(ctx, APIRentcast); err != nil {
+			t.Fatalf("Wait() returned unexpected error: %v", err)
+		}
+	}
+
+	if got := tracker.Total(); got < 50*time.Millisecond {
+		t.Errorf("Total() = %v, want at least 50ms", got)
+	}
+}
@@ -0,0 +1,59 @@
+package registry
+
+import (
+	"sync"
+	"time"
+)
+
+// Entry holds the latest value recorded for a key
+type Entry struct {
+	Value     float64
+	UpdatedAt time.Time
+}
+
+// Registry holds the most recent value per fetcher key.
+// It is safe for concurrent use, so a long-lived process can serve
+// the latest snapshot while fetchers keep updating it.
+type Registry struct {
+	entries map[string]Entry
+	mu      sync.RWMutex
+}
+
+// New creates an empty registry
+func New() *Registry {
+	return &Registry{
+		entries: make(map[string]Entry),
+	}
+}
+
+// Set records the value for key as of ts, replacing any previous entry
+func (r *Registry) Set(key string, value float64, ts time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[key] = Entry{
+		Value:     value,
+		UpdatedAt: ts,
+	}
+}
+
+// Get returns the latest entry for key and whether one exists
+func (r *Registry) Get(key string) (Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.entries[key]
+	return entry, ok
+}
+
+// Snapshot returns a copy of all entries, safe to use after the registry changes
+func (r *Registry) Snapshot() map[string]Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := make(map[string]Entry, len(r.entries))
+	for key, entry := range r.entries {
+		snapshot[key] = entry
+	}
+	return snapshot
+}
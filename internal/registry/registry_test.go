@@ -0,0 +1,78 @@
+package registry
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegistry_SetGet(t *testing.T) {
+	r := New()
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	if _, ok := r.Get("fetcher:alphavantage:AAPL"); ok {
+		t.Error("Get() on empty registry returned ok = true")
+	}
+
+	r.Set("fetcher:alphavantage:AAPL", 178.23, ts)
+
+	entry, ok := r.Get("fetcher:alphavantage:AAPL")
+	if !ok {
+		t.Fatal("Get() returned ok = false after Set()")
+	}
+	if entry.Value != 178.23 {
+		t.Errorf("Value = %.2f, want 178.23", entry.Value)
+	}
+	if !entry.UpdatedAt.Equal(ts) {
+		t.Errorf("UpdatedAt = %v, want %v", entry.UpdatedAt, ts)
+	}
+
+	// Later values replace earlier ones
+	r.Set("fetcher:alphavantage:AAPL", 180.00, ts.Add(time.Minute))
+	entry, _ = r.Get("fetcher:alphavantage:AAPL")
+	if entry.Value != 180.00 {
+		t.Errorf("Value = %.2f, want 180.00", entry.Value)
+	}
+}
+
+func TestRegistry_Snapshot(t *testing.T) {
+	r := New()
+	now := time.Now()
+	r.Set("a", 1, now)
+	r.Set("b", 2, now)
+
+	snapshot := r.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("len(Snapshot()) = %d, want 2", len(snapshot))
+	}
+
+	// Mutating the registry must not affect an existing snapshot
+	r.Set("c", 3, now)
+	if len(snapshot) != 2 {
+		t.Errorf("snapshot changed after Set(), len = %d, want 2", len(snapshot))
+	}
+}
+
+func TestRegistry_ConcurrentAccess(t *testing.T) {
+	r := New()
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			r.Set(fmt.Sprintf("key:%d", i%5), float64(i), time.Now())
+		}(i)
+		go func() {
+			defer wg.Done()
+			r.Snapshot()
+		}()
+	}
+
+	wg.Wait()
+
+	if got := len(r.Snapshot()); got != 5 {
+		t.Errorf("len(Snapshot()) = %d, want 5", got)
+	}
+}
@@ -0,0 +1,68 @@
+package rentcast
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+)
+
+// BatchPropertyFetcher fetches valuations for several properties in one run.
+// Properties that resolve to the same Key() are only queried once.
+type BatchPropertyFetcher struct {
+	fetchers []*PropertyFetcher
+}
+
+// NewBatchPropertyFetcher creates a batch fetcher for the given properties.
+// All properties share a single HTTP client, and duplicate addresses are dropped.
+func NewBatchPropertyFetcher(apiKey string, params []PropertyParams, baseURL string) *BatchPropertyFetcher {
+	client := fetcher.NewHTTPClient(baseURL, fetcher.WithRateLimitedRetries(ratelimit.APIRentcast))
+	client.SetHeader("X-Api-Key", apiKey)
+
+	seen := make(map[string]bool)
+	batch := &BatchPropertyFetcher{}
+
+	for _, p := range params {
+		f := &PropertyFetcher{
+			apiKey: apiKey,
+			params: p,
+			client: client,
+		}
+
+		if seen[f.Key()] {
+			slog.Debug("skipping duplicate property in batch", "address", p.Address, "key", f.Key())
+			continue
+		}
+		seen[f.Key()] = true
+
+		batch.fetchers = append(batch.fetchers, f)
+	}
+
+	return batch
+}
+
+// FetchAll retrieves each property valuation sequentially, respecting the rate limiter.
+// Returns a map of address to value for every successful fetch. If any fetch fails,
+// the successful values are still returned along with the joined errors.
+func (b *BatchPropertyFetcher) FetchAll(ctx context.Context) (map[string]float64, error) {
+	values := make(map[string]float64, len(b.fetchers))
+	var errs []error
+
+	for _, f := range b.fetchers {
+		value, err := f.Fetch(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values[f.params.Address] = value
+	}
+
+	return values, errors.Join(errs...)
+}
+
+// Fetchers returns the deduplicated property fetchers in this batch
+func (b *BatchPropertyFetcher) Fetchers() []*PropertyFetcher {
+	return b.fetchers
+}
@@ -0,0 +1,107 @@
+package rentcast
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNewBatchPropertyFetcher_Dedup(t *testing.T) {
+	params := []PropertyParams{
+		{Address: "123 Main St, Anytown, TX 12345"},
+		{Address: "123 MAIN ST, ANYTOWN, TX 12345"},
+		{Address: "456 Oak Ave, Anytown, TX 12345"},
+	}
+
+	batch := NewBatchPropertyFetcher("test_key", params, "http://localhost")
+
+	if got := len(batch.Fetchers()); got != 2 {
+		t.Errorf("len(Fetchers()) = %d, want 2", got)
+	}
+}
+
+func TestBatchPropertyFetcher_FetchAll(t *testing.T) {
+	var requests atomic.Int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("address") == "456 Oak Ave" {
+			w.Write([]byte(`{"price": 300000.00}`))
+			return
+		}
+		w.Write([]byte(`{"price": 250000.00}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	params := []PropertyParams{
+		{Address: "123 Main St"},
+		{Address: "123 Main St"},
+		{Address: "456 Oak Ave"},
+	}
+
+	batch := NewBatchPropertyFetcher("test_key", params, server.URL)
+
+	values, err := batch.FetchAll(context.Background())
+	if err != nil {
+		t.Fatalf("FetchAll() returned unexpected error: %v", err)
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server received %d requests, want 2", got)
+	}
+
+	want := map[string]float64{
+		"123 Main St": 250000.00,
+		"456 Oak Ave": 300000.00,
+	}
+	if len(values) != len(want) {
+		t.Fatalf("FetchAll() returned %d values, want %d", len(values), len(want))
+	}
+	for address, value := range want {
+		if values[address] != value {
+			t.Errorf("values[%q] = %.2f, want %.2f", address, values[address], value)
+		}
+	}
+}
+
+func TestBatchPropertyFetcher_FetchAll_PartialFailure(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("address") == "456 Oak Ave" {
+			w.Write([]byte(`{"price": 0}`))
+			return
+		}
+		w.Write([]byte(`{"price": 250000.00}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	params := []PropertyParams{
+		{Address: "123 Main St"},
+		{Address: "456 Oak Ave"},
+	}
+
+	batch := NewBatchPropertyFetcher("test_key", params, server.URL)
+
+	values, err := batch.FetchAll(context.Background())
+	if err == nil {
+		t.Error("FetchAll() expected error for failed property, got nil")
+	}
+
+	if values["123 Main St"] != 250000.00 {
+		t.Errorf("values[%q] = %.2f, want 250000.00", "123 Main St", values["123 Main St"])
+	}
+
+	if _, ok := values["456 Oak Ave"]; ok {
+		t.Error("failed property should not be present in values")
+	}
+}
@@ -0,0 +1,140 @@
+package rentcast
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+)
+
+// RentEstimateResponse represents the Rentcast API response for long-term rent estimates
+type RentEstimateResponse struct {
+	Rent            float64         `json:"rent"`
+	RentRangeLow    float64         `json:"rentRangeLow"`
+	RentRangeHigh   float64         `json:"rentRangeHigh"`
+	SubjectProperty SubjectProperty `json:"subjectProperty"`
+	Comparables     []Comparable    `json:"comparables"`
+}
+
+// CombinedPropertyFetcher fetches a property's value and its long-term rent estimate in
+// one coordinator entry. Fetch returns the value, like PropertyFetcher, and keeps the rent
+// for GetRent. Each half is a separate rate-limited request, and either can fail without
+// losing the other: a failed rent lookup is logged and Fetch still returns the value, while
+// a failed valuation is returned as the error but a successful rent is still kept. The rent
+// isn't requested when the run was canceled or the valuation failed with a client error
+// that retrying won't fix, such as an invalid key or an unknown address, since it would
+// fail the same way.
+type CombinedPropertyFetcher struct {
+	*PropertyFetcher
+
+	// rent and value hold the results of the latest Fetch; nil means that half failed
+	rent  atomic.Pointer[float64]
+	value atomic.Pointer[float64]
+}
+
+// NewCombinedPropertyFetcher creates a fetcher for a property's value and rent. Options
+// apply to the valuation and share its client with the rent request.
+func NewCombinedPropertyFetcher(apiKey string, params PropertyParams, baseURL string, opts ...Option) *CombinedPropertyFetcher {
+	return &CombinedPropertyFetcher{
+		PropertyFetcher: NewPropertyFetcher(apiKey, params, baseURL, opts...),
+	}
+}
+
+// Fetch retrieves the property valuation and rent estimate and returns the valuation
+func (f *CombinedPropertyFetcher) Fetch(ctx context.Context) (float64, error) {
+	value, valueErr := f.PropertyFetcher.Fetch(ctx)
+	if valueErr != nil {
+		f.value.Store(nil)
+	} else {
+		f.value.Store(&value)
+	}
+
+	if skipRent(ctx, valueErr) {
+		f.rent.Store(nil)
+		slog.Debug("skipping rent estimate after failed valuation", "run_id", fetcher.RunIDFromContext(ctx), "address", f.params.Address, "error", valueErr)
+		return value, valueErr
+	}
+
+	rent, rentErr := f.fetchRent(ctx)
+	if rentErr != nil {
+		f.rent.Store(nil)
+		slog.Warn("failed to fetch rent estimate", "run_id", fetcher.RunIDFromContext(ctx), "address", f.params.Address, "error", rentErr)
+	} else {
+		f.rent.Store(&rent)
+	}
+
+	return value, valueErr
+}
+
+// skipRent reports whether the rent lookup should be skipped after the valuation returned
+// valueErr: the context is done, or the valuation failed with a non-retryable client error
+func skipRent(ctx context.Context, valueErr error) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	return fetcher.ErrorTypeOf(valueErr) == fetcher.ErrorTypeClient && !fetcher.IsRetryable(valueErr)
+}
+
+// fetchRent retrieves the long-term rent estimate
+func (f *CombinedPropertyFetcher) fetchRent(ctx context.Context) (float64, error) {
+	// Apply rate limiting
+	limiter := ratelimit.GetLimiter()
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIRentcast)
+	if err != nil {
+		return 0, fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIRentcast, "address", f.params.Address, "wait_duration", waited)
+
+	slog.Debug("fetching rent estimate from Rentcast", "run_id", fetcher.RunIDFromContext(ctx), "address", f.params.Address)
+
+	var result RentEstimateResponse
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParams(f.queryParams()).
+		SetResult(&result).
+		Get("/avm/rent/long-term")
+
+	if err != nil {
+		return 0, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch rent estimate for " + f.params.Address)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return 0, addressNotFoundError(f.params.Address)
+	}
+
+	if !resp.IsSuccess() {
+		return 0, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch rent estimate for " + f.params.Address)
+	}
+
+	if result.Rent == 0 {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("rent not found in response for %s", f.params.Address))
+	}
+
+	return result.Rent, nil
+}
+
+// GetValue returns the valuation from the latest Fetch, and false if it failed or there
+// hasn't been a Fetch yet. It is safe to call concurrently with Fetch.
+func (f *CombinedPropertyFetcher) GetValue() (float64, bool) {
+	return loadFloat(&f.value)
+}
+
+// GetRent returns the monthly rent estimate from the latest Fetch, and false if it failed
+// or there hasn't been a Fetch yet. It is safe to call concurrently with Fetch.
+func (f *CombinedPropertyFetcher) GetRent() (float64, bool) {
+	return loadFloat(&f.rent)
+}
+
+// loadFloat returns the value p points to, and false if it is nil
+func loadFloat(p *atomic.Pointer[float64]) (float64, bool) {
+	v := p.Load()
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
@@ -0,0 +1,116 @@
+package rentcast
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+// newCombinedServer answers /avm/value and /avm/rent/long-term with the given status,
+// failing a path whose status isn't 200
+func newCombinedServer(t *testing.T, valueStatus, rentStatus int) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /avm/value", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("address") != "123 Main St" {
+			t.Errorf("address = %q, want 123 Main St", r.URL.Query().Get("address"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(valueStatus)
+		w.Write([]byte(`{"price": 350000.00}`))
+	})
+	mux.HandleFunc("GET /avm/rent/long-term", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("address") != "123 Main St" {
+			t.Errorf("address = %q, want 123 Main St", r.URL.Query().Get("address"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(rentStatus)
+		w.Write([]byte(`{"rent": 2100.00, "rentRangeLow": 1900.00, "rentRangeHigh": 2300.00}`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestCombinedPropertyFetcher_Fetch(t *testing.T) {
+	tests := []struct {
+		name        string
+		valueStatus int
+		rentStatus  int
+		wantErr     bool
+		wantValue   bool
+		wantRent    bool
+	}{
+		{name: "both succeed", valueStatus: http.StatusOK, rentStatus: http.StatusOK, wantValue: true, wantRent: true},
+		{name: "rent fails", valueStatus: http.StatusOK, rentStatus: http.StatusBadRequest, wantValue: true},
+		{name: "value fails", valueStatus: http.StatusInternalServerError, rentStatus: http.StatusOK, wantErr: true, wantRent: true},
+		{name: "both fail", valueStatus: http.StatusInternalServerError, rentStatus: http.StatusBadRequest, wantErr: true},
+		{name: "value client error skips rent", valueStatus: http.StatusBadRequest, rentStatus: http.StatusOK, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCombinedServer(t, tt.valueStatus, tt.rentStatus)
+			defer server.Close()
+
+			fetcher := NewCombinedPropertyFetcher("test_key", PropertyParams{Address: "123 Main St"}, server.URL,
+				WithClientOptions(fetcherpkg.WithoutRetries()))
+
+			value, err := fetcher.Fetch(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Fetch() returned nil error, want the valuation's error")
+				}
+			} else if err != nil {
+				t.Fatalf("Fetch() returned unexpected error: %v", err)
+			} else if value != 350000.00 {
+				t.Errorf("Fetch() = %.2f, want 350000.00", value)
+			}
+
+			gotValue, ok := fetcher.GetValue()
+			if ok != tt.wantValue || (ok && gotValue != 350000.00) {
+				t.Errorf("GetValue() = %.2f, %v, want 350000.00 only if the valuation succeeded (%v)", gotValue, ok, tt.wantValue)
+			}
+
+			rent, ok := fetcher.GetRent()
+			if ok != tt.wantRent || (ok && rent != 2100.00) {
+				t.Errorf("GetRent() = %.2f, %v, want 2100.00 only if the rent lookup succeeded (%v)", rent, ok, tt.wantRent)
+			}
+		})
+	}
+}
+
+func TestCombinedPropertyFetcher_Fetch_Canceled(t *testing.T) {
+	server := newCombinedServer(t, http.StatusOK, http.StatusOK)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fetcher := NewCombinedPropertyFetcher("test_key", PropertyParams{Address: "123 Main St"}, server.URL)
+	if _, err := fetcher.Fetch(ctx); fetcherpkg.ErrorTypeOf(err) != fetcherpkg.ErrorTypeCanceled {
+		t.Fatalf("Fetch() error = %v, want canceled error", err)
+	}
+	if _, ok := fetcher.GetRent(); ok {
+		t.Error("GetRent() reported a rent after a canceled Fetch()")
+	}
+}
+
+func TestCombinedPropertyFetcher_BeforeFetch(t *testing.T) {
+	fetcher := NewCombinedPropertyFetcher("test_key", PropertyParams{Address: "123 Main St"}, "http://localhost")
+
+	if _, ok := fetcher.GetValue(); ok {
+		t.Error("GetValue() reported a value before Fetch()")
+	}
+	if _, ok := fetcher.GetRent(); ok {
+		t.Error("GetRent() reported a rent before Fetch()")
+	}
+
+	// The combined fetcher stands in for the property's value entry, so it shares its key
+	if got, want := fetcher.Key(), NewPropertyFetcher("test_key", PropertyParams{Address: "123 Main St"}, "http://localhost").Key(); got != want {
+		t.Errorf("Key() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+package rentcast
+
+import "financefetcher/internal/fetcher"
+
+func init() {
+	fetcher.Register("rentcast", newFromParams)
+}
+
+// newFromParams builds a PropertyFetcher from the "api_key", "address", and "base_url"
+// parameters, plus the optional "property_type", "bedrooms", "bathrooms", and "square_footage"
+func newFromParams(params fetcher.Params) (fetcher.Fetcher, error) {
+	if err := params.Require("api_key", "address", "base_url"); err != nil {
+		return nil, err
+	}
+
+	bedrooms, err := params.Int("bedrooms")
+	if err != nil {
+		return nil, err
+	}
+	bathrooms, err := params.Float("bathrooms")
+	if err != nil {
+		return nil, err
+	}
+	squareFootage, err := params.Int("square_footage")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPropertyFetcher(params["api_key"], PropertyParams{
+		Address:       params["address"],
+		PropertyType:  params["property_type"],
+		Bedrooms:      bedrooms,
+		Bathrooms:     bathrooms,
+		SquareFootage: squareFootage,
+	}, params["base_url"]), nil
+}
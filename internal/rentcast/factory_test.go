@@ -0,0 +1,38 @@
+package rentcast
+
+import (
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestFactory(t *testing.T) {
+	f, err := fetcherpkg.New("rentcast", fetcherpkg.Params{
+		"api_key":        "test_key",
+		"address":        "123 Main St",
+		"base_url":       "http://localhost",
+		"bedrooms":       "3",
+		"bathrooms":      "2.5",
+		"square_footage": "1800",
+	})
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+
+	fetcher, ok := f.(*PropertyFetcher)
+	if !ok {
+		t.Fatalf("New() returned %T, want *PropertyFetcher", f)
+	}
+	if fetcher.params.Bedrooms != 3 || fetcher.params.Bathrooms != 2.5 || fetcher.params.SquareFootage != 1800 {
+		t.Errorf("params = %+v, want 3 bed / 2.5 bath / 1800 sqft", fetcher.params)
+	}
+
+	if _, err := fetcherpkg.New("rentcast", fetcherpkg.Params{
+		"api_key":  "test_key",
+		"address":  "123 Main St",
+		"base_url": "http://localhost",
+		"bedrooms": "three",
+	}); err == nil {
+		t.Error("New() expected error for non-numeric bedrooms, got nil")
+	}
+}
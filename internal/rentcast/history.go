@@ -0,0 +1,143 @@
+package rentcast
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+)
+
+// HistoryEvent is one entry in a property record's history, such as a sale
+type HistoryEvent struct {
+	Event string  `json:"event"`
+	Date  string  `json:"date"`
+	Price float64 `json:"price"`
+}
+
+// PropertyRecord represents a property record from the Rentcast /properties endpoint.
+// Only the fields needed for value history are decoded.
+type PropertyRecord struct {
+	ID               string                  `json:"id"`
+	FormattedAddress string                  `json:"formattedAddress"`
+	History          map[string]HistoryEvent `json:"history"`
+}
+
+// HistoryPoint is a property's value at the start of a month
+type HistoryPoint struct {
+	Date  time.Time
+	Value float64
+}
+
+// FetchHistory returns a monthly value series covering the last months months, oldest first.
+// Rentcast has no per-address valuation history, so each month carries forward the most
+// recent recorded sale price; months before the first known sale are omitted. It returns
+// a validation error if Rentcast has no sale history for the address.
+func (f *PropertyFetcher) FetchHistory(ctx context.Context, months int) ([]HistoryPoint, error) {
+	if months <= 0 {
+		return nil, fetcher.NewValidationError(fmt.Sprintf("months must be positive, got %d", months))
+	}
+
+	// Apply rate limiting
+	limiter := ratelimit.GetLimiter()
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIRentcast)
+	if err != nil {
+		return nil, fetcher.ClassifyLimiterError(err)
+	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIRentcast, "address", f.params.Address, "wait_duration", waited)
+
+	slog.Debug("fetching property history from Rentcast", "run_id", fetcher.RunIDFromContext(ctx), "address", f.params.Address)
+
+	var records []PropertyRecord
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParam("address", f.params.Address).
+		SetResult(&records).
+		Get("/properties")
+
+	if err != nil {
+		return nil, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch property history for " + f.params.Address)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, addressNotFoundError(f.params.Address)
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch property history for " + f.params.Address)
+	}
+
+	if len(records) == 0 {
+		return nil, fetcher.NewValidationError(fmt.Sprintf("property not found for %s", f.params.Address))
+	}
+
+	series := monthlySeries(records[0].History, months, time.Now())
+	if len(series) == 0 {
+		return nil, fetcher.NewValidationError(fmt.Sprintf("no value history available for %s", f.params.Address))
+	}
+
+	return series, nil
+}
+
+// monthlySeries builds a value for the start of each of the last months months up to now,
+// carrying forward the latest sale price recorded on or before each month
+func monthlySeries(history map[string]HistoryEvent, months int, now time.Time) []HistoryPoint {
+	type sale struct {
+		date  time.Time
+		price float64
+	}
+
+	var sales []sale
+	for key, event := range history {
+		if event.Event != "Sale" || event.Price <= 0 {
+			continue
+		}
+		date, err := parseEventDate(event.Date, key)
+		if err != nil {
+			slog.Debug("skipping history event with unparseable date", "date", event.Date, "key", key)
+			continue
+		}
+		sales = append(sales, sale{date: date, price: event.Price})
+	}
+	sort.Slice(sales, func(i, j int) bool { return sales[i].date.Before(sales[j].date) })
+
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var series []HistoryPoint
+	for i := months - 1; i >= 0; i-- {
+		monthStart := thisMonth.AddDate(0, -i, 0)
+
+		// The value at the start of a month is the last sale before it began,
+		// except for the current month, which also reflects sales so far
+		cutoff := monthStart
+		if i == 0 {
+			cutoff = now
+		}
+
+		value := 0.0
+		for _, s := range sales {
+			if s.date.After(cutoff) {
+				break
+			}
+			value = s.price
+		}
+		if value > 0 {
+			series = append(series, HistoryPoint{Date: monthStart, Value: value})
+		}
+	}
+
+	return series
+}
+
+// parseEventDate parses an event's timestamp, falling back to its history map key (YYYY-MM-DD)
+func parseEventDate(date, key string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, key)
+}
@@ -0,0 +1,89 @@
+package rentcast
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestMonthlySeries(t *testing.T) {
+	now := time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)
+	history := map[string]HistoryEvent{
+		"2024-02-10": {Event: "Sale", Date: "2024-02-10T00:00:00.000Z", Price: 300000},
+		"2024-05-20": {Event: "Sale", Date: "2024-05-20T00:00:00.000Z", Price: 350000},
+		"2024-04-01": {Event: "Listing", Date: "2024-04-01T00:00:00.000Z", Price: 999999},
+	}
+
+	got := monthlySeries(history, 6, now)
+
+	// January predates the first sale, so the series starts in March
+	want := []HistoryPoint{
+		{Date: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Value: 300000},
+		{Date: time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC), Value: 300000},
+		{Date: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC), Value: 300000},
+		{Date: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), Value: 350000},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("monthlySeries() returned %d points, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !got[i].Date.Equal(want[i].Date) || got[i].Value != want[i].Value {
+			t.Errorf("point %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPropertyFetcher_FetchHistory(t *testing.T) {
+	saleDate := time.Now().AddDate(0, -2, 0).Format(time.DateOnly)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/properties" || r.URL.Query().Get("address") != "123 Main St" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id": "1", "history": {"` + saleDate + `": {"event": "Sale", "price": 250000}}}]`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewPropertyFetcher("test_key", PropertyParams{Address: "123 Main St"}, server.URL)
+
+	series, err := fetcher.FetchHistory(context.Background(), 12)
+	if err != nil {
+		t.Fatalf("FetchHistory() returned unexpected error: %v", err)
+	}
+	if len(series) < 2 || len(series) > 3 {
+		t.Fatalf("FetchHistory() returned %d points, want the months since the sale", len(series))
+	}
+	for _, point := range series {
+		if point.Value != 250000 {
+			t.Errorf("point %v value = %v, want 250000", point.Date, point.Value)
+		}
+	}
+}
+
+func TestPropertyFetcher_FetchHistory_NoHistory(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id": "1", "history": {}}]`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewPropertyFetcher("test_key", PropertyParams{Address: "123 Main St"}, server.URL)
+
+	_, err := fetcher.FetchHistory(context.Background(), 12)
+	if got := fetcherpkg.ErrorTypeOf(err); got != fetcherpkg.ErrorTypeValidation {
+		t.Errorf("FetchHistory() error = %v, want validation error", err)
+	}
+}
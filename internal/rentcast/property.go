@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"strings"
+	"sync/atomic"
+	"unicode"
 
 	"financefetcher/internal/fetcher"
 	"financefetcher/internal/ratelimit"
@@ -38,34 +41,34 @@ type SubjectProperty struct {
 
 // Comparable represents a comparable property
 type Comparable struct {
-	ID               string   `json:"id"`
-	FormattedAddress string   `json:"formattedAddress"`
-	AddressLine1     string   `json:"addressLine1"`
-	AddressLine2     *string  `json:"addressLine2"`
-	City             string   `json:"city"`
-	State            string   `json:"state"`
-	StateFips        string   `json:"stateFips"`
-	ZipCode          string   `json:"zipCode"`
-	County           string   `json:"county"`
-	CountyFips       string   `json:"countyFips"`
-	Latitude         float64  `json:"latitude"`
-	Longitude        float64  `json:"longitude"`
-	PropertyType     string   `json:"propertyType"`
-	Bedrooms         int      `json:"bedrooms"`
-	Bathrooms        float64  `json:"bathrooms"`
-	SquareFootage    int      `json:"squareFootage"`
-	LotSize          int      `json:"lotSize"`
-	YearBuilt        int      `json:"yearBuilt"`
-	Status           string   `json:"status"`
-	Price            float64  `json:"price"`
-	ListingType      string   `json:"listingType"`
-	ListedDate       string   `json:"listedDate"`
-	RemovedDate      *string  `json:"removedDate"`
-	LastSeenDate     string   `json:"lastSeenDate"`
-	DaysOnMarket     int      `json:"daysOnMarket"`
-	Distance         float64  `json:"distance"`
-	DaysOld          int      `json:"daysOld"`
-	Correlation      float64  `json:"correlation"`
+	ID               string  `json:"id"`
+	FormattedAddress string  `json:"formattedAddress"`
+	AddressLine1     string  `json:"addressLine1"`
+	AddressLine2     *string `json:"addressLine2"`
+	City             string  `json:"city"`
+	State            string  `json:"state"`
+	StateFips        string  `json:"stateFips"`
+	ZipCode          string  `json:"zipCode"`
+	County           string  `json:"county"`
+	CountyFips       string  `json:"countyFips"`
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	PropertyType     string  `json:"propertyType"`
+	Bedrooms         int     `json:"bedrooms"`
+	Bathrooms        float64 `json:"bathrooms"`
+	SquareFootage    int     `json:"squareFootage"`
+	LotSize          int     `json:"lotSize"`
+	YearBuilt        int     `json:"yearBuilt"`
+	Status           string  `json:"status"`
+	Price            float64 `json:"price"`
+	ListingType      string  `json:"listingType"`
+	ListedDate       string  `json:"listedDate"`
+	RemovedDate      *string `json:"removedDate"`
+	LastSeenDate     string  `json:"lastSeenDate"`
+	DaysOnMarket     int     `json:"daysOnMarket"`
+	Distance         float64 `json:"distance"`
+	DaysOld          int     `json:"daysOld"`
+	Correlation      float64 `json:"correlation"`
 }
 
 // PropertyValueResponse represents the Rentcast API response for property valuations
@@ -75,6 +78,10 @@ type PropertyValueResponse struct {
 	PriceRangeHigh  float64         `json:"priceRangeHigh"`
 	SubjectProperty SubjectProperty `json:"subjectProperty"`
 	Comparables     []Comparable    `json:"comparables"`
+
+	// Estimated is true when Price was missing and the value was taken from the midpoint
+	// of the price range (see WithRangeMidpoint). It is not part of the API response.
+	Estimated bool `json:"-"`
 }
 
 // PropertyParams holds the parameters needed for a property valuation request
@@ -88,55 +95,99 @@ type PropertyParams struct {
 
 // PropertyFetcher fetches property valuations from Rentcast
 type PropertyFetcher struct {
-	apiKey         string
-	params         PropertyParams
-	client         *resty.Client
-	lastResponse   *PropertyValueResponse
+	apiKey string
+	params PropertyParams
+	client *resty.Client
+
+	// lastResponse is atomic so GetLastResponse can be called while a Fetch is in flight
+	lastResponse atomic.Pointer[PropertyValueResponse]
+
+	// rangeMidpoint falls back to the price range midpoint when the price is missing
+	rangeMidpoint bool
+}
+
+// Option configures optional behavior of a PropertyFetcher
+type Option func(*PropertyFetcher)
+
+// WithHeader adds an extra header to every request, e.g. an org id required by an API gateway
+func WithHeader(key, value string) Option {
+	return func(f *PropertyFetcher) {
+		f.client.SetHeader(key, value)
+	}
+}
+
+// WithRangeMidpoint falls back to the midpoint of priceRangeLow and priceRangeHigh when a
+// response has no price, instead of failing. The fallback is logged and the response is
+// marked Estimated.
+func WithRangeMidpoint() Option {
+	return func(f *PropertyFetcher) {
+		f.rangeMidpoint = true
+	}
+}
+
+// WithClientOptions applies HTTP client options, e.g. fetcher.WithoutRetries()
+func WithClientOptions(opts ...fetcher.ClientOption) Option {
+	return func(f *PropertyFetcher) {
+		for _, opt := range opts {
+			opt(f.client)
+		}
+	}
 }
 
 // NewPropertyFetcher creates a new property valuation fetcher
-func NewPropertyFetcher(apiKey string, params PropertyParams, baseURL string) *PropertyFetcher {
-	client := fetcher.NewHTTPClient(baseURL)
+func NewPropertyFetcher(apiKey string, params PropertyParams, baseURL string, opts ...Option) *PropertyFetcher {
+	client := fetcher.NewHTTPClient(baseURL, fetcher.WithRateLimitedRetries(ratelimit.APIRentcast))
 	client.SetHeader("X-Api-Key", apiKey)
 
-	return &PropertyFetcher{
+	f := &PropertyFetcher{
 		apiKey: apiKey,
 		params: params,
 		client: client,
 	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
 }
 
 // Fetch retrieves the property valuation
 func (f *PropertyFetcher) Fetch(ctx context.Context) (float64, error) {
 	// Apply rate limiting
 	limiter := ratelimit.GetLimiter()
-	if err := limiter.Wait(ctx, ratelimit.APIRentcast); err != nil {
-		return 0, fetcher.NewTimeoutError(err)
+	waited, err := limiter.WaitTimed(ctx, ratelimit.APIRentcast)
+	if err != nil {
+		return 0, fetcher.ClassifyLimiterError(err)
 	}
+	slog.Debug("rate limiter wait complete", "run_id", fetcher.RunIDFromContext(ctx), "source", ratelimit.APIRentcast, "address", f.params.Address, "wait_duration", waited)
 
-	slog.Debug("fetching property valuation from Rentcast", "address", f.params.Address)
+	slog.Debug("fetching property valuation from Rentcast", "run_id", fetcher.RunIDFromContext(ctx), "address", f.params.Address)
 
 	var result PropertyValueResponse
 
 	resp, err := f.client.R().
 		SetContext(ctx).
-		SetQueryParams(map[string]string{
-			"address":       f.params.Address,
-			"propertyType":  f.params.PropertyType,
-			"bedrooms":      fmt.Sprintf("%d", f.params.Bedrooms),
-			"bathrooms":     fmt.Sprintf("%.1f", f.params.Bathrooms),
-			"squareFootage": fmt.Sprintf("%d", f.params.SquareFootage),
-		}).
+		SetQueryParams(f.queryParams()).
 		SetResult(&result).
 		Get("/avm/value")
 
 	if err != nil {
-		return 0, fetcher.NewNetworkError(err)
+		return 0, fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to fetch property valuation for " + f.params.Address)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return 0, addressNotFoundError(f.params.Address)
 	}
 
 	if !resp.IsSuccess() {
-		fetchErr := fetcher.ClassifyHTTPError(resp.StatusCode())
-		return 0, fmt.Errorf("failed to fetch property valuation for %s: %w", f.params.Address, fetchErr)
+		return 0, fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to fetch property valuation for " + f.params.Address)
+	}
+
+	if result.Price == 0 && f.rangeMidpoint && result.PriceRangeLow > 0 && result.PriceRangeHigh > 0 {
+		result.Price = (result.PriceRangeLow + result.PriceRangeHigh) / 2
+		result.Estimated = true
+		slog.Warn("Rentcast returned no price, using price range midpoint", "run_id", fetcher.RunIDFromContext(ctx), "address", f.params.Address, "low", result.PriceRangeLow, "high", result.PriceRangeHigh, "estimate", result.Price)
 	}
 
 	if result.Price == 0 {
@@ -144,20 +195,80 @@ func (f *PropertyFetcher) Fetch(ctx context.Context) (float64, error) {
 	}
 
 	// Store the full response for later access
-	f.lastResponse = &result
+	f.lastResponse.Store(&result)
 
 	return result.Price, nil
 }
 
-// GetLastResponse returns the last full API response
+// queryParams returns the property description sent with every AVM request
+func (f *PropertyFetcher) queryParams() map[string]string {
+	return map[string]string{
+		"address":       f.params.Address,
+		"propertyType":  f.params.PropertyType,
+		"bedrooms":      fmt.Sprintf("%d", f.params.Bedrooms),
+		"bathrooms":     fmt.Sprintf("%.1f", f.params.Bathrooms),
+		"squareFootage": fmt.Sprintf("%d", f.params.SquareFootage),
+	}
+}
+
+// GetLastResponse returns the last full API response, or nil before the first successful
+// Fetch. It is safe to call concurrently with Fetch.
 func (f *PropertyFetcher) GetLastResponse() *PropertyValueResponse {
-	return f.lastResponse
+	return f.lastResponse.Load()
+}
+
+// EstimateFromComparables returns a correlation-weighted average of the last response's
+// comparable prices, using only comps within maxDistance miles whose correlation is at
+// least minCorrelation. Comps without a price are ignored. It returns an error before the
+// first successful Fetch or when no comps pass the filter.
+func (f *PropertyFetcher) EstimateFromComparables(maxDistance float64, minCorrelation float64) (float64, error) {
+	resp := f.lastResponse.Load()
+	if resp == nil {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("no response to estimate from for %s; call Fetch first", f.params.Address))
+	}
+
+	var weighted, totalWeight float64
+	for _, comp := range resp.Comparables {
+		if comp.Price <= 0 || comp.Distance > maxDistance || comp.Correlation < minCorrelation {
+			continue
+		}
+		weighted += comp.Price * comp.Correlation
+		totalWeight += comp.Correlation
+	}
+
+	if totalWeight <= 0 {
+		return 0, fetcher.NewValidationError(fmt.Sprintf("no comparables for %s within %.2f miles with correlation >= %.2f",
+			f.params.Address, maxDistance, minCorrelation))
+	}
+
+	return weighted / totalWeight, nil
+}
+
+// addressNotFoundError reports that Rentcast has no property for address. Retrying
+// won't help, so the message points the user at the address instead.
+func addressNotFoundError(address string) *fetcher.FetchError {
+	return fetcher.NewClientError(http.StatusNotFound,
+		fmt.Sprintf("address not found: Rentcast has no property for %q; check the address in your config", address))
 }
 
 // Key returns the Redis key for this fetcher
-// Creates a stub from the address by replacing spaces with underscores and lowercasing
+// Creates a stub from the address (see addressStub), so formatting differences in the
+// configured address don't produce separate keys for the same property
 func (f *PropertyFetcher) Key() string {
-	addressStub := strings.ToLower(strings.ReplaceAll(f.params.Address, " ", "_"))
-	addressStub = strings.ReplaceAll(addressStub, ",", "")
-	return fmt.Sprintf("fetcher:rentcast:%s", addressStub)
-}
\ No newline at end of file
+	return fmt.Sprintf("fetcher:rentcast:%s", addressStub(f.params.Address))
+}
+
+// addressStub normalizes an address for use in a key: it lowercases and joins the words
+// with single underscores. Any rune that isn't a letter or digit separates words, so a run
+// of whitespace (including tabs and non-breaking spaces) or punctuation collapses to one
+// underscore: "123 Main St." and "123 Main St" share a stub, and "123-125 Main St" becomes
+// "123_125_main_st", still distinct from "123125 Main St". It is idempotent: the stub of
+// a stub is the stub itself.
+func addressStub(address string) string {
+	// Underscores separate words too, so normalizing a stub again leaves it unchanged
+	words := strings.FieldsFunc(strings.ToLower(address), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(words, "_")
+}
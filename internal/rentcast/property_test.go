@@ -2,11 +2,23 @@ package rentcast
 
 import (
 	"context"
+	"errors"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
 )
 
+// Run this package's tests without real API rate limits
+func init() {
+	ratelimit.SetTestMode(true)
+}
+
 func TestNewPropertyFetcher(t *testing.T) {
 	apiKey := "test_api_key"
 	params := PropertyParams{
@@ -58,6 +70,41 @@ func TestPropertyFetcher_Key(t *testing.T) {
 			address:     "456 BROADWAY AVE, NEW YORK, NY 10001",
 			expectedKey: "fetcher:rentcast:456_broadway_ave_new_york_ny_10001",
 		},
+		{
+			name:        "leading, trailing, and repeated whitespace",
+			address:     "  123  Main St \t Anytown ",
+			expectedKey: "fetcher:rentcast:123_main_st_anytown",
+		},
+		{
+			name:        "non-breaking space",
+			address:     "123\u00a0Main St",
+			expectedKey: "fetcher:rentcast:123_main_st",
+		},
+		{
+			name:        "punctuation",
+			address:     "123 Main St., Apt #4, Anytown",
+			expectedKey: "fetcher:rentcast:123_main_st_apt_4_anytown",
+		},
+		{
+			name:        "standalone punctuation",
+			address:     "123 Main St - Unit 4",
+			expectedKey: "fetcher:rentcast:123_main_st_unit_4",
+		},
+		{
+			name:        "hyphenated house number",
+			address:     "123-125 Main St",
+			expectedKey: "fetcher:rentcast:123_125_main_st",
+		},
+		{
+			name:        "comma without a space",
+			address:     "123 Main St,Anytown",
+			expectedKey: "fetcher:rentcast:123_main_st_anytown",
+		},
+		{
+			name:        "unicode letters",
+			address:     "12 Rue Général, Montréal",
+			expectedKey: "fetcher:rentcast:12_rue_général_montréal",
+		},
 	}
 
 	for _, tt := range tests {
@@ -138,11 +185,11 @@ func TestPropertyFetcher_Fetch_Success(t *testing.T) {
 	}
 
 	// Verify last response is stored
-	if fetcher.lastResponse == nil {
+	if fetcher.GetLastResponse() == nil {
 		t.Error("lastResponse is nil")
 	} else {
-		if fetcher.lastResponse.Price != expected {
-			t.Errorf("lastResponse.Price = %.2f, want %.2f", fetcher.lastResponse.Price, expected)
+		if fetcher.GetLastResponse().Price != expected {
+			t.Errorf("lastResponse.Price = %.2f, want %.2f", fetcher.GetLastResponse().Price, expected)
 		}
 	}
 }
@@ -232,8 +279,8 @@ func TestPropertyFetcher_Fetch_WithComparables(t *testing.T) {
 	}
 
 	// Verify comparables are stored
-	if len(fetcher.lastResponse.Comparables) != 1 {
-		t.Errorf("len(comparables) = %d, want 1", len(fetcher.lastResponse.Comparables))
+	if len(fetcher.GetLastResponse().Comparables) != 1 {
+		t.Errorf("len(comparables) = %d, want 1", len(fetcher.GetLastResponse().Comparables))
 	}
 }
 
@@ -302,6 +349,35 @@ func TestPropertyFetcher_Fetch_MissingPrice(t *testing.T) {
 	}
 }
 
+func TestPropertyFetcher_Fetch_RangeMidpoint(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"price": null,
+			"priceRangeLow": 200000.00,
+			"priceRangeHigh": 300000.00
+		}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	params := PropertyParams{Address: "123 Main St"}
+	fetcher := NewPropertyFetcher("test_key", params, server.URL, WithRangeMidpoint())
+
+	value, err := fetcher.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+	if value != 250000 {
+		t.Errorf("Fetch() = %.2f, want 250000.00", value)
+	}
+	if resp := fetcher.GetLastResponse(); resp == nil || !resp.Estimated {
+		t.Error("GetLastResponse().Estimated = false, want true for a midpoint value")
+	}
+}
+
 func TestPropertyFetcher_Fetch_ContextCancellation(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Server will be slow to respond
@@ -415,4 +491,233 @@ func TestPropertyFetcher_GetLastResponse(t *testing.T) {
 	if lastResp.PriceRangeHigh != 320000.00 {
 		t.Errorf("GetLastResponse().PriceRangeHigh = %.2f, want 320000.00", lastResp.PriceRangeHigh)
 	}
-}
\ No newline at end of file
+}
+
+// TestPropertyFetcher_ConcurrentFetchAndRead is meant to be run with -race: concurrent
+// Fetch calls on one instance must not race with each other or with GetLastResponse.
+func TestPropertyFetcher_ConcurrentFetchAndRead(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"price": 300000.00}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewPropertyFetcher("test_key", PropertyParams{Address: "123 Main St"}, server.URL)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := fetcher.Fetch(context.Background()); err != nil {
+				t.Errorf("Fetch() returned unexpected error: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if resp := fetcher.GetLastResponse(); resp != nil && resp.Price != 300000.00 {
+				t.Errorf("GetLastResponse().Price = %.2f, want 300000.00", resp.Price)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if resp := fetcher.GetLastResponse(); resp == nil || resp.Price != 300000.00 {
+		t.Errorf("GetLastResponse() = %+v after concurrent fetches, want price 300000.00", resp)
+	}
+}
+
+func TestPropertyFetcher_Fetch_WithHeader(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Org-Id"); got != "org_123" {
+			t.Errorf("X-Org-Id header = %q, want %q", got, "org_123")
+		}
+		if got := r.Header.Get("X-Api-Key"); got != "test_key" {
+			t.Errorf("X-Api-Key header = %q, want %q", got, "test_key")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"price": 250000.00}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	params := PropertyParams{Address: "123 Main St"}
+	fetcher := NewPropertyFetcher("test_key", params, server.URL, WithHeader("X-Org-Id", "org_123"))
+	ctx := context.Background()
+
+	if _, err := fetcher.Fetch(ctx); err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+}
+
+func TestPropertyFetcher_Fetch_HTTPErrorMessage(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	params := PropertyParams{Address: "123 Main St"}
+	fetcher := NewPropertyFetcher("test_key", params, server.URL)
+	ctx := context.Background()
+
+	_, err := fetcher.Fetch(ctx)
+	if err == nil {
+		t.Fatal("Fetch() expected error, got nil")
+	}
+
+	if _, ok := err.(*fetcherpkg.FetchError); !ok {
+		t.Fatalf("Fetch() error type = %T, want *FetchError", err)
+	}
+
+	expectedErrMsg := "client error (status 400): failed to fetch property valuation for 123 Main St: client error: HTTP 400"
+	if err.Error() != expectedErrMsg {
+		t.Errorf("Fetch() error = %q, want %q", err.Error(), expectedErrMsg)
+	}
+}
+
+func TestPropertyFetcher_Fetch_ReportsAttempts(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	params := PropertyParams{Address: "123 Main St"}
+	fetcher := NewPropertyFetcher("test_key", params, server.URL,
+		WithClientOptions(fetcherpkg.WithRetryCount(1)))
+
+	_, err := fetcher.Fetch(context.Background())
+
+	var fetchErr *fetcherpkg.FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("Fetch() error = %v, want *FetchError", err)
+	}
+	if fetchErr.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2 (one retry)", fetchErr.Attempts)
+	}
+	if !fetchErr.Exhausted {
+		t.Error("Exhausted = false, want true after using the only retry")
+	}
+	if !strings.Contains(err.Error(), "after 1 retry") {
+		t.Errorf("Fetch() error = %q, want retry count in message", err.Error())
+	}
+}
+
+func TestAddressStub_PunctuationDoesNotCollide(t *testing.T) {
+	if a, b := addressStub("123-125 Main St"), addressStub("123125 Main St"); a == b {
+		t.Errorf("addressStub() = %q for both addresses, want distinct stubs", a)
+	}
+}
+
+func TestAddressStub_Idempotent(t *testing.T) {
+	for _, address := range []string{"123  Main St ", "123 Main St", " 123 main st, ", "123 Main St."} {
+		stub := addressStub(address)
+		if stub != "123_main_st" {
+			t.Errorf("addressStub(%q) = %q, want %q", address, stub, "123_main_st")
+		}
+		if again := addressStub(stub); again != stub {
+			t.Errorf("addressStub(%q) = %q, want it unchanged", stub, again)
+		}
+	}
+}
+
+func TestPropertyFetcher_Fetch_AddressNotFound(t *testing.T) {
+	var requests int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	params := PropertyParams{Address: "123 Mian St"}
+	fetcher := NewPropertyFetcher("test_key", params, server.URL)
+
+	_, err := fetcher.Fetch(context.Background())
+	if err == nil {
+		t.Fatal("Fetch() expected error, got nil")
+	}
+
+	var fetchErr *fetcherpkg.FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("Fetch() error type = %T, want *FetchError", err)
+	}
+	if fetchErr.Type != fetcherpkg.ErrorTypeClient || fetchErr.Retryable {
+		t.Errorf("Fetch() error = %s (retryable %v), want non-retryable client error", fetchErr.Type, fetchErr.Retryable)
+	}
+	if !strings.Contains(fetchErr.Message, `address not found: Rentcast has no property for "123 Mian St"`) {
+		t.Errorf("Fetch() error message = %q, want it to name the missing address", fetchErr.Message)
+	}
+	if requests != 1 {
+		t.Errorf("server received %d requests, want 1 (404 must not be retried)", requests)
+	}
+}
+
+func TestPropertyFetcher_EstimateFromComparables(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"price": 350000.00,
+			"comparables": [
+				{"id": "near-strong", "price": 300000.00, "distance": 0.2, "correlation": 0.9},
+				{"id": "near-weak", "price": 500000.00, "distance": 0.3, "correlation": 0.5},
+				{"id": "far-strong", "price": 900000.00, "distance": 4.0, "correlation": 0.95},
+				{"id": "near-medium", "price": 400000.00, "distance": 0.5, "correlation": 0.6}
+			]
+		}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := NewPropertyFetcher("test_key", PropertyParams{Address: "123 Main St"}, server.URL)
+
+	if _, err := fetcher.EstimateFromComparables(1, 0.5); fetcherpkg.ErrorTypeOf(err) != fetcherpkg.ErrorTypeValidation {
+		t.Errorf("EstimateFromComparables() before Fetch() error = %v, want validation error", err)
+	}
+
+	if _, err := fetcher.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch() returned unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		maxDistance    float64
+		minCorrelation float64
+		want           float64
+		wantErr        bool
+	}{
+		// (300000*0.9 + 400000*0.6) / 1.5
+		{name: "filters distance and correlation", maxDistance: 1, minCorrelation: 0.6, want: 340000},
+		// (300000*0.9 + 500000*0.5 + 400000*0.6) / 2.0
+		{name: "thresholds are inclusive", maxDistance: 0.5, minCorrelation: 0.5, want: 380000},
+		{name: "no comps pass", maxDistance: 0.1, minCorrelation: 0.5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fetcher.EstimateFromComparables(tt.maxDistance, tt.minCorrelation)
+			if tt.wantErr {
+				if fetcherpkg.ErrorTypeOf(err) != fetcherpkg.ErrorTypeValidation {
+					t.Errorf("EstimateFromComparables() error = %v, want validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EstimateFromComparables() returned unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("EstimateFromComparables() = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package rentcast
+
+import (
+	"context"
+
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/ratelimit"
+)
+
+// Verify checks the API key with a single-record property search.
+// Rentcast rejects bad keys with HTTP 401, which is classified as a client error.
+func (f *PropertyFetcher) Verify(ctx context.Context) error {
+	if err := ratelimit.GetLimiter().Wait(ctx, ratelimit.APIRentcast); err != nil {
+		return fetcher.ClassifyLimiterError(err)
+	}
+
+	resp, err := f.client.R().
+		SetContext(ctx).
+		SetQueryParam("limit", "1").
+		Get("/properties")
+
+	if err != nil {
+		return fetcher.ClassifyRequestError(err).WithAttempts(resp).WithContext("failed to verify Rentcast API key")
+	}
+
+	if !resp.IsSuccess() {
+		return fetcher.ClassifyHTTPError(resp.StatusCode()).WithAttempts(resp).WithContext("failed to verify Rentcast API key")
+	}
+
+	return nil
+}
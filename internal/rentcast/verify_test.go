@@ -0,0 +1,51 @@
+package rentcast
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fetcherpkg "financefetcher/internal/fetcher"
+)
+
+func TestPropertyFetcher_Verify(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		wantType fetcherpkg.ErrorType
+	}{
+		{"valid key", http.StatusOK, ""},
+		{"invalid key", http.StatusUnauthorized, fetcherpkg.ErrorTypeClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/properties" {
+					t.Errorf("path = %q, want /properties", r.URL.Path)
+				}
+				if r.Header.Get("X-Api-Key") != "test_key" {
+					t.Errorf("X-Api-Key = %q, want test_key", r.Header.Get("X-Api-Key"))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+
+			fetcher := NewPropertyFetcher("test_key", PropertyParams{Address: "123 Main St"}, server.URL)
+			err := fetcher.Verify(context.Background())
+
+			if tt.wantType == "" {
+				if err != nil {
+					t.Errorf("Verify() error = %v, want nil", err)
+				}
+				return
+			}
+			if got := fetcherpkg.ErrorTypeOf(err); got != tt.wantType {
+				t.Errorf("Verify() error type = %q, want %q (err: %v)", got, tt.wantType, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"financefetcher/internal/coordinator"
+	"financefetcher/internal/fetcher"
+)
+
+// Report is the JSON document written for a single fetch cycle
+type Report struct {
+	// RunTimestamp is when the cycle began
+	RunTimestamp time.Time `json:"run_timestamp"`
+
+	// DurationMS is how long the cycle took in milliseconds
+	DurationMS int64 `json:"duration_ms"`
+
+	// Total is the sum of all successfully fetched values (see coordinator.RunSummary)
+	Total float64 `json:"total"`
+
+	SuccessCount int `json:"success_count"`
+	FailureCount int `json:"failure_count"`
+	StaleCount   int `json:"stale_count"`
+
+	// Results holds every result in the order it arrived
+	Results []ResultEntry `json:"results"`
+
+	// Errors holds only the failed results, for quick inspection
+	Errors []ErrorEntry `json:"errors"`
+}
+
+// ResultEntry is the JSON representation of a single fetcher result
+type ResultEntry struct {
+	Key   string  `json:"key"`
+	Label string  `json:"label,omitempty"`
+	Value float64 `json:"value"`
+	Stale bool    `json:"stale,omitempty"`
+	Error string  `json:"error,omitempty"`
+}
+
+// ErrorEntry describes a failed fetch
+type ErrorEntry struct {
+	Key     string            `json:"key"`
+	Type    fetcher.ErrorType `json:"type"`
+	Message string            `json:"message"`
+}
+
+// New builds a Report from a run summary
+func New(summary coordinator.RunSummary) Report {
+	r := Report{
+		RunTimestamp: summary.StartedAt.UTC(),
+		DurationMS:   summary.Duration.Milliseconds(),
+		Total:        summary.Total,
+		SuccessCount: summary.SuccessCount,
+		FailureCount: summary.FailureCount,
+		StaleCount:   summary.StaleCount,
+		Results:      make([]ResultEntry, 0, len(summary.Results)),
+		Errors:       []ErrorEntry{},
+	}
+
+	for _, result := range summary.Results {
+		entry := ResultEntry{
+			Key:   result.Key,
+			Label: result.Label,
+			Value: result.Value,
+			Stale: result.Stale,
+		}
+		if result.Error != nil {
+			entry.Value = 0
+			entry.Error = result.Error.Error()
+			r.Errors = append(r.Errors, ErrorEntry{
+				Key:     result.Key,
+				Type:    fetcher.ErrorTypeOf(result.Error),
+				Message: result.Error.Error(),
+			})
+		}
+		r.Results = append(r.Results, entry)
+	}
+
+	return r
+}
+
+// WriteReport writes summary to path as an indented JSON document. The file is written
+// to a temporary file in the same directory and renamed into place, so an interrupted
+// run never leaves a truncated report behind.
+func WriteReport(path string, summary coordinator.RunSummary) error {
+	data, err := json.MarshalIndent(New(summary), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	data = append(data, '\n')
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".report-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return nil
+}
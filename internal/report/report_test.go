@@ -0,0 +1,112 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"financefetcher/internal/coordinator"
+	"financefetcher/internal/fetcher"
+)
+
+func TestWriteReport(t *testing.T) {
+	started := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	summary := coordinator.RunSummary{
+		Total:        1500,
+		SuccessCount: 2,
+		FailureCount: 2,
+		StaleCount:   1,
+		StartedAt:    started,
+		Duration:     1250 * time.Millisecond,
+		Results: []fetcher.Result{
+			{Key: "fetcher:alphavantage:AAPL", Label: "Apple", Value: 1000},
+			{Key: "fetcher:etherscan:0xabc", Value: 500, Stale: true},
+			{Key: "fetcher:rentcast:123_main_st", Error: fetcher.NewRateLimitError(429)},
+			{Key: "fetcher:finnhub:MSFT", Error: errors.New("boom")},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := WriteReport(path, summary); err != nil {
+		t.Fatalf("WriteReport() returned unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+
+	var got Report
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+
+	if !got.RunTimestamp.Equal(started) {
+		t.Errorf("RunTimestamp = %v, want %v", got.RunTimestamp, started)
+	}
+	if got.DurationMS != 1250 {
+		t.Errorf("DurationMS = %d, want 1250", got.DurationMS)
+	}
+	if got.Total != 1500 || got.SuccessCount != 2 || got.FailureCount != 2 || got.StaleCount != 1 {
+		t.Errorf("totals = %+v, want total 1500, 2 successes, 2 failures, 1 stale", got)
+	}
+
+	if len(got.Results) != 4 {
+		t.Fatalf("len(Results) = %d, want 4", len(got.Results))
+	}
+	if got.Results[0].Label != "Apple" || got.Results[0].Value != 1000 {
+		t.Errorf("Results[0] = %+v, want Apple at 1000", got.Results[0])
+	}
+	if !got.Results[1].Stale {
+		t.Errorf("Results[1].Stale = false, want true")
+	}
+	if got.Results[2].Error == "" {
+		t.Errorf("Results[2].Error is empty, want the fetch error")
+	}
+
+	if len(got.Errors) != 2 {
+		t.Fatalf("len(Errors) = %d, want 2", len(got.Errors))
+	}
+	if got.Errors[0].Type != fetcher.ErrorTypeRateLimit {
+		t.Errorf("Errors[0].Type = %q, want %q", got.Errors[0].Type, fetcher.ErrorTypeRateLimit)
+	}
+	if got.Errors[1].Type != fetcher.ErrorTypeUnknown {
+		t.Errorf("Errors[1].Type = %q, want %q", got.Errors[1].Type, fetcher.ErrorTypeUnknown)
+	}
+}
+
+func TestWriteReport_NoErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	summary := coordinator.RunSummary{
+		SuccessCount: 1,
+		Results:      []fetcher.Result{{Key: "fetcher:alphavantage:AAPL", Value: 100}},
+	}
+
+	if err := WriteReport(path, summary); err != nil {
+		t.Fatalf("WriteReport() returned unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+
+	// An empty errors list is written as [] rather than null
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if string(raw["errors"]) != "[]" {
+		t.Errorf("errors = %s, want []", raw["errors"])
+	}
+}
+
+func TestWriteReport_MissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "report.json")
+	if err := WriteReport(path, coordinator.RunSummary{}); err == nil {
+		t.Error("WriteReport() into a missing directory should return an error")
+	}
+}
@@ -0,0 +1,137 @@
+// Package sqlitestore persists fetch results to a local SQLite file for long-term
+// history and charting without running Redis.
+package sqlitestore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"financefetcher/internal/registry"
+
+	// Registers the pure-Go "sqlite" database/sql driver, so builds don't need cgo
+	_ "modernc.org/sqlite"
+)
+
+// migrations are applied in order on Open; PRAGMA user_version records how many have run.
+// Append new statements to change the schema; never edit ones that have shipped.
+var migrations = []string{
+	`CREATE TABLE results (
+		key        TEXT    NOT NULL,
+		value      REAL    NOT NULL,
+		fetched_at INTEGER NOT NULL -- Unix nanoseconds
+	);
+	CREATE INDEX results_key_fetched_at ON results (key, fetched_at);`,
+}
+
+// Store records fetch results as (key, value, fetched_at) rows in a SQLite database.
+// It satisfies coordinator.Storer and coordinator.FallbackStore, and fetcher.ValueStore.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (or creates) the SQLite database at path and migrates it to the current schema
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database %s: %w", path, err)
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate history database %s: %w", path, err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// migrate applies any migrations the database hasn't seen yet, each in its own transaction
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		// PRAGMA doesn't accept bound parameters
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Insert records value for key as fetched at ts
+func (s *Store) Insert(key string, value float64, ts time.Time) error {
+	_, err := s.db.Exec("INSERT INTO results (key, value, fetched_at) VALUES (?, ?, ?)", key, value, ts.UnixNano())
+	return err
+}
+
+// Set records value for key as fetched at ts, logging rather than returning any error so
+// a full disk never fails a fetch run
+func (s *Store) Set(key string, value float64, ts time.Time) {
+	if err := s.Insert(key, value, ts); err != nil {
+		slog.Error("failed to record result in history database", "key", key, "error", err)
+	}
+}
+
+// Get returns the most recent value recorded for key
+func (s *Store) Get(key string) (registry.Entry, bool) {
+	var value float64
+	var fetchedAt int64
+
+	err := s.db.QueryRow(
+		"SELECT value, fetched_at FROM results WHERE key = ? ORDER BY fetched_at DESC LIMIT 1", key,
+	).Scan(&value, &fetchedAt)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("failed to read latest result from history database", "key", key, "error", err)
+		}
+		return registry.Entry{}, false
+	}
+
+	return registry.Entry{Value: value, UpdatedAt: time.Unix(0, fetchedAt)}, true
+}
+
+// History returns every value recorded for key at or after since, oldest first
+func (s *Store) History(key string, since time.Time) ([]registry.Entry, error) {
+	rows, err := s.db.Query(
+		"SELECT value, fetched_at FROM results WHERE key = ? AND fetched_at >= ? ORDER BY fetched_at", key, since.UnixNano(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history for %s: %w", key, err)
+	}
+	defer rows.Close()
+
+	var history []registry.Entry
+	for rows.Next() {
+		var value float64
+		var fetchedAt int64
+		if err := rows.Scan(&value, &fetchedAt); err != nil {
+			return nil, fmt.Errorf("failed to read history for %s: %w", key, err)
+		}
+		history = append(history, registry.Entry{Value: value, UpdatedAt: time.Unix(0, fetchedAt)})
+	}
+
+	return history, rows.Err()
+}
@@ -0,0 +1,65 @@
+package sqlitestore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_HistoryAndLatest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, value := range []float64{100, 101.5, 99.25} {
+		if err := store.Insert("fetcher:alphavantage:AAPL", value, base.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("Insert() returned unexpected error: %v", err)
+		}
+	}
+	store.Set("fetcher:alphavantage:MSFT", 400, base)
+
+	history, err := store.History("fetcher:alphavantage:AAPL", base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("History() returned unexpected error: %v", err)
+	}
+	if len(history) != 2 || history[0].Value != 101.5 || history[1].Value != 99.25 {
+		t.Fatalf("History() = %+v, want the last two AAPL values oldest first", history)
+	}
+	if !history[1].UpdatedAt.Equal(base.Add(2 * time.Hour)) {
+		t.Errorf("History()[1].UpdatedAt = %v, want %v", history[1].UpdatedAt, base.Add(2*time.Hour))
+	}
+
+	latest, ok := store.Get("fetcher:alphavantage:AAPL")
+	if !ok || latest.Value != 99.25 {
+		t.Errorf("Get() = %+v, %v, want latest value 99.25", latest, ok)
+	}
+	if _, ok := store.Get("fetcher:alphavantage:GOOG"); ok {
+		t.Error("Get() found a value for a key that was never recorded")
+	}
+}
+
+func TestOpen_ReopensExistingDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned unexpected error: %v", err)
+	}
+	store.Set("fetcher:alphavantage:AAPL", 100, time.Now())
+	store.Close()
+
+	// Migrations already applied must not run again
+	store, err = Open(path)
+	if err != nil {
+		t.Fatalf("second Open() returned unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	if entry, ok := store.Get("fetcher:alphavantage:AAPL"); !ok || entry.Value != 100 {
+		t.Errorf("Get() after reopen = %+v, %v, want 100", entry, ok)
+	}
+}
@@ -0,0 +1,34 @@
+// Package stock builds stock price fetchers without callers needing to know
+// which provider serves the quotes.
+package stock
+
+import (
+	"fmt"
+
+	"financefetcher/internal/alphavantage"
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/finnhub"
+)
+
+// Provider identifies a stock price provider
+type Provider string
+
+const (
+	// ProviderAlphaVantage fetches quotes from AlphaVantage's GLOBAL_QUOTE endpoint
+	ProviderAlphaVantage Provider = "alphavantage"
+	// ProviderFinnhub fetches quotes from Finnhub's /quote endpoint
+	ProviderFinnhub Provider = "finnhub"
+)
+
+// NewFetcher creates a stock price fetcher for symbol backed by the given provider.
+// It returns an error for an unknown provider.
+func NewFetcher(provider Provider, apiKey, symbol, baseURL string) (fetcher.Fetcher, error) {
+	switch provider {
+	case ProviderAlphaVantage:
+		return alphavantage.NewStockFetcher(apiKey, symbol, baseURL), nil
+	case ProviderFinnhub:
+		return finnhub.NewStockFetcher(apiKey, symbol, baseURL), nil
+	default:
+		return nil, fmt.Errorf("unknown stock provider %q", provider)
+	}
+}
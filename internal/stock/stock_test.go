@@ -0,0 +1,48 @@
+package stock
+
+import (
+	"testing"
+
+	"financefetcher/internal/alphavantage"
+	"financefetcher/internal/finnhub"
+)
+
+func TestNewFetcher(t *testing.T) {
+	tests := []struct {
+		provider Provider
+		wantKey  string
+	}{
+		{ProviderAlphaVantage, "fetcher:alphavantage:AAPL"},
+		{ProviderFinnhub, "fetcher:finnhub:AAPL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.provider), func(t *testing.T) {
+			f, err := NewFetcher(tt.provider, "test_key", "AAPL", "http://localhost")
+			if err != nil {
+				t.Fatalf("NewFetcher() returned unexpected error: %v", err)
+			}
+
+			switch tt.provider {
+			case ProviderAlphaVantage:
+				if _, ok := f.(*alphavantage.StockFetcher); !ok {
+					t.Errorf("NewFetcher() = %T, want *alphavantage.StockFetcher", f)
+				}
+			case ProviderFinnhub:
+				if _, ok := f.(*finnhub.StockFetcher); !ok {
+					t.Errorf("NewFetcher() = %T, want *finnhub.StockFetcher", f)
+				}
+			}
+
+			if got := f.Key(); got != tt.wantKey {
+				t.Errorf("Key() = %q, want %q", got, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestNewFetcher_UnknownProvider(t *testing.T) {
+	if _, err := NewFetcher("polygon", "test_key", "AAPL", "http://localhost"); err == nil {
+		t.Error("NewFetcher() expected error for unknown provider, got nil")
+	}
+}
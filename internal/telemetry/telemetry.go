@@ -0,0 +1,80 @@
+// Package telemetry exports fetches to OpenTelemetry. It adapts an OTel tracer and meter
+// provider to coordinator.Telemetry, so only programs that want traces and metrics link
+// the OTel API.
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+
+	"financefetcher/internal/fetcher"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationName identifies this package's spans and metrics
+const instrumentationName = "financefetcher/internal/telemetry"
+
+// OTel wraps each fetch in a span and records fetched values as a gauge
+type OTel struct {
+	tracer trace.Tracer
+	values metric.Float64Gauge
+}
+
+// New builds OTel telemetry from the given providers: a "fetch" span around each fetch
+// with the fetcher's key, source, and outcome, and a financefetcher.fetch.value gauge for
+// each fetched value. A nil provider disables that signal.
+func New(tp trace.TracerProvider, mp metric.MeterProvider) *OTel {
+	if tp == nil {
+		tp = tracenoop.NewTracerProvider()
+	}
+	if mp == nil {
+		mp = metricnoop.NewMeterProvider()
+	}
+
+	values, err := mp.Meter(instrumentationName).Float64Gauge("financefetcher.fetch.value",
+		metric.WithDescription("Most recently fetched value for each fetcher key"))
+	if err != nil {
+		slog.Warn("failed to create fetch value gauge, metrics disabled", "error", err)
+		values = metricnoop.Float64Gauge{}
+	}
+
+	return &OTel{
+		tracer: tp.Tracer(instrumentationName),
+		values: values,
+	}
+}
+
+// StartFetch implements coordinator.Telemetry. It starts a span carrying the fetcher's
+// key and source; the returned function ends it with the outcome and records a
+// successful value on the gauge.
+func (t *OTel) StartFetch(ctx context.Context, key string) (context.Context, func(value float64, err error)) {
+	attrs := []attribute.KeyValue{
+		attribute.String("fetcher.key", key),
+		attribute.String("fetcher.source", fetcher.SourceFromKey(key)),
+	}
+
+	ctx, span := t.tracer.Start(ctx, "fetch", trace.WithAttributes(attrs...))
+
+	return ctx, func(value float64, err error) {
+		defer span.End()
+
+		if err != nil {
+			span.SetAttributes(
+				attribute.String("fetcher.outcome", "error"),
+				attribute.String("fetcher.error_type", string(fetcher.ErrorTypeOf(err))),
+			)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return
+		}
+
+		span.SetAttributes(attribute.String("fetcher.outcome", "success"))
+		t.values.Record(ctx, value, metric.WithAttributes(attrs...))
+	}
+}
@@ -0,0 +1,145 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"financefetcher/internal/coordinator"
+	"financefetcher/internal/fetcher"
+	"financefetcher/internal/testutil"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordedSpan captures what the coordinator set on a span
+type recordedSpan struct {
+	attrs  map[string]string
+	status codes.Code
+	ended  bool
+}
+
+// recordingTracerProvider hands out spans that record their attributes and status
+type recordingTracerProvider struct {
+	tracenoop.TracerProvider
+
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (p *recordingTracerProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return recordingTracer{provider: p}
+}
+
+type recordingTracer struct {
+	tracenoop.Tracer
+	provider *recordingTracerProvider
+}
+
+func (t recordingTracer) Start(ctx context.Context, _ string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordingSpan{rec: &recordedSpan{attrs: make(map[string]string)}}
+	config := trace.NewSpanStartConfig(opts...)
+	span.SetAttributes(config.Attributes()...)
+
+	t.provider.mu.Lock()
+	t.provider.spans = append(t.provider.spans, span.rec)
+	t.provider.mu.Unlock()
+
+	return ctx, span
+}
+
+type recordingSpan struct {
+	tracenoop.Span
+	rec *recordedSpan
+}
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	for _, attr := range kv {
+		s.rec.attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+}
+func (s *recordingSpan) SetStatus(code codes.Code, _ string) { s.rec.status = code }
+func (s *recordingSpan) End(...trace.SpanEndOption)          { s.rec.ended = true }
+
+// recordingMeterProvider hands out a gauge that records values by key
+type recordingMeterProvider struct {
+	metricnoop.MeterProvider
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (p *recordingMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return recordingMeter{provider: p}
+}
+
+type recordingMeter struct {
+	metricnoop.Meter
+	provider *recordingMeterProvider
+}
+
+func (m recordingMeter) Float64Gauge(string, ...metric.Float64GaugeOption) (metric.Float64Gauge, error) {
+	return recordingGauge{provider: m.provider}, nil
+}
+
+type recordingGauge struct {
+	metricnoop.Float64Gauge
+	provider *recordingMeterProvider
+}
+
+func (g recordingGauge) Record(_ context.Context, value float64, opts ...metric.RecordOption) {
+	config := metric.NewRecordConfig(opts)
+	attrs := config.Attributes()
+	key, _ := attrs.Value("fetcher.key")
+
+	g.provider.mu.Lock()
+	g.provider.values[key.AsString()] = value
+	g.provider.mu.Unlock()
+}
+
+func TestOTel_Run(t *testing.T) {
+	tp := &recordingTracerProvider{}
+	mp := &recordingMeterProvider{values: make(map[string]float64)}
+
+	coord := coordinator.New([]fetcher.Fetcher{
+		testutil.NewMockFetcher("fetcher:alphavantage:AAPL", 178.5, nil),
+		testutil.NewMockFetcher("fetcher:etherscan:0xabc", 0, fetcher.NewServerError(503)),
+	})
+	coord.SetOutput(io.Discard)
+	coord.SetTelemetry(New(tp, mp))
+
+	if err := coord.Run(context.Background()); err != nil {
+		t.Fatalf("Run() returned unexpected error: %v", err)
+	}
+
+	spans := make(map[string]*recordedSpan)
+	for _, span := range tp.spans {
+		spans[span.attrs["fetcher.key"]] = span
+	}
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(tp.spans))
+	}
+
+	ok := spans["fetcher:alphavantage:AAPL"]
+	if ok.attrs["fetcher.source"] != "alphavantage" || ok.attrs["fetcher.outcome"] != "success" || !ok.ended {
+		t.Errorf("success span = %+v, want ended alphavantage success", ok)
+	}
+
+	failed := spans["fetcher:etherscan:0xabc"]
+	if failed.attrs["fetcher.outcome"] != "error" || failed.attrs["fetcher.error_type"] != "server" || failed.status != codes.Error {
+		t.Errorf("error span = %+v, want server error status", failed)
+	}
+
+	if got := mp.values["fetcher:alphavantage:AAPL"]; got != 178.5 {
+		t.Errorf("gauge value = %v, want 178.5", got)
+	}
+	if _, recorded := mp.values["fetcher:etherscan:0xabc"]; recorded {
+		t.Error("gauge recorded a value for a failed fetch")
+	}
+}
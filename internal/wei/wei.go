@@ -0,0 +1,27 @@
+// Package wei converts Ether amounts in wei, the smallest unit, to USD.
+package wei
+
+import (
+	"math"
+	"math/big"
+)
+
+var (
+	perEth     = big.NewInt(1e18)
+	halfPerEth = big.NewInt(5e17)
+)
+
+// ToUSD returns the USD value of wei at the given ETH/USD price, exact to the cent.
+// Prices are quoted with two decimals, so the price is taken in whole cents and
+// (wei * priceCents) / 10^18 is computed with big.Int arithmetic, rounding half up.
+// Only the final cent amount is converted to float64.
+func ToUSD(wei *big.Int, ethUSD float64) float64 {
+	priceCents := big.NewInt(int64(math.Round(ethUSD * 100)))
+
+	cents := new(big.Int).Mul(wei, priceCents)
+	cents.Add(cents, halfPerEth)
+	cents.Quo(cents, perEth)
+
+	centsFloat, _ := new(big.Float).SetInt(cents).Float64()
+	return centsFloat / 100
+}
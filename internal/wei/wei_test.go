@@ -0,0 +1,34 @@
+package wei
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestToUSD(t *testing.T) {
+	tests := []struct {
+		name   string
+		wei    string
+		ethUSD float64
+		want   float64
+	}{
+		{"one ETH", "1000000000000000000", 2000, 2000.00},
+		{"zero", "0", 2000, 0},
+		// 0.0078125 ETH at $1234.56 is exactly $9.645, which a float64 product rounds down
+		{"rounds half up", "7812500000000000", 1234.56, 9.65},
+		{"below half a cent", "2000000000000", 2000, 0},
+		{"larger than float64 mantissa", "123456789012345678901234", 1, 123456.79},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, ok := new(big.Int).SetString(tt.wei, 10)
+			if !ok {
+				t.Fatalf("invalid test amount %q", tt.wei)
+			}
+			if got := ToUSD(amount, tt.ethUSD); got != tt.want {
+				t.Errorf("ToUSD(%s, %v) = %v, want %v", tt.wei, tt.ethUSD, got, tt.want)
+			}
+		})
+	}
+}
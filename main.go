@@ -2,28 +2,66 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"financefetcher/internal/alphavantage"
+	"financefetcher/internal/coinbase"
 	"financefetcher/internal/config"
 	"financefetcher/internal/coordinator"
 	"financefetcher/internal/etherscan"
+	"financefetcher/internal/ethrpc"
 	"financefetcher/internal/fetcher"
+	"financefetcher/internal/httpserver"
+	"financefetcher/internal/portfolio"
+	"financefetcher/internal/ratelimit"
+	"financefetcher/internal/registry"
 	"financefetcher/internal/rentcast"
+	"financefetcher/internal/report"
+	"financefetcher/internal/sqlitestore"
+	"financefetcher/internal/stock"
+)
+
+const (
+	// shutdownTimeout bounds how long the HTTP server waits for in-flight requests
+	shutdownTimeout = 5 * time.Second
 )
 
 func main() {
+	httpAddr := flag.String("http", "", "serve the latest results over HTTP on this address (e.g. :8080)")
+	interval := flag.Duration("interval", 0, "fetch repeatedly on this interval (e.g. 5m) instead of once")
+	reportPath := flag.String("report", "", "write a JSON report of the run (results, total, errors) to this path")
+	golden := flag.Bool("golden", false, "print only a sorted key=value snapshot of the results, for diffing against a golden file")
+	flag.Parse()
+
+	// A report describes a single run, so there is nothing to write on an interval
+	if *reportPath != "" && *interval > 0 {
+		log.Fatalf("-report can't be combined with -interval")
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Apply the configured AlphaVantage quota (premium keys allow far more than the free tier)
+	ratelimit.GetLimiter().Configure(ratelimit.APIAlphaVantage, cfg.AlphavantageRatePerMin, cfg.AlphavantageBurst)
+
+	// Share the ETH price across wallet fetchers
+	etherscan.SetPriceCacheTTL(cfg.EthPriceCacheTTL)
+
+	// Log each HTTP request (API keys redacted); the lines are at debug level, so lower the log level too
+	if cfg.DebugHTTP {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		fetcher.SetDebugHTTP(true)
+	}
+
 	// Create context with cancellation for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -40,26 +78,49 @@ func main() {
 	// Create fetchers dynamically from configuration
 	var fetchers []fetcher.Fetcher
 
-	// Create Ethereum wallet fetchers
+	// Create Ethereum wallet fetchers using the configured provider.
+	// The RPC provider still prices balances with Etherscan's cached ETH price.
+	ethPrice := etherscan.NewPriceFetcher(cfg.EtherscanAPIKey, cfg.EtherscanBaseURL)
 	for _, wallet := range cfg.EthereumWallets {
+		if cfg.IsDisabled(wallet) {
+			slog.Info("skipping disabled wallet", "address", wallet)
+			continue
+		}
+		if cfg.EthProvider == config.EthProviderRPC {
+			fetchers = append(fetchers, ethrpc.NewWalletFetcher(cfg.EthRPCURL, wallet, ethPrice))
+			continue
+		}
 		fetchers = append(fetchers, etherscan.NewWalletFetcher(
 			cfg.EtherscanAPIKey,
 			wallet,
 			cfg.EtherscanBaseURL,
+			etherscan.WithGasReserve(cfg.EthGasReserve),
 		))
 	}
 
-	// Create stock fetchers
+	// Create stock fetchers using the configured provider
+	stockAPIKey, stockBaseURL := cfg.AlphavantageAPIKey, cfg.AlphavantageBaseURL
+	if cfg.StockProvider == config.StockProviderFinnhub {
+		stockAPIKey, stockBaseURL = cfg.FinnhubAPIKey, cfg.FinnhubBaseURL
+	}
 	for _, symbol := range cfg.StockSymbols {
-		fetchers = append(fetchers, alphavantage.NewStockFetcher(
-			cfg.AlphavantageAPIKey,
-			symbol,
-			cfg.AlphavantageBaseURL,
-		))
+		if cfg.IsDisabled(symbol) {
+			slog.Info("skipping disabled stock symbol", "symbol", symbol)
+			continue
+		}
+		f, err := stock.NewFetcher(stock.Provider(cfg.StockProvider), stockAPIKey, symbol, stockBaseURL)
+		if err != nil {
+			log.Fatalf("Failed to create stock fetcher: %v", err)
+		}
+		fetchers = append(fetchers, f)
 	}
 
 	// Create property fetchers
 	for _, prop := range cfg.Properties {
+		if !prop.IsEnabled() {
+			slog.Info("skipping disabled property", "address", prop.Address)
+			continue
+		}
 		fetchers = append(fetchers, rentcast.NewPropertyFetcher(
 			cfg.RentcastAPIKey,
 			rentcast.PropertyParams{
@@ -73,20 +134,116 @@ func main() {
 		))
 	}
 
-	// Create coordinator
-	coord := coordinator.New(fetchers)
+	// Create the Coinbase balance fetcher when credentials are configured
+	if cfg.CoinbaseEnabled() {
+		if cfg.IsDisabled(cfg.CoinbaseAccount) {
+			slog.Info("skipping disabled Coinbase account", "account", cfg.CoinbaseAccount)
+		} else {
+			fetchers = append(fetchers, coinbase.NewBalanceFetcher(
+				cfg.CoinbaseAPIKey,
+				cfg.CoinbaseAPISecret,
+				cfg.CoinbaseAccount,
+				cfg.BaseURL("coinbase"),
+			))
+		}
+	}
+
+	// Create coordinator, recording results in a registry that the HTTP server can read
+	results := registry.New()
+	var coordOpts []coordinator.Option
+	if cfg.DedupKeys {
+		coordOpts = append(coordOpts, coordinator.WithDedupKeys())
+	}
+	coord := coordinator.New(fetchers, coordOpts...)
+	coord.SetRegistry(results)
+	coord.SetFallbackStore(results)
+	coord.SetRetryBudget(cfg.RetryBudget)
+	coord.SetLabels(cfg.Labels)
+
+	// Optionally keep a history of every successful fetch in SQLite
+	if cfg.HistoryDB != "" {
+		history, err := sqlitestore.Open(cfg.HistoryDB)
+		if err != nil {
+			log.Fatalf("Failed to open history database: %v", err)
+		}
+		defer history.Close()
+		coord.SetStorer(history)
+	}
 
-	// Add timeout to prevent hanging indefinitely
-	fetchCtx, fetchCancel := context.WithTimeout(ctx, 30*time.Second)
-	defer fetchCancel()
+	// Format values using the configured locale and rounding mode
+	locale, err := coordinator.LookupLocale(cfg.OutputLocale)
+	if err != nil {
+		log.Fatalf("Invalid OUTPUT_LOCALE: %v", err)
+	}
+	rounding, err := coordinator.ParseRoundingMode(cfg.OutputRounding)
+	if err != nil {
+		log.Fatalf("Invalid OUTPUT_ROUNDING: %v", err)
+	}
+	coord.SetFormatter(coordinator.TextFormatter{CurrencySymbol: "$", Locale: locale, Rounding: rounding})
+	coord.SetGroupErrors(true)
+	if *golden {
+		coord.SetFormatter(coordinator.GoldenFormatter{})
+	}
 
-	// Run all fetchers concurrently
-	fmt.Println("Fetching financial data from multiple sources...")
-	fmt.Println("================================================")
-	if err := coord.Run(fetchCtx); err != nil {
-		log.Fatalf("Coordinator failed: %v", err)
+	// Optionally serve the latest results over HTTP
+	var server *httpserver.Server
+	if *httpAddr != "" {
+		server = httpserver.New(*httpAddr, results)
+		if err := server.Start(); err != nil {
+			log.Fatalf("Failed to start HTTP server: %v", err)
+		}
 	}
 
-	fmt.Println("================================================")
-	fmt.Println("All fetches completed!")
+	// Fetch repeatedly until interrupted when an interval is set
+	if *interval > 0 {
+		fmt.Printf("Fetching financial data every %v (Ctrl+C to stop)...\n", *interval)
+		coord.SetCycleTimeout(cfg.RunTimeout)
+		if err := coord.RunEvery(ctx, *interval); err != nil {
+			log.Fatalf("Coordinator failed: %v", err)
+		}
+	} else {
+		// Add timeout to prevent hanging indefinitely
+		fetchCtx, fetchCancel := context.WithTimeout(ctx, cfg.RunTimeout)
+		defer fetchCancel()
+
+		// Run all fetchers concurrently; a golden snapshot is printed without the banners
+		if !*golden {
+			fmt.Println("Fetching financial data from multiple sources...")
+			fmt.Println("================================================")
+		}
+		summary, err := coord.RunWithSummary(fetchCtx)
+		if err != nil {
+			log.Fatalf("Coordinator failed: %v", err)
+		}
+
+		// Flag holdings that have grown past the configured share of the portfolio
+		if cfg.MaxAllocationPct > 0 {
+			portfolio.WarnConcentrations(summary.Results, cfg.MaxAllocationPct)
+		}
+
+		// Archive the run as a JSON snapshot
+		if *reportPath != "" {
+			if err := report.WriteReport(*reportPath, summary); err != nil {
+				log.Fatalf("Failed to write report: %v", err)
+			}
+			fmt.Printf("Report written to %s\n", *reportPath)
+		}
+
+		if !*golden {
+			fmt.Println("================================================")
+			fmt.Println("All fetches completed!")
+		}
+	}
+
+	// Keep serving results until interrupted, then shut down gracefully
+	if server != nil {
+		<-ctx.Done()
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer shutdownCancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("HTTP server shutdown failed: %v", err)
+		}
+	}
 }